@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE IF NOT EXISTS clicks
+(   id          BIGSERIAL    PRIMARY KEY,
+    short       VARCHAR      NOT NULL REFERENCES urls (short) ON DELETE CASCADE,
+    clicked_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    referrer    VARCHAR      NOT NULL DEFAULT '',
+    user_agent  VARCHAR      NOT NULL DEFAULT '',
+    ip          VARCHAR      NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS clicks_short_idx1 ON clicks (short, clicked_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS clicks;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE IF NOT EXISTS url_revisions
+(   id          BIGSERIAL    PRIMARY KEY,
+    short       VARCHAR      NOT NULL REFERENCES urls (short) ON DELETE CASCADE,
+    old_long    VARCHAR      NOT NULL,
+    new_long    VARCHAR      NOT NULL,
+    userID      VARCHAR      NOT NULL,
+    changed_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS url_revisions_short_idx1 ON url_revisions (short);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS url_revisions;
+-- +goose StatementEnd
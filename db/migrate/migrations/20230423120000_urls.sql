@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+-- срок восстановления удаленных ранее ссылок отсчитывается от миграции
+UPDATE urls SET deleted_at = now() WHERE deleted AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS deleted_at_idx1 ON urls (deleted_at) WHERE deleted;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS deleted_at_idx1;
+ALTER TABLE urls DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE IF NOT EXISTS pending_deletes
+(   id          BIGSERIAL    PRIMARY KEY,
+    userID      VARCHAR      NOT NULL,
+    shorts      VARCHAR[]    NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS pending_deletes;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE pending_deletes ADD COLUMN IF NOT EXISTS status VARCHAR NOT NULL DEFAULT 'queued';
+ALTER TABLE pending_deletes ADD COLUMN IF NOT EXISTS deleted VARCHAR[] NOT NULL DEFAULT '{}';
+ALTER TABLE pending_deletes ADD COLUMN IF NOT EXISTS skipped VARCHAR[] NOT NULL DEFAULT '{}';
+ALTER TABLE pending_deletes ADD COLUMN IF NOT EXISTS error VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE pending_deletes ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS pending_deletes_status_idx ON pending_deletes (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS pending_deletes_status_idx;
+ALTER TABLE pending_deletes DROP COLUMN IF EXISTS status;
+ALTER TABLE pending_deletes DROP COLUMN IF EXISTS deleted;
+ALTER TABLE pending_deletes DROP COLUMN IF EXISTS skipped;
+ALTER TABLE pending_deletes DROP COLUMN IF EXISTS error;
+ALTER TABLE pending_deletes DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- у существующих ссылок время создания неизвестно и остается NULL
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+ALTER TABLE urls ALTER COLUMN created_at SET DEFAULT now();
+CREATE INDEX IF NOT EXISTS userid_short_idx1 ON urls (userID, short);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS userid_short_idx1;
+ALTER TABLE urls DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd
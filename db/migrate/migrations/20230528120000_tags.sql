@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE IF NOT EXISTS tags
+(   userID      VARCHAR      NOT NULL,
+    name        VARCHAR      NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (userID, name)
+);
+CREATE TABLE IF NOT EXISTS url_tags
+(   short       VARCHAR      NOT NULL REFERENCES urls (short) ON DELETE CASCADE,
+    userID      VARCHAR      NOT NULL,
+    tag         VARCHAR      NOT NULL,
+    position    INT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (short, tag),
+    FOREIGN KEY (userID, tag) REFERENCES tags (userID, name) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS url_tags_tag_idx1 ON url_tags (userID, tag);
+CREATE TABLE IF NOT EXISTS folders
+(   userID      VARCHAR      NOT NULL,
+    name        VARCHAR      NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (userID, name)
+);
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS folder VARCHAR;
+ALTER TABLE urls ADD CONSTRAINT urls_folder_fkey FOREIGN KEY (userID, folder) REFERENCES folders (userID, name);
+-- метки из колонки urls.tags переносятся в таблицы
+INSERT INTO tags (userID, name, created_at)
+SELECT userID, tag, min(COALESCE(created_at, now())) FROM urls, unnest(tags) AS tag GROUP BY userID, tag;
+INSERT INTO url_tags (short, userID, tag, position)
+SELECT short, userID, t.tag, t.position FROM urls, unnest(tags) WITH ORDINALITY AS t(tag, position);
+ALTER TABLE urls DROP COLUMN IF EXISTS tags;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS tags VARCHAR[] NOT NULL DEFAULT '{}';
+UPDATE urls SET tags = ARRAY(SELECT tag FROM url_tags WHERE url_tags.short = urls.short ORDER BY position);
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS urls_folder_fkey;
+ALTER TABLE urls DROP COLUMN IF EXISTS folder;
+DROP TABLE IF EXISTS url_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS folders;
+-- +goose StatementEnd
@@ -3,6 +3,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"go.uber.org/zap"
 	"log"
 	"net"
@@ -10,36 +11,64 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/Spear5030/yapshrtnr/db/migrate"
 	"github.com/Spear5030/yapshrtnr/internal/config"
 	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/geoip"
 	grpcS "github.com/Spear5030/yapshrtnr/internal/grpc/server"
 	"github.com/Spear5030/yapshrtnr/internal/handler"
+	"github.com/Spear5030/yapshrtnr/internal/module"
 	"github.com/Spear5030/yapshrtnr/internal/router"
 	"github.com/Spear5030/yapshrtnr/internal/storage"
 	"github.com/Spear5030/yapshrtnr/pkg/logger"
 )
 
+// shutdownTimeout сколько HTTP сервер при остановке ждет завершения запросов
+const shutdownTimeout = 30 * time.Second
+
 // App основная структура приложения. HTTP сервер и логгер
 type App struct {
 	HTTPServer *http.Server
 	GRPCServer *grpcS.GRPCServer
 	logger     *zap.Logger
 	tls        bool
+	stopped    chan struct{} // закрывается после остановки хранилища
 }
 
 // New возвращает App
 func New(cfg config.Config) (*App, error) {
 
 	var storager interface {
-		SetURL(ctx context.Context, user, short, long string) error
-		GetURL(ctx context.Context, short string) (string, bool)
-		GetURLsByUser(ctx context.Context, user string) (urls map[string]string)
+		SetURL(ctx context.Context, url domain.URL) error
+		GetURL(ctx context.Context, short string) (domain.URL, error)
 		SetBatchURLs(ctx context.Context, urls []domain.URL) error
-		DeleteURLs(ctx context.Context, user string, shorts []string)
+		DeleteURLs(ctx context.Context, user string, shorts []string) (string, error)
+		GetDeleteJob(ctx context.Context, id string) (domain.DeleteJob, error)
+		ListURLs(ctx context.Context, user string, query domain.ListQuery) ([]domain.LinkInfo, error)
 		GetUsersCount(ctx context.Context) (int, error)
 		GetUrlsCount(ctx context.Context) (int, error)
+		RecordClick(ctx context.Context, click domain.Click)
+		GetClickStats(ctx context.Context, short string) (domain.ClickStats, error)
+		UpdateURL(ctx context.Context, user, short, long string) (domain.URL, error)
+		GetURLRevisions(ctx context.Context, short string) ([]domain.Revision, error)
+		RestoreURL(ctx context.Context, user, short string, deletedAfter time.Time) (domain.URL, error)
+		PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
+		DeleteExpired(ctx context.Context) (int, error)
+		ListTags(ctx context.Context, user string) ([]domain.Tag, error)
+		CreateTag(ctx context.Context, user, name string) (domain.Tag, error)
+		RenameTag(ctx context.Context, user, name, newName string) (domain.Tag, error)
+		DeleteTag(ctx context.Context, user, name string) error
+		ListFolders(ctx context.Context, user string) ([]domain.Folder, error)
+		DeleteFolder(ctx context.Context, user, name string) error
+		SetURLTags(ctx context.Context, user, short string, tags []string) (domain.URL, error)
+		SetURLFolder(ctx context.Context, user, short, folder string) (domain.URL, error)
+		SetURLPreview(ctx context.Context, user, short string, preview bool) (domain.URL, error)
+		SetURLPassword(ctx context.Context, user, short, hash string) (domain.URL, error)
+		SetURLRules(ctx context.Context, user, short string, rules []domain.RedirectRule) (domain.URL, error)
+		SetURLVariants(ctx context.Context, user, short string, variants []domain.Variant) (domain.URL, error)
+		UseURL(ctx context.Context, short string) (domain.URL, error)
 		Shutdown() error // возможно стоит вынести в отдельный интерфейс БД - ping и shutdown, т.к оба реализуются только для Postgre
 	} // также при усложнении стоит добавить context. но при текущей реализации imho избыточно
 
@@ -60,7 +89,7 @@ func New(cfg config.Config) (*App, error) {
 		storager = pgStorage
 		lg.Info("PostgreSQL storage.", zap.String("config", cfg.Database))
 	} else if len(cfg.FileStorage) > 0 {
-		fileStorage, err := storage.NewFileStorage(cfg.FileStorage)
+		fileStorage, err := storage.NewFileStorage(cfg.FileStorage, cfg.CompactSize, cfg.SnapshotInterval)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -70,43 +99,102 @@ func New(cfg config.Config) (*App, error) {
 		storager = memoryStorage
 		lg.Info("Inmemory storage.")
 	}
-	h := handler.New(lg, storager, cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	gen, err := module.NewGenerator(cfg.ShortIDStrategy)
+	if err != nil {
+		return nil, err
+	}
+	var geo *geoip.DB
+	if len(cfg.GeoIPDatabase) > 0 {
+		if geo, err = geoip.Open(cfg.GeoIPDatabase); err != nil {
+			return nil, err
+		}
+		lg.Info("GeoIP database.", zap.String("path", cfg.GeoIPDatabase))
+	}
+	h := handler.New(lg, storager, cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), gen, cfg.DeleteGracePeriod, geo)
 	r := router.New(h)
 	srv := &http.Server{
 		Addr:    cfg.Addr,
 		Handler: r,
 	}
 
-	grpcSrv := grpcS.New(storager, lg, cfg.GRPCPort, cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	grpcSrv := grpcS.New(storager, lg, cfg.GRPCPort, cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), gen, cfg.DeleteGracePeriod)
 
+	sweepCtx, stopSweep := context.WithCancel(context.Background())
+	if cfg.SweepInterval > 0 {
+		go sweep(sweepCtx, storager, cfg.SweepInterval, cfg.DeleteGracePeriod, lg)
+	}
+
+	stopped := make(chan struct{})
 	sigint := make(chan os.Signal, 1)
 	signal.Notify(sigint, os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	go func() {
 		<-sigint
 		lg.Info("Will gracefully shutdown")
+		defer close(stopped)
+		stopSweep()
+		// сначала перестаем принимать запросы и дожидаемся текущих, иначе они попадут в закрытое хранилище
 		grpcSrv.Server.GracefulStop()
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			lg.Info("HTTP server Shutdown:", zap.Error(err))
+		}
 		if err := storager.Shutdown(); err != nil {
 			lg.Info("Storage Shutdown:", zap.Error(err))
 		}
-		if err := srv.Shutdown(context.Background()); err != nil {
-			lg.Info("HTTP server Shutdown:", zap.Error(err))
-		}
 	}()
 	return &App{
 		HTTPServer: srv,
 		GRPCServer: grpcSrv,
 		logger:     lg,
 		tls:        cfg.HTTPS,
+		stopped:    stopped,
 	}, nil
 }
 
-// Run запуск приложения.
+// sweep раз в interval удаляет из хранилища ссылки с истекшим сроком действия и ссылки,
+// удаленные раньше gracePeriod назад, до отмены ctx
+func sweep(ctx context.Context, storage interface {
+	DeleteExpired(ctx context.Context) (int, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
+}, interval time.Duration, gracePeriod time.Duration, lg *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := storage.DeleteExpired(ctx)
+			if err != nil {
+				lg.Error("DeleteExpired error", zap.Error(err))
+			} else if count > 0 {
+				lg.Info("Expired urls deleted", zap.Int("count", count))
+			}
+			count, err = storage.PurgeDeleted(ctx, time.Now().Add(-gracePeriod))
+			if err != nil {
+				lg.Error("PurgeDeleted error", zap.Error(err))
+			} else if count > 0 {
+				lg.Info("Deleted urls purged", zap.Int("count", count))
+			}
+		}
+	}
+}
+
+// Run запуск приложения. После сигнала остановки возвращается, когда остановлено и хранилище
 func (app *App) Run() error {
 	app.GRPCServer.Start()
+	var err error
 	if app.tls {
 		app.logger.Info("Listen with TLS " + app.HTTPServer.Addr)
-		return app.HTTPServer.ListenAndServeTLS("cert/cert.pem", "cert/private.key")
+		err = app.HTTPServer.ListenAndServeTLS("cert/cert.pem", "cert/private.key")
+	} else {
+		app.logger.Info("Listen without TLS " + app.HTTPServer.Addr)
+		err = app.HTTPServer.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		// ListenAndServe возвращается сразу после вызова Shutdown, не дожидаясь запросов и хранилища
+		<-app.stopped
 	}
-	app.logger.Info("Listen without TLS " + app.HTTPServer.Addr)
-	return app.HTTPServer.ListenAndServe()
+	return err
 }
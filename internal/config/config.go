@@ -8,12 +8,18 @@ import (
 	"log"
 	"net"
 	"os"
+	"time"
 )
 
 const (
 	defaultAddr     = "localhost:8080"
 	defaultBaseURL  = "http://localhost:8080"
 	defaultGRPCPort = "3200"
+
+	defaultCompactSize      = 16 << 20
+	defaultSnapshotInterval = 10 * time.Minute
+	defaultSweepInterval    = time.Minute
+	defaultGracePeriod      = 30 * 24 * time.Hour
 )
 
 // CustomIPNet кастомный net.IPNet для интрейфесов из flag, env,json
@@ -68,11 +74,29 @@ type Config struct {
 	Config        string      `env:"CONFIG"`
 	TrustedSubnet CustomIPNet `env:"TRUSTED_SUBNET" json:"trusted_subnet"`
 	GRPCPort      string      `env:"GRPC_PORT" json:"grpc_port"`
+	// ShortIDStrategy стратегия генерации коротких идентификаторов: random, counter или hash
+	ShortIDStrategy string `env:"SHORT_ID_STRATEGY" json:"short_id_strategy"`
+	// CompactSize размер журнала файлового хранилища в байтах, при котором делается снимок. 0 - отключено
+	CompactSize int64 `env:"FILE_STORAGE_COMPACT_SIZE" json:"file_storage_compact_size"`
+	// SnapshotInterval период снимков файлового хранилища. 0 - отключено
+	SnapshotInterval time.Duration `env:"FILE_STORAGE_SNAPSHOT_INTERVAL" json:"file_storage_snapshot_interval"`
+	// SweepInterval период удаления ссылок с истекшим сроком действия. 0 - отключено
+	SweepInterval time.Duration `env:"EXPIRED_SWEEP_INTERVAL" json:"expired_sweep_interval"`
+	// DeleteGracePeriod срок, в течение которого удаленную ссылку можно восстановить. После него ссылка удаляется окончательно
+	DeleteGracePeriod time.Duration `env:"DELETE_GRACE_PERIOD" json:"delete_grace_period"`
+	// GeoIPDatabase путь к базе MaxMind DB для правил перенаправления по стране. Пустое - правила по стране не срабатывают
+	GeoIPDatabase string `env:"GEOIP_DATABASE" json:"geoip_database"`
 }
 
 var cfg Config
 
 func init() {
+	// значения по умолчанию, для которых 0 - осмысленное значение, задаются до разбора: envDefault
+	// применялся бы при каждом env.Parse и перетирал значения из файла конфигурации
+	cfg.CompactSize = defaultCompactSize
+	cfg.SnapshotInterval = defaultSnapshotInterval
+	cfg.SweepInterval = defaultSweepInterval
+	cfg.DeleteGracePeriod = defaultGracePeriod
 	flag.StringVar(&cfg.Addr, "a", cfg.Addr, "Server Address")
 	flag.StringVar(&cfg.BaseURL, "b", cfg.BaseURL, "Base URL")
 	flag.StringVar(&cfg.FileStorage, "f", cfg.FileStorage, "path to file storage")
@@ -83,6 +107,12 @@ func init() {
 	flag.StringVar(&cfg.Config, "c", cfg.Config, "Config file destination")
 	flag.StringVar(&cfg.Config, "config", cfg.Config, "Config file destination")
 	flag.StringVar(&cfg.GRPCPort, "g", cfg.GRPCPort, "grpc server port")
+	flag.StringVar(&cfg.ShortIDStrategy, "id-strategy", cfg.ShortIDStrategy, "short id strategy: random, counter or hash")
+	flag.Int64Var(&cfg.CompactSize, "compact-size", cfg.CompactSize, "file storage log size in bytes to make snapshot")
+	flag.DurationVar(&cfg.SnapshotInterval, "snapshot-interval", cfg.SnapshotInterval, "file storage snapshot interval")
+	flag.DurationVar(&cfg.SweepInterval, "sweep-interval", cfg.SweepInterval, "expired and deleted urls sweep interval")
+	flag.DurationVar(&cfg.DeleteGracePeriod, "delete-grace-period", cfg.DeleteGracePeriod, "period to restore deleted urls")
+	flag.StringVar(&cfg.GeoIPDatabase, "geoip", cfg.GeoIPDatabase, "path to MaxMind DB file for country redirect rules")
 }
 
 // New возвращает конфиг. Приоритет file->env->flag
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_ConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"file_storage_compact_size": 1024,
+		"file_storage_snapshot_interval": 0,
+		"expired_sweep_interval": 5000000000,
+		"delete_grace_period": 3600000000000
+	}`), 0o600))
+	t.Setenv("CONFIG", path)
+
+	got, err := New()
+	require.NoError(t, err)
+	require.Equal(t, int64(1024), got.CompactSize)
+	require.Equal(t, time.Duration(0), got.SnapshotInterval)
+	require.Equal(t, 5*time.Second, got.SweepInterval)
+	require.Equal(t, time.Hour, got.DeleteGracePeriod)
+
+	t.Setenv("FILE_STORAGE_COMPACT_SIZE", "2048")
+	got, err = New()
+	require.NoError(t, err)
+	require.Equal(t, int64(2048), got.CompactSize, "env overrides file")
+}
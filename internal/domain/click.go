@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Click переход по короткой ссылке
+type Click struct {
+	Short     string    `db:"short"`
+	Time      time.Time `db:"clicked_at"`
+	Referrer  string    `db:"referrer"`
+	UserAgent string    `db:"user_agent"`
+	IP        string    `db:"ip"`
+	Variant   string    `db:"variant"` // вариант A/B-разделения, пустое значение - переход без вариантов
+}
+
+// DailyClicks количество переходов за сутки (UTC). Date - начало суток
+type DailyClicks struct {
+	Date   time.Time
+	Clicks int
+}
+
+// ReferrerClicks количество переходов с одного источника
+type ReferrerClicks struct {
+	Referrer string
+	Clicks   int
+}
+
+// VariantClicks количество переходов и уникальных посетителей по варианту A/B-разделения
+type VariantClicks struct {
+	Variant string
+	Clicks  int
+	Unique  int
+}
+
+// ClickStats статистика переходов по ссылке: всего, уникальных посетителей (IP и User-Agent),
+// самые частые источники по убыванию, переходы по дням в порядке возрастания даты и по вариантам в порядке имен
+type ClickStats struct {
+	Total        int
+	Unique       int
+	TopReferrers []ReferrerClicks
+	Daily        []DailyClicks
+	Variants     []VariantClicks
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// JobStatus состояние асинхронной задачи
+type JobStatus string
+
+// Состояния задачи удаления
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// DeleteJob задача пакетного удаления ссылок пользователя. После выполнения Deleted содержит удаленные ссылки,
+// Skipped - пропущенные, потому что они не принадлежат пользователю или не существуют
+type DeleteJob struct {
+	ID        string
+	User      string
+	Shorts    []string
+	Status    JobStatus
+	Deleted   []string
+	Skipped   []string
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
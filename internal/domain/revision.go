@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Revision изменение полного URL короткой ссылки
+type Revision struct {
+	Short     string    `db:"short"`
+	OldLong   string    `db:"old_long"`
+	NewLong   string    `db:"new_long"`
+	User      string    `db:"userID"`
+	ChangedAt time.Time `db:"changed_at"`
+}
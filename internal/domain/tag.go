@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTagExists метка с таким именем у пользователя уже есть.
+var ErrTagExists = errors.New("tag already exists")
+
+// Tag метка пользователя. Ссылка может иметь несколько меток
+type Tag struct {
+	Name      string
+	Links     int // неудаленные ссылки с меткой
+	CreatedAt time.Time
+}
+
+// Folder папка пользователя. Ссылка лежит не больше чем в одной папке, папка создается при первом переносе в нее
+type Folder struct {
+	Name      string
+	Links     int // неудаленные ссылки в папке
+	CreatedAt time.Time
+}
@@ -1,9 +1,154 @@
 // Package domain содержит структуры относящиеся к бизнес логике.
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrShortExists короткий идентификатор уже занят другой ссылкой.
+	ErrShortExists = errors.New("short already exists")
+	// ErrNotFound ссылки с таким коротким идентификатором нет.
+	ErrNotFound = errors.New("url not found")
+	// ErrClicksExhausted у ссылки закончились разрешенные переходы.
+	ErrClicksExhausted = errors.New("url click limit reached")
+)
+
 // URL структура описывающая ссылку.
 type URL struct {
-	Short string `db:"short"`
-	Long  string `db:"long"`
-	User  string `db:"userID"`
+	Short     string    `db:"short"`
+	Long      string    `db:"long"`
+	User      string    `db:"userID"`
+	Deleted   bool      `db:"deleted"`
+	DeletedAt time.Time `db:"deleted_at"` // момент удаления, от него отсчитывается срок восстановления
+	ExpiresAt time.Time `db:"expires_at"` // нулевое значение - ссылка бессрочная
+	CreatedAt time.Time `db:"created_at"` // нулевое значение у ссылок, созданных до появления поля
+	UpdatedAt time.Time `db:"updated_at"` // последнее изменение: создание, смена URL или настроек, удаление или восстановление
+	Title     string    `db:"title"`
+	Tags      []string  `db:"tags"`
+	Folder    string    `db:"folder"`        // пустое значение - вне папок
+	Preview   bool      `db:"preview"`       // вместо перехода показывается страница с адресом назначения
+	Password  string    `db:"password_hash"` // bcrypt-хэш пароля, пустое значение - ссылка без пароля
+	MaxClicks int       `db:"max_clicks"`    // разрешено переходов, 0 - без ограничения
+	Uses      int       `db:"uses"`          // переходов, учтенных в ограничении
+	NotBefore time.Time `db:"not_before"`    // начало окна активности, нулевое значение - ссылка активна сразу
+	Fallback  string    `db:"fallback_url"`  // куда вести до начала окна, пустое значение - страница "скоро"
+	// Rules правила перенаправления по порядку проверки. Если не подошло ни одно - переход на Long
+	Rules []RedirectRule `db:"rules"`
+	// Variants варианты A/B-разделения. Если заданы, переходы без подходящего правила делятся между ними вместо Long
+	Variants []Variant `db:"variants"`
+}
+
+// RedirectRule правило перенаправления. Пустое условие подходит любому посетителю, непустые должны совпасть все
+type RedirectRule struct {
+	Platform string `json:"platform,omitempty"` // ios, android, windows, macos или linux по User-Agent
+	Language string `json:"language,omitempty"` // основной язык из Accept-Language: en подходит и для en-US
+	Country  string `json:"country,omitempty"`  // код страны ISO 3166-1 alpha-2 по базе GeoIP
+	Long     string `json:"url"`
+}
+
+// Variant вариант адреса для A/B-разделения переходов
+type Variant struct {
+	Name   string `json:"name"`
+	Long   string `json:"url"`
+	Weight int    `json:"weight"` // доля переходов относительно суммы весов всех вариантов
+}
+
+// LinkInfo ссылка со сводкой для списков и выгрузок
+type LinkInfo struct {
+	URL
+	Clicks int
+}
+
+// ListSort поле сортировки ссылок. При равенстве ссылки упорядочены по короткому идентификатору
+type ListSort string
+
+// Поля сортировки ссылок
+const (
+	SortShort   ListSort = "short"
+	SortCreated ListSort = "created"
+	SortClicks  ListSort = "clicks"
+)
+
+// ListStatus фильтр ссылок по состоянию. Пустое значение - все ссылки
+type ListStatus string
+
+// Фильтры по состоянию
+const (
+	StatusAll     ListStatus = "all"
+	StatusActive  ListStatus = "active" // не удаленные и не истекшие
+	StatusDeleted ListStatus = "deleted"
+)
+
+// ListCursor позиция в выборке: ключи сортировки последней ссылки предыдущей страницы.
+// Пустой Short - начало выборки
+type ListCursor struct {
+	Short     string
+	CreatedAt time.Time
+	Clicks    int
+}
+
+// ListQuery параметры постраничной выборки ссылок пользователя
+type ListQuery struct {
+	After  ListCursor
+	Limit  int
+	Sort   ListSort // пустое значение - по короткому идентификатору
+	Desc   bool
+	Status ListStatus
+	Domain string   // хост полного URL, включая поддомены
+	Search string   // подстрока полного URL или короткого идентификатора без учета регистра
+	Tags   []string // ссылка должна иметь все метки
+	Folder string
+}
+
+// Cursor возвращает позицию выборки сразу после ссылки
+func (l LinkInfo) Cursor() ListCursor {
+	return ListCursor{Short: l.Short, CreatedAt: l.CreatedAt, Clicks: l.Clicks}
+}
+
+// Expired проверяет, истек ли срок действия ссылки на момент now.
+func (u URL) Expired(now time.Time) bool {
+	return !u.ExpiresAt.IsZero() && !now.Before(u.ExpiresAt)
+}
+
+// Pending проверяет, что окно активности ссылки на момент now еще не открылось
+func (u URL) Pending(now time.Time) bool {
+	return !u.NotBefore.IsZero() && now.Before(u.NotBefore)
+}
+
+// Exhausted проверяет, что разрешенные переходы по ссылке закончились
+func (u URL) Exhausted() bool {
+	return u.MaxClicks > 0 && u.Uses >= u.MaxClicks
+}
+
+// Protected проверяет, что переход по ссылке требует пароль
+func (u URL) Protected() bool {
+	return u.Password != ""
+}
+
+// Restorable проверяет, что удаленную ссылку еще можно восстановить: удалена не раньше deletedAfter
+func (u URL) Restorable(deletedAfter time.Time) bool {
+	return u.Deleted && !u.DeletedAt.Before(deletedAfter)
+}
+
+// ShortExistsError ошибка занятого идентификатора. Содержит сам идентификатор, errors.Is(err, ErrShortExists) - true
+type ShortExistsError struct {
+	Short string
+}
+
+// Error для интерфейса Error
+func (e *ShortExistsError) Error() string {
+	return fmt.Sprintf("short %s already exists", e.Short)
+}
+
+// Is для errors.Is
+func (e *ShortExistsError) Is(target error) bool {
+	return target == ErrShortExists
+}
+
+// NewShortExistsError возвращает ошибку ShortExistsError
+func NewShortExistsError(short string) error {
+	return &ShortExistsError{Short: short}
 }
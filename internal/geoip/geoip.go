@@ -0,0 +1,330 @@
+// Package geoip определяет страну по IP-адресу из локальной базы в формате MaxMind DB
+// (GeoLite2-Country, GeoIP2-Country, GeoLite2-City и совместимые) без внешних зависимостей.
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+var (
+	// ErrWrongFormat - файл не является базой MaxMind DB или поврежден.
+	ErrWrongFormat = errors.New("geoip: wrong database format")
+)
+
+// metadataMarker предваряет метаданные в конце файла
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSeparatorSize - нулевые байты между деревом поиска и секцией данных
+const dataSeparatorSize = 16
+
+// maxDepth ограничивает вложенность данных, защита от зацикленных указателей в поврежденной базе
+const maxDepth = 32
+
+// Типы полей секции данных
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// DB база MaxMind DB, целиком загруженная в память. Безопасна для одновременного использования
+type DB struct {
+	tree       []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint // узел, с которого ищутся IPv4-адреса в базе IPv6
+}
+
+// Open читает базу из файла path
+func Open(path string) (*DB, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(buf)
+}
+
+// New разбирает базу из buf. Ошибка - ErrWrongFormat
+func New(buf []byte) (*DB, error) {
+	i := bytes.LastIndex(buf, metadataMarker)
+	if i < 0 {
+		return nil, ErrWrongFormat
+	}
+	meta := buf[i+len(metadataMarker):]
+	d := decoder{buf: meta}
+	value, _, err := d.decode(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, ErrWrongFormat
+	}
+	nodeCount, okNodes := m["node_count"].(uint64)
+	recordSize, okRecord := m["record_size"].(uint64)
+	ipVersion, okVersion := m["ip_version"].(uint64)
+	if !okNodes || !okRecord || !okVersion {
+		return nil, ErrWrongFormat
+	}
+	if recordSize != 24 && recordSize != 28 && recordSize != 32 || ipVersion != 4 && ipVersion != 6 {
+		return nil, ErrWrongFormat
+	}
+	treeSize := nodeCount * recordSize / 4
+	if treeSize+dataSeparatorSize > uint64(i) {
+		return nil, ErrWrongFormat
+	}
+	db := &DB{
+		tree:       buf[:treeSize],
+		data:       buf[treeSize+dataSeparatorSize : i],
+		nodeCount:  uint(nodeCount),
+		recordSize: uint(recordSize),
+		ipVersion:  uint(ipVersion),
+	}
+	if db.ipVersion == 6 {
+		// IPv4-адреса лежат в дереве как ::a.b.c.d, то есть после 96 нулевых бит
+		node := uint(0)
+		for i := 0; i < 96 && node < db.nodeCount; i++ {
+			node = db.record(node, 0)
+		}
+		db.ipv4Start = node
+	}
+	return db, nil
+}
+
+// Country возвращает код страны ISO 3166-1 alpha-2 для ip: страну местонахождения, а если ее нет -
+// страну регистрации сети. Для адресов, которых нет в базе, - пустая строка
+func (db *DB) Country(ip net.IP) (string, error) {
+	value, err := db.Lookup(ip)
+	if err != nil || value == nil {
+		return "", err
+	}
+	record, _ := value.(map[string]any)
+	for _, key := range []string{"country", "registered_country"} {
+		if country, ok := record[key].(map[string]any); ok {
+			if code, ok := country["iso_code"].(string); ok && code != "" {
+				return code, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// Lookup возвращает запись базы для ip: map[string]any, []any, string, uint64, int32, float64, bool или []byte.
+// Для адресов, которых нет в базе, - nil
+func (db *DB) Lookup(ip net.IP) (any, error) {
+	node, bits := uint(0), 128
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 32
+		if db.ipVersion == 6 {
+			node = db.ipv4Start
+		}
+	} else if ip = ip.To16(); ip == nil || db.ipVersion == 4 {
+		return nil, nil
+	}
+	for i := 0; i < bits && node < db.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-uint(i&7))) & 1
+		node = db.record(node, bit)
+	}
+	if node == db.nodeCount {
+		return nil, nil
+	}
+	if node < db.nodeCount {
+		return nil, ErrWrongFormat
+	}
+	offset := node - db.nodeCount - dataSeparatorSize
+	if offset >= uint(len(db.data)) {
+		return nil, ErrWrongFormat
+	}
+	d := decoder{buf: db.data}
+	value, _, err := d.decode(offset, 0)
+	return value, err
+}
+
+// record возвращает левую (bit 0) или правую (bit 1) запись узла дерева
+func (db *DB) record(node, bit uint) uint {
+	switch db.recordSize {
+	case 24:
+		b := db.tree[node*6+bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		b := db.tree[node*7:]
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(db.tree[node*8+bit*4:]))
+	}
+}
+
+// decoder читает поля секции данных. Указатели отсчитываются от начала buf
+type decoder struct {
+	buf []byte
+}
+
+// decode читает поле по смещению offset и возвращает его значение и смещение следующего поля
+func (d decoder) decode(offset uint, depth int) (any, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, ErrWrongFormat
+	}
+	typ, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if typ == typePointer {
+		pointer, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	switch typ {
+	case typeMap:
+		m := make(map[string]any, size)
+		for i := uint(0); i < size; i++ {
+			var key, value any
+			if key, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, ErrWrongFormat
+			}
+			if value, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			m[name] = value
+		}
+		return m, offset, nil
+	case typeArray:
+		a := make([]any, 0, size)
+		for i := uint(0); i < size; i++ {
+			var value any
+			if value, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+		}
+		return a, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+	if offset+size > uint(len(d.buf)) {
+		return nil, 0, ErrWrongFormat
+	}
+	b, next := d.buf[offset:offset+size], offset+size
+	switch typ {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, ErrWrongFormat
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, ErrWrongFormat
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case typeUint16, typeUint32, typeUint64, typeInt32:
+		if size > 8 {
+			return nil, 0, ErrWrongFormat
+		}
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		if typ == typeInt32 {
+			return int32(uint32(v)), next, nil
+		}
+		return v, next, nil
+	case typeUint128:
+		// значения больше uint64 для стран не нужны, отдаются байтами
+		return append([]byte(nil), b...), next, nil
+	}
+	return nil, 0, fmt.Errorf("%w: data type %d", ErrWrongFormat, typ)
+}
+
+// control читает управляющий байт поля: тип и размер, и возвращает смещение содержимого
+func (d decoder) control(offset uint) (int, uint, uint, error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, 0, 0, ErrWrongFormat
+	}
+	ctrl := d.buf[offset]
+	offset++
+	typ := int(ctrl >> 5)
+	if typ == typeExtended {
+		if offset >= uint(len(d.buf)) {
+			return 0, 0, 0, ErrWrongFormat
+		}
+		typ = 7 + int(d.buf[offset])
+		offset++
+	}
+	size := uint(ctrl & 0x1F)
+	if typ == typePointer || size < 29 {
+		return typ, size, offset, nil
+	}
+	n := size - 28
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, 0, ErrWrongFormat
+	}
+	var v uint
+	for _, c := range d.buf[offset : offset+n] {
+		v = v<<8 | uint(c)
+	}
+	switch size {
+	case 29:
+		size = 29 + v
+	case 30:
+		size = 285 + v
+	default:
+		size = 65821 + v
+	}
+	return typ, size, offset + n, nil
+}
+
+// pointer читает указатель по младшим битам управляющего байта ctrl
+func (d decoder) pointer(ctrl, offset uint) (uint, uint, error) {
+	n := (ctrl>>3)&0x3 + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, ErrWrongFormat
+	}
+	var v uint
+	if n < 4 {
+		v = ctrl & 0x7
+	}
+	for _, c := range d.buf[offset : offset+n] {
+		v = v<<8 | uint(c)
+	}
+	switch n {
+	case 2:
+		v += 2048
+	case 3:
+		v += 526336
+	}
+	return v, offset + n, nil
+}
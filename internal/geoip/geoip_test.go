@@ -0,0 +1,219 @@
+package geoip
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// encode кодирует значение в формате секции данных MaxMind DB
+func encode(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case string:
+		control(buf, typeString, len(v))
+		buf.WriteString(v)
+	case uint64:
+		var b []byte
+		for ; v > 0; v >>= 8 {
+			b = append([]byte{byte(v)}, b...)
+		}
+		control(buf, typeUint32, len(b))
+		buf.Write(b)
+	case bool:
+		n := 0
+		if v {
+			n = 1
+		}
+		control(buf, typeBool, n)
+	case []any:
+		control(buf, typeArray, len(v))
+		for _, item := range v {
+			encode(buf, item)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		control(buf, typeMap, len(v))
+		for _, key := range keys {
+			encode(buf, key)
+			encode(buf, v[key])
+		}
+	}
+}
+
+func control(buf *bytes.Buffer, typ, size int) {
+	var ext []byte
+	if typ > 7 {
+		ext = []byte{byte(typ - 7)}
+		typ = typeExtended
+	}
+	var extra []byte
+	switch {
+	case size < 29:
+	case size < 285:
+		extra, size = []byte{byte(size - 29)}, 29
+	default:
+		extra, size = []byte{byte((size - 285) >> 8), byte(size - 285)}, 30
+	}
+	buf.WriteByte(byte(typ<<5 | size))
+	buf.Write(ext)
+	buf.Write(extra)
+}
+
+type network struct {
+	cidr   string
+	record map[string]any
+}
+
+// build собирает базу IPv6 с записями размера recordSize по сетям networks
+func build(t *testing.T, recordSize int, networks []network) []byte {
+	t.Helper()
+	const empty = -1
+	tree := [][2]int{{empty, empty}}
+	var data bytes.Buffer
+	leaves := map[[2]int]int{}
+	for _, n := range networks {
+		_, ipNet, err := net.ParseCIDR(n.cidr)
+		require.NoError(t, err)
+		ip, ones := ipNet.IP.To16(), 0
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			// IPv4 в дереве IPv6 - ::a.b.c.d
+			ip = append(make([]byte, 12), ip4...)
+			prefix, _ := ipNet.Mask.Size()
+			ones = 96 + prefix
+		} else {
+			ones, _ = ipNet.Mask.Size()
+		}
+		offset := data.Len()
+		encode(&data, n.record)
+		node := 0
+		for i := 0; i < ones; i++ {
+			bit := int(ip[i>>3]>>(7-uint(i&7))) & 1
+			if i == ones-1 {
+				leaves[[2]int{node, bit}] = offset
+				break
+			}
+			if tree[node][bit] == empty {
+				tree = append(tree, [2]int{empty, empty})
+				tree[node][bit] = len(tree) - 1
+			}
+			node = tree[node][bit]
+		}
+	}
+	nodeCount := len(tree)
+	var buf bytes.Buffer
+	for node, records := range tree {
+		var values [2]uint
+		for bit, next := range records {
+			switch offset, ok := leaves[[2]int{node, bit}]; {
+			case ok:
+				values[bit] = uint(nodeCount + dataSeparatorSize + offset)
+			case next == empty:
+				values[bit] = uint(nodeCount)
+			default:
+				values[bit] = uint(next)
+			}
+		}
+		switch recordSize {
+		case 24:
+			for _, v := range values {
+				buf.Write([]byte{byte(v >> 16), byte(v >> 8), byte(v)})
+			}
+		case 28:
+			l, r := values[0], values[1]
+			buf.Write([]byte{byte(l >> 16), byte(l >> 8), byte(l), byte(l>>20&0xF0 | r>>24&0x0F),
+				byte(r >> 16), byte(r >> 8), byte(r)})
+		case 32:
+			for _, v := range values {
+				buf.Write([]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+			}
+		}
+	}
+	buf.Write(make([]byte, dataSeparatorSize))
+	buf.Write(data.Bytes())
+	buf.Write(metadataMarker)
+	encode(&buf, map[string]any{
+		"node_count":                  uint64(nodeCount),
+		"record_size":                 uint64(recordSize),
+		"ip_version":                  uint64(6),
+		"database_type":               "Test-Country",
+		"binary_format_major_version": uint64(2),
+		"languages":                   []any{"en"},
+	})
+	return buf.Bytes()
+}
+
+func country(code string) map[string]any {
+	return map[string]any{"iso_code": code, "names": map[string]any{"en": "Test " + code}}
+}
+
+func TestDB_Country(t *testing.T) {
+	networks := []network{
+		{cidr: "81.2.69.0/24", record: map[string]any{"country": country("GB"), "registered_country": country("GB")}},
+		{cidr: "81.2.70.0/23", record: map[string]any{"country": country("SE")}},
+		{cidr: "2001:db8::/32", record: map[string]any{"registered_country": country("DE"), "is_anycast": true}},
+	}
+	for _, recordSize := range []int{24, 28, 32} {
+		db, err := New(build(t, recordSize, networks))
+		require.NoError(t, err, recordSize)
+		for ip, want := range map[string]string{
+			"81.2.69.160":    "GB",
+			"81.2.70.1":      "SE",
+			"81.2.71.255":    "SE",
+			"81.2.72.1":      "",
+			"10.0.0.1":       "",
+			"2001:db8::1":    "DE",
+			"2001:db9::1":    "",
+			"::ffff:81.2.69": "",
+		} {
+			got, err := db.Country(net.ParseIP(ip))
+			require.NoError(t, err)
+			require.Equal(t, want, got, "%s record size %d", ip, recordSize)
+		}
+		record, err := db.Lookup(net.ParseIP("2001:db8::1"))
+		require.NoError(t, err)
+		require.Equal(t, true, record.(map[string]any)["is_anycast"])
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "country.mmdb")
+	require.NoError(t, os.WriteFile(path, build(t, 24, []network{{cidr: "1.0.0.0/8", record: map[string]any{"country": country("AU")}}}), 0o600))
+	db, err := Open(path)
+	require.NoError(t, err)
+	got, err := db.Country(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	require.Equal(t, "AU", got)
+
+	_, err = New([]byte("not a database"))
+	require.ErrorIs(t, err, ErrWrongFormat)
+	_, err = New(append([]byte{}, metadataMarker...))
+	require.ErrorIs(t, err, ErrWrongFormat)
+}
+
+func TestDecoder_Pointer(t *testing.T) {
+	// значение {"a": "xyz", "b": указатель на "xyz"}
+	var buf bytes.Buffer
+	control(&buf, typeMap, 2)
+	encode(&buf, "a")
+	encode(&buf, "xyz")
+	encode(&buf, "b")
+	buf.WriteByte(byte(typePointer<<5 | 0)) // указатель на 1 байт
+	buf.WriteByte(3)                        // смещение строки "xyz"
+	value, next, err := decoder{buf: buf.Bytes()}.decode(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint(buf.Len()), next)
+	require.Equal(t, map[string]any{"a": "xyz", "b": "xyz"}, value)
+
+	// указатель сам на себя
+	_, _, err = decoder{buf: []byte{typePointer << 5, 0}}.decode(0, 0)
+	require.ErrorIs(t, err, ErrWrongFormat)
+}
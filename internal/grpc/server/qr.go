@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	"github.com/Spear5030/yapshrtnr/internal/pb"
+)
+
+// GetQR возвращает QR-код короткой ссылки в PNG или SVG с типом содержимого и ETag.
+// Для несуществующих, удаленных и истекших ссылок - NotFound
+func (s *ShortenerServer) GetQR(ctx context.Context, in *pb.QRRequest) (*pb.QRImage, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	margin := -1
+	if in.Margin != nil {
+		if margin = int(in.GetMargin()); margin < 0 {
+			return nil, status.Error(codes.InvalidArgument, module.ErrWrongQR.Error())
+		}
+	}
+	opts, err := module.NewQROptions(int(in.GetSize()), in.GetFormat(), in.GetEc(), margin)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	url, err := s.Storage.GetURL(ctx, in.GetShort())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if url.Deleted {
+		return nil, status.Error(codes.NotFound, "url deleted")
+	}
+	if url.Expired(time.Now()) {
+		return nil, status.Error(codes.NotFound, "url expired")
+	}
+	if url.Exhausted() {
+		return nil, status.Error(codes.NotFound, "click limit reached")
+	}
+	content := s.baseURL + "/" + url.Short
+	img, err := module.RenderQR(content, opts)
+	if err != nil {
+		s.logger.Error("RenderQR error", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.QRImage{Image: img, ContentType: opts.ContentType(), Etag: opts.ETag(content)}, nil
+}
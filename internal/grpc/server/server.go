@@ -15,11 +15,14 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"log"
 	"net"
+	"time"
 )
 
 // ShortenerServer - сервер с точки зрения grpc
@@ -32,6 +35,9 @@ type ShortenerServer struct {
 	baseURL       string
 	secretKey     string
 	trustedSubnet net.IPNet
+	shortener     *module.Shortener
+	gracePeriod   time.Duration // срок восстановления удаленных ссылок
+	unlocks       *module.Throttle
 }
 
 // GRPCServer с портом для запуска
@@ -41,23 +47,46 @@ type GRPCServer struct {
 }
 
 type storage interface {
-	SetURL(ctx context.Context, user, short, long string) error
-	GetURL(ctx context.Context, short string) (string, bool)
-	GetURLsByUser(ctx context.Context, user string) (urls map[string]string)
+	SetURL(ctx context.Context, url domain.URL) error
+	GetURL(ctx context.Context, short string) (domain.URL, error)
 	SetBatchURLs(ctx context.Context, urls []domain.URL) error
-	DeleteURLs(ctx context.Context, user string, shorts []string)
+	DeleteURLs(ctx context.Context, user string, shorts []string) (string, error)
+	GetDeleteJob(ctx context.Context, id string) (domain.DeleteJob, error)
+	ListURLs(ctx context.Context, user string, query domain.ListQuery) ([]domain.LinkInfo, error)
 	GetUsersCount(ctx context.Context) (int, error)
 	GetUrlsCount(ctx context.Context) (int, error)
+	RecordClick(ctx context.Context, click domain.Click)
+	GetClickStats(ctx context.Context, short string) (domain.ClickStats, error)
+	UpdateURL(ctx context.Context, user, short, long string) (domain.URL, error)
+	GetURLRevisions(ctx context.Context, short string) ([]domain.Revision, error)
+	RestoreURL(ctx context.Context, user, short string, deletedAfter time.Time) (domain.URL, error)
+	ListTags(ctx context.Context, user string) ([]domain.Tag, error)
+	CreateTag(ctx context.Context, user, name string) (domain.Tag, error)
+	RenameTag(ctx context.Context, user, name, newName string) (domain.Tag, error)
+	DeleteTag(ctx context.Context, user, name string) error
+	ListFolders(ctx context.Context, user string) ([]domain.Folder, error)
+	DeleteFolder(ctx context.Context, user, name string) error
+	SetURLTags(ctx context.Context, user, short string, tags []string) (domain.URL, error)
+	SetURLFolder(ctx context.Context, user, short, folder string) (domain.URL, error)
+	SetURLPreview(ctx context.Context, user, short string, preview bool) (domain.URL, error)
+	SetURLPassword(ctx context.Context, user, short, hash string) (domain.URL, error)
+	SetURLRules(ctx context.Context, user, short string, rules []domain.RedirectRule) (domain.URL, error)
+	SetURLVariants(ctx context.Context, user, short string, variants []domain.Variant) (domain.URL, error)
+	UseURL(ctx context.Context, short string) (domain.URL, error)
 }
 
 // New конструктор GRPCServer
-func New(storage storage, logger *zap.Logger, port string, baseURL string, skey string, ipNet net.IPNet) *GRPCServer {
+func New(storage storage, logger *zap.Logger, port string, baseURL string, skey string, ipNet net.IPNet, gen module.Generator,
+	gracePeriod time.Duration) *GRPCServer {
 	shortenerServer := &ShortenerServer{
 		Storage:       storage,
 		logger:        logger,
 		baseURL:       baseURL,
 		secretKey:     skey,
 		trustedSubnet: ipNet,
+		shortener:     module.NewShortener(gen),
+		gracePeriod:   gracePeriod,
+		unlocks:       module.NewThrottle(module.PasswordAttempts, module.PasswordWindow),
 	}
 	s := GRPCServer{
 		Server: grpc.NewServer(grpc.UnaryInterceptor(shortenerServer.AuthInterceptor)),
@@ -97,13 +126,66 @@ func (s *ShortenerServer) Ping(ctx context.Context, in *emptypb.Empty) (*emptypb
 	//log.Fatal("Storage haven't pinger")
 }
 
-// GetURL возвращает полную ссылку по короткому представлению
+// GetURL возвращает полную ссылку по короткому представлению, флаг предпросмотра и название.
+// Для несуществующих, истекших и исчерпавших переходы ссылок - NotFound. Пароль защищенной ссылки передается в метаданных password:
+// без него - Unauthenticated, неверный - PermissionDenied, после PasswordAttempts неудач - ResourceExhausted.
+// До начала окна активности - запасной URL с not_before или FailedPrecondition, если запасного URL нет.
+// Для удаленной ссылки - только флаг deleted, пароль не проверяется, как и в HTTP
 func (s *ShortenerServer) GetURL(ctx context.Context, in *pb.Short) (*pb.GetResponse, error) {
 	var response pb.GetResponse
 	if len(in.GetShort()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Missing short url")
 	}
-	response.Long, response.Deleted = s.Storage.GetURL(ctx, in.GetShort())
+	url, err := s.Storage.GetURL(ctx, in.GetShort())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	now := time.Now()
+	if url.Expired(now) {
+		return nil, status.Error(codes.NotFound, "url expired")
+	}
+	if url.Exhausted() {
+		return nil, status.Error(codes.NotFound, "click limit reached")
+	}
+	if url.Deleted {
+		return &pb.GetResponse{Deleted: true}, nil
+	}
+	if url.Pending(now) {
+		if url.Fallback == "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "url is not active until %s", url.NotBefore.UTC().Format(time.RFC3339))
+		}
+		return &pb.GetResponse{Long: url.Fallback, Title: url.Title, NotBefore: timestamppb.New(url.NotBefore)}, nil
+	}
+	if url.Protected() {
+		if err = s.checkPassword(ctx, url, now); err != nil {
+			return nil, err
+		}
+	}
+	if url.MaxClicks > 0 {
+		url, err = s.Storage.UseURL(ctx, url.Short)
+		if errors.Is(err, domain.ErrClicksExhausted) || errors.Is(err, domain.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "click limit reached")
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	click := clickFromContext(ctx, url.Short)
+	if len(url.Variants) > 0 {
+		// посетитель - id из метаданных, без него - IP и User-Agent, как в HTTP без cookie
+		visitor := getUserByMD(ctx)
+		if visitor == "" {
+			visitor = click.IP + "|" + click.UserAgent
+		}
+		variant := module.PickVariant(url.Short, url.Variants, visitor)
+		url.Long, click.Variant, response.Variant = variant.Long, variant.Name, variant.Name
+	}
+	response.Long = url.Long
+	response.Preview, response.Title = url.Preview, url.Title
+	s.Storage.RecordClick(context.Background(), click)
 	return &response, nil
 }
 
@@ -112,13 +194,40 @@ func (s *ShortenerServer) PostURL(ctx context.Context, in *pb.Long) (*pb.Short,
 	if len(in.Long) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "No url for shorting")
 	}
-	short, err := module.ShortingURL(in.Long)
+	user := getUserByMD(ctx)
+	expiresAt, err := module.NotAfter(timestampToTime(in.ExpiresAt), timestampToTime(in.NotAfter))
+	if err == nil {
+		expiresAt, err = module.ExpiresAt(in.Ttl, expiresAt, time.Now())
+	}
+	if err == nil {
+		err = module.ValidateSchedule(timestampToTime(in.NotBefore), expiresAt, in.FallbackUrl)
+	}
 	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	title, tags, err := module.NormalizeMeta(in.Title, in.Tags)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	password, err := module.HashPassword(in.Password)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = module.ValidateMaxClicks(int(in.MaxClicks)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	short, err := s.shortener.Short(in.Long, in.Alias, func(short string) error {
+		return s.Storage.SetURL(ctx, domain.URL{Short: short, Long: in.Long, User: user, ExpiresAt: expiresAt,
+			Title: title, Tags: tags, Preview: in.Preview, Password: password,
+			MaxClicks: int(in.MaxClicks), NotBefore: timestampToTime(in.NotBefore), Fallback: in.FallbackUrl})
+	})
+	if module.IsInputError(err) {
 		s.logger.Info("Error shorting", zap.Error(err))
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	user := getUserByMD(ctx)
-	err = s.Storage.SetURL(ctx, user, short, in.Long)
+	if errors.Is(err, module.ErrAliasTaken) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
 	var de *pckgstorage.DuplicationError
 	var response pb.Short
 	if err != nil {
@@ -133,18 +242,278 @@ func (s *ShortenerServer) PostURL(ctx context.Context, in *pb.Long) (*pb.Short,
 	return &response, nil
 }
 
-// GetURLsByUser возвращает слайс ссылок, которые созданы текущим пользователем
-func (s *ShortenerServer) GetURLsByUser(ctx context.Context, in *emptypb.Empty) (*pb.ResponseGetURLsByUser, error) {
+// GetURLsByUser возвращает страницу ссылок, которые созданы текущим пользователем, с курсором следующей страницы.
+// Параметры выборки те же, что у GET /api/user/urls
+func (s *ShortenerServer) GetURLsByUser(ctx context.Context, in *pb.RequestGetURLsByUser) (*pb.ResponseGetURLsByUser, error) {
 	user := getUserByMD(ctx)
-	urls := s.Storage.GetURLsByUser(ctx, user)
-	if len(urls) == 0 {
+	query, err := module.NewListQuery(int(in.GetLimit()), in.GetCursor(), in.GetSort(), in.GetOrder(), in.GetStatus(),
+		in.GetDomain(), in.GetSearch(), in.GetTags(), in.GetFolder())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	page, err := s.Storage.ListURLs(ctx, user, query)
+	if err != nil {
+		s.logger.Error("ListURLs error", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(page) == 0 {
 		return nil, status.Error(codes.NotFound, "0 urls")
 	}
-	response := &pb.ResponseGetURLsByUser{}
-	for short, long := range urls {
-		response.Urls = append(response.Urls, &pb.URL{
-			Short: s.baseURL + "/" + short,
-			Long:  long})
+	response := &pb.ResponseGetURLsByUser{NextCursor: module.NextCursor(query, page)}
+	for _, info := range page {
+		url := s.url(info.URL)
+		url.Deleted = info.Deleted
+		url.Clicks = int64(info.Clicks)
+		response.Urls = append(response.Urls, url)
+	}
+	return response, nil
+}
+
+// GetURLStats возвращает статистику переходов по ссылке текущего пользователя. Для чужих и несуществующих ссылок - NotFound
+func (s *ShortenerServer) GetURLStats(ctx context.Context, in *pb.Short) (*pb.URLStatsResponse, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	user := getUserByMD(ctx)
+	url, err := s.Storage.GetURL(ctx, in.GetShort())
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && url.User != user) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	stats, err := s.Storage.GetClickStats(ctx, url.Short)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &pb.URLStatsResponse{
+		Short:  s.baseURL + "/" + url.Short,
+		Total:  int64(stats.Total),
+		Unique: int64(stats.Unique),
+	}
+	for _, referrer := range stats.TopReferrers {
+		response.TopReferrers = append(response.TopReferrers, &pb.URLStatsResponseReferrer{
+			Referrer: referrer.Referrer,
+			Clicks:   int64(referrer.Clicks),
+		})
+	}
+	for _, day := range stats.Daily {
+		response.Daily = append(response.Daily, &pb.URLStatsResponsePoint{
+			Date:   timestamppb.New(day.Date),
+			Clicks: int64(day.Clicks),
+		})
+	}
+	for _, variant := range stats.Variants {
+		response.Variants = append(response.Variants, &pb.URLStatsResponseVariant{
+			Variant: variant.Variant,
+			Clicks:  int64(variant.Clicks),
+			Unique:  int64(variant.Unique),
+		})
+	}
+	return response, nil
+}
+
+// UpdateURL меняет полный URL ссылки текущего пользователя. Для чужих и несуществующих ссылок - NotFound,
+// если новый URL уже сокращен другой ссылкой - AlreadyExists
+func (s *ShortenerServer) UpdateURL(ctx context.Context, in *pb.UpdateURLRequest) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	if err := module.ValidateURL(in.GetLong()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	url, err := s.Storage.UpdateURL(ctx, getUserByMD(ctx), in.GetShort(), in.GetLong())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	var de *pckgstorage.DuplicationError
+	if errors.As(err, &de) {
+		return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("url already shortened as %s/%s", s.baseURL, de.Duplication))
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.url(url), nil
+}
+
+// RestoreURL восстанавливает удаленную ссылку текущего пользователя в течение срока восстановления.
+// Для чужих, несуществующих и удаленных раньше срока ссылок - NotFound
+func (s *ShortenerServer) RestoreURL(ctx context.Context, in *pb.Short) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	url, err := s.Storage.RestoreURL(ctx, getUserByMD(ctx), in.GetShort(), time.Now().Add(-s.gracePeriod))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.url(url), nil
+}
+
+// SetURLPreview включает или выключает предпросмотр ссылки текущего пользователя.
+// Для чужих, удаленных и несуществующих ссылок - NotFound
+func (s *ShortenerServer) SetURLPreview(ctx context.Context, in *pb.SetURLPreviewRequest) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	url, err := s.Storage.SetURLPreview(ctx, getUserByMD(ctx), in.GetShort(), in.GetPreview())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.url(url), nil
+}
+
+// SetURLPassword задает пароль ссылки текущего пользователя, пустой пароль снимает защиту.
+// Для чужих, удаленных и несуществующих ссылок - NotFound
+func (s *ShortenerServer) SetURLPassword(ctx context.Context, in *pb.SetURLPasswordRequest) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	hash, err := module.HashPassword(in.GetPassword())
+	if module.IsInputError(err) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	url, err := s.Storage.SetURLPassword(ctx, getUserByMD(ctx), in.GetShort(), hash)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.url(url), nil
+}
+
+// SetURLRules заменяет упорядоченный список правил перенаправления ссылки текущего пользователя,
+// пустой список убирает правила. Для неверных правил - InvalidArgument, для чужих, удаленных и несуществующих ссылок - NotFound
+func (s *ShortenerServer) SetURLRules(ctx context.Context, in *pb.SetURLRulesRequest) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	rules := make([]domain.RedirectRule, 0, len(in.GetRules()))
+	for _, rule := range in.GetRules() {
+		rules = append(rules, domain.RedirectRule{Platform: rule.GetPlatform(), Language: rule.GetLanguage(),
+			Country: rule.GetCountry(), Long: rule.GetLong()})
+	}
+	rules, err := module.NormalizeRules(rules)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	url, err := s.Storage.SetURLRules(ctx, getUserByMD(ctx), in.GetShort(), rules)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.url(url), nil
+}
+
+// SetURLVariants заменяет варианты A/B-разделения ссылки текущего пользователя, пустой список убирает разделение.
+// Для неверных вариантов - InvalidArgument, для чужих, удаленных и несуществующих ссылок - NotFound
+func (s *ShortenerServer) SetURLVariants(ctx context.Context, in *pb.SetURLVariantsRequest) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	variants := make([]domain.Variant, 0, len(in.GetVariants()))
+	for _, variant := range in.GetVariants() {
+		variants = append(variants, domain.Variant{Name: variant.GetName(), Long: variant.GetLong(), Weight: int(variant.GetWeight())})
+	}
+	variants, err := module.NormalizeVariants(variants)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	url, err := s.Storage.SetURLVariants(ctx, getUserByMD(ctx), in.GetShort(), variants)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.url(url), nil
+}
+
+// checkPassword сверяет пароль защищенной ссылки из метаданных с ограничением числа неудач по ссылке
+func (s *ShortenerServer) checkPassword(ctx context.Context, url domain.URL, now time.Time) error {
+	var password string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("password"); len(values) > 0 {
+			password = values[0]
+		}
+	}
+	if password == "" {
+		return status.Error(codes.Unauthenticated, "password required")
+	}
+	if _, ok := s.unlocks.Allow(url.Short, now); !ok {
+		return status.Error(codes.ResourceExhausted, "too many password attempts")
+	}
+	if !module.CheckPassword(url.Password, password) {
+		return status.Error(codes.PermissionDenied, "wrong password")
+	}
+	s.unlocks.Reset(url.Short)
+	return nil
+}
+
+// url переводит ссылку в сообщение pb.URL. Нулевые метки времени не заполняются
+func (s *ShortenerServer) url(url domain.URL) *pb.URL {
+	res := &pb.URL{Short: s.baseURL + "/" + url.Short, Long: url.Long, Title: url.Title, Tags: url.Tags, Folder: url.Folder,
+		Preview: url.Preview, Protected: url.Protected(), MaxClicks: int32(url.MaxClicks), ClicksUsed: int32(url.Uses)}
+	if !url.CreatedAt.IsZero() {
+		res.CreatedAt = timestamppb.New(url.CreatedAt)
+	}
+	if !url.UpdatedAt.IsZero() {
+		res.UpdatedAt = timestamppb.New(url.UpdatedAt)
+	}
+	if !url.NotBefore.IsZero() {
+		res.NotBefore = timestamppb.New(url.NotBefore)
+	}
+	if !url.ExpiresAt.IsZero() {
+		res.ExpiresAt = timestamppb.New(url.ExpiresAt)
+	}
+	res.FallbackUrl = url.Fallback
+	for _, rule := range url.Rules {
+		res.Rules = append(res.Rules, &pb.RedirectRule{Platform: rule.Platform, Language: rule.Language,
+			Country: rule.Country, Long: rule.Long})
+	}
+	for _, variant := range url.Variants {
+		res.Variants = append(res.Variants, &pb.Variant{Name: variant.Name, Long: variant.Long, Weight: int32(variant.Weight)})
+	}
+	if url.Deleted && !url.DeletedAt.IsZero() {
+		res.DeletedAt = timestamppb.New(url.DeletedAt)
+	}
+	return res
+}
+
+// GetURLRevisions возвращает историю изменений ссылки текущего пользователя. Для чужих и несуществующих ссылок - NotFound
+func (s *ShortenerServer) GetURLRevisions(ctx context.Context, in *pb.Short) (*pb.ResponseURLRevisions, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	url, err := s.Storage.GetURL(ctx, in.GetShort())
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && url.User != getUserByMD(ctx)) {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	revisions, err := s.Storage.GetURLRevisions(ctx, url.Short)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &pb.ResponseURLRevisions{}
+	for _, rev := range revisions {
+		response.Revisions = append(response.Revisions, &pb.Revision{
+			OldLong:   rev.OldLong,
+			NewLong:   rev.NewLong,
+			ChangedAt: timestamppb.New(rev.ChangedAt),
+		})
 	}
 	return response, nil
 }
@@ -190,44 +559,118 @@ func (s *ShortenerServer) PostBatchURLs(ctx context.Context, in *pb.RequestBatch
 	}
 
 	user := getUserByMD(ctx)
-	urls := make([]domain.URL, 0, len(in.Inputs))
-	response := &pb.ResponseBatchURLs{}
+	longs := make([]string, 0, len(in.Inputs))
+	aliases := make([]string, 0, len(in.Inputs))
+	expires := make([]time.Time, 0, len(in.Inputs))
+	titles := make([]string, 0, len(in.Inputs))
+	tags := make([][]string, 0, len(in.Inputs))
+	passwords := make([]string, 0, len(in.Inputs))
+	now := time.Now()
 	for _, input := range in.Inputs {
-		tmpShort, errInput := module.ShortingURL(input.Long)
-		if errInput != nil {
-			return nil, status.Error(codes.Internal, errInput.Error())
+		expiresAt, err := module.NotAfter(timestampToTime(input.ExpiresAt), timestampToTime(input.NotAfter))
+		if err == nil {
+			expiresAt, err = module.ExpiresAt(input.Ttl, expiresAt, now)
 		}
-		urls = append(urls, domain.URL{
-			Short: tmpShort,
-			Long:  input.Long,
-			User:  user,
-		})
-		response.Outputs = append(response.Outputs, &pb.ResponseBatchURLsOutput{
-			Short:         tmpShort,
-			CorrelationId: input.CorrelationId,
-		})
+		if err == nil {
+			err = module.ValidateSchedule(timestampToTime(input.NotBefore), expiresAt, input.FallbackUrl)
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		title, inputTags, err := module.NormalizeMeta(input.Title, input.Tags)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		password, err := module.HashPassword(input.Password)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err = module.ValidateMaxClicks(int(input.MaxClicks)); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		titles = append(titles, title)
+		tags = append(tags, inputTags)
+		passwords = append(passwords, password)
+		longs = append(longs, input.Long)
+		aliases = append(aliases, input.Alias)
+		expires = append(expires, expiresAt)
+	}
+	shorts, err := s.shortener.ShortBatch(longs, aliases, func(shorts []string) error {
+		urls := make([]domain.URL, 0, len(in.Inputs))
+		for i, input := range in.Inputs {
+			urls = append(urls, domain.URL{
+				Short:     shorts[i],
+				Long:      input.Long,
+				User:      user,
+				ExpiresAt: expires[i],
+				Title:     titles[i],
+				Tags:      tags[i],
+				Preview:   input.Preview,
+				Password:  passwords[i],
+				MaxClicks: int(input.MaxClicks),
+				NotBefore: timestampToTime(input.NotBefore),
+				Fallback:  input.FallbackUrl,
+			})
+		}
+		return s.Storage.SetBatchURLs(ctx, urls)
+	})
+	if module.IsInputError(err) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, module.ErrAliasTaken) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
 	}
-
-	err := s.Storage.SetBatchURLs(ctx, urls)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	response := &pb.ResponseBatchURLs{}
+	for i, input := range in.Inputs {
+		response.Outputs = append(response.Outputs, &pb.ResponseBatchURLsOutput{
+			Short:         shorts[i],
+			CorrelationId: input.CorrelationId,
+		})
+	}
 	return response, nil
 }
 
-// DeleteBatchByUser Пакетное удаление ссылок пользователя.
-func (s *ShortenerServer) DeleteBatchByUser(ctx context.Context, in *pb.RequestDeleteBatch) (*emptypb.Empty, error) {
+// DeleteBatchByUser Пакетное удаление ссылок пользователя. Возвращает идентификатор задачи удаления
+func (s *ShortenerServer) DeleteBatchByUser(ctx context.Context, in *pb.RequestDeleteBatch) (*pb.JobID, error) {
 
 	if len(in.Shorts) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "No urls for delete")
 	}
-	shorts := make([]string, len(in.Shorts))
+	shorts := make([]string, 0, len(in.Shorts))
 	for _, short := range in.Shorts {
 		shorts = append(shorts, short.Short)
 	}
 	user := getUserByMD(ctx)
-	s.Storage.DeleteURLs(ctx, user, shorts)
-	return &emptypb.Empty{}, nil
+	jobID, err := s.Storage.DeleteURLs(ctx, user, shorts)
+	if err != nil {
+		s.logger.Error("DeleteURLs error", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.JobID{Id: jobID}, nil
+}
+
+// GetDeleteJob возвращает состояние задачи удаления пользователя. Для чужих задач - NotFound
+func (s *ShortenerServer) GetDeleteJob(ctx context.Context, in *pb.JobID) (*pb.DeleteJob, error) {
+	job, err := s.Storage.GetDeleteJob(ctx, in.Id)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && job.User != getUserByMD(ctx)) {
+		return nil, status.Error(codes.NotFound, "job not found")
+	}
+	if err != nil {
+		s.logger.Error("GetDeleteJob error", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.DeleteJob{
+		Id:        job.ID,
+		Status:    string(job.Status),
+		Deleted:   job.Deleted,
+		Skipped:   job.Skipped,
+		Error:     job.Error,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+		UpdatedAt: timestamppb.New(job.UpdatedAt),
+	}, nil
 }
 
 // AuthInterceptor проверяет наличие токена и его валидность
@@ -239,6 +682,8 @@ func (s *ShortenerServer) AuthInterceptor(ctx context.Context, req interface{},
 		return handler(ctx, req)
 	case "/yapshrtnr.Shortener/GetURL":
 		return handler(ctx, req)
+	case "/yapshrtnr.Shortener/GetQR":
+		return handler(ctx, req)
 	}
 	var id, token []byte
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
@@ -263,6 +708,36 @@ func (s *ShortenerServer) AuthInterceptor(ctx context.Context, req interface{},
 	return handler(ctx, req)
 }
 
+// clickFromContext собирает переход из метаданных запроса: user-agent, referer и x-real-ip, иначе адрес peer
+func clickFromContext(ctx context.Context, short string) domain.Click {
+	click := domain.Click{Short: short, Time: time.Now().UTC()}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			click.UserAgent = values[0]
+		}
+		if values := md.Get("referer"); len(values) > 0 {
+			click.Referrer = values[0]
+		}
+		if values := md.Get("x-real-ip"); len(values) > 0 {
+			click.IP = values[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && click.IP == "" {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			click.IP = host
+		}
+	}
+	return click
+}
+
+// timestampToTime переводит необязательный Timestamp во время. nil - нулевое время
+func timestampToTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
 // getUserByMD получает id пользователя из метаданных. ошибки уже отловлены на уровне interceptor'a
 func getUserByMD(ctx context.Context) (user string) {
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -2,7 +2,11 @@ package server
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/module"
 	"github.com/Spear5030/yapshrtnr/internal/pb"
 	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
 	"github.com/Spear5030/yapshrtnr/pkg/logger"
@@ -14,9 +18,11 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"log"
 	"net"
 	"testing"
+	"time"
 )
 
 func dialer() func(context.Context, string) (net.Conn, error) {
@@ -24,7 +30,7 @@ func dialer() func(context.Context, string) (net.Conn, error) {
 	cfg, _ := config.New()
 	lg, _ := logger.New(true)
 	_, IPNet, _ := net.ParseCIDR("127.0.0.0/8")
-	srv := New(testStorage.NewMemoryStorage(), lg, cfg.GRPCPort, cfg.BaseURL, cfg.Key, *IPNet)
+	srv := New(testStorage.NewMemoryStorage(), lg, cfg.GRPCPort, cfg.BaseURL, cfg.Key, *IPNet, module.RandomGenerator{}, time.Hour)
 
 	go func() {
 		if err := srv.Server.Serve(listener); err != nil {
@@ -89,3 +95,440 @@ func TestShortenerServer_AuthInterceptor(t *testing.T) {
 	}
 
 }
+
+func TestShortenerServer_GetURLStats(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	short, err := client.PostURL(owner, &pb.Long{Long: "https://google.com", Alias: "grpc-stats"})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = client.GetURL(metadata.AppendToOutgoingContext(ctx, "referer", "https://ref.ru"), short)
+		require.NoError(t, err)
+	}
+
+	stats, err := client.GetURLStats(owner, short)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, stats.Total)
+	require.EqualValues(t, 1, stats.Unique)
+	require.Len(t, stats.TopReferrers, 1)
+	require.Equal(t, "https://ref.ru", stats.TopReferrers[0].Referrer)
+	require.Len(t, stats.Daily, 1)
+	require.EqualValues(t, 2, stats.Daily[0].Clicks)
+
+	cfg, _ := config.New()
+	h := hmac.New(sha256.New, []byte(cfg.Key))
+	h.Write([]byte("67890"))
+	stranger := metadata.AppendToOutgoingContext(ctx, "id", "67890", "token", hex.EncodeToString(h.Sum(nil)))
+	_, err = client.GetURLStats(stranger, short)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetURLStats(ctx, short)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestShortenerServer_GetDeleteJob(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	short, err := client.PostURL(owner, &pb.Long{Long: "https://google.com", Alias: "grpc-job"})
+	require.NoError(t, err)
+
+	jobID, err := client.DeleteBatchByUser(owner, &pb.RequestDeleteBatch{Shorts: []*pb.Short{short, {Short: "grpc-missing"}}})
+	require.NoError(t, err)
+	job, err := client.GetDeleteJob(owner, jobID)
+	require.NoError(t, err)
+	require.Equal(t, "done", job.Status)
+	require.Equal(t, []string{"grpc-job"}, job.Deleted)
+	require.Equal(t, []string{"grpc-missing"}, job.Skipped)
+
+	cfg, _ := config.New()
+	h := hmac.New(sha256.New, []byte(cfg.Key))
+	h.Write([]byte("67890"))
+	stranger := metadata.AppendToOutgoingContext(ctx, "id", "67890", "token", hex.EncodeToString(h.Sum(nil)))
+	_, err = client.GetDeleteJob(stranger, jobID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestShortenerServer_GetURLsByUser(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	for _, alias := range []string{"grpc-list-b", "grpc-list-a", "grpc-list-c"} {
+		_, err = client.PostURL(owner, &pb.Long{Long: "https://" + alias + ".ru", Alias: alias})
+		require.NoError(t, err)
+	}
+
+	resp, err := client.GetURLsByUser(owner, &pb.RequestGetURLsByUser{Limit: 2, Search: "grpc-list"})
+	require.NoError(t, err)
+	require.Len(t, resp.Urls, 2)
+	require.Equal(t, "https://grpc-list-a.ru", resp.Urls[0].Long)
+	require.NotNil(t, resp.Urls[0].CreatedAt)
+	require.NotEmpty(t, resp.NextCursor)
+
+	resp, err = client.GetURLsByUser(owner, &pb.RequestGetURLsByUser{Limit: 2, Search: "grpc-list", Cursor: resp.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, resp.Urls, 1)
+	require.Equal(t, "https://grpc-list-c.ru", resp.Urls[0].Long)
+	require.Empty(t, resp.NextCursor)
+
+	_, err = client.GetURLsByUser(owner, &pb.RequestGetURLsByUser{Sort: "long"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestShortenerServer_PostURLMeta(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-meta.ru", Alias: "grpc-meta", Title: "Мета",
+		Tags: []string{"Docs", "docs"}})
+	require.NoError(t, err)
+
+	resp, err := client.GetURLsByUser(owner, &pb.RequestGetURLsByUser{Search: "grpc-meta"})
+	require.NoError(t, err)
+	require.Len(t, resp.Urls, 1)
+	require.Equal(t, "Мета", resp.Urls[0].Title)
+	require.Equal(t, []string{"docs"}, resp.Urls[0].Tags)
+	require.NotNil(t, resp.Urls[0].UpdatedAt)
+
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-meta.ru/bad", Tags: []string{"bad tag"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestShortenerServer_Tags(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-tags.ru", Alias: "grpc-tags"})
+	require.NoError(t, err)
+
+	tag, err := client.CreateTag(owner, &pb.Label{Name: "GRPC"})
+	require.NoError(t, err)
+	require.Equal(t, "grpc", tag.Name)
+	_, err = client.CreateTag(owner, &pb.Label{Name: "grpc"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	url, err := client.SetURLTags(owner, &pb.SetURLTagsRequest{Short: "grpc-tags", Tags: []string{"grpc", "api"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"grpc", "api"}, url.Tags)
+	url, err = client.SetURLFolder(owner, &pb.SetURLFolderRequest{Short: "grpc-tags", Folder: "services"})
+	require.NoError(t, err)
+	require.Equal(t, "services", url.Folder)
+
+	tag, err = client.RenameTag(owner, &pb.RenameTagRequest{Name: "grpc", NewName: "rpc"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tag.Links)
+	resp, err := client.GetURLsByUser(owner, &pb.RequestGetURLsByUser{Tags: []string{"rpc"}, Folder: "services"})
+	require.NoError(t, err)
+	require.Len(t, resp.Urls, 1)
+	require.Equal(t, []string{"rpc", "api"}, resp.Urls[0].Tags)
+
+	folders, err := client.ListFolders(owner, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, folders.Labels, 1)
+	_, err = client.DeleteFolder(owner, &pb.Label{Name: "services"})
+	require.NoError(t, err)
+	_, err = client.DeleteTag(owner, &pb.Label{Name: "rpc"})
+	require.NoError(t, err)
+	_, err = client.DeleteTag(owner, &pb.Label{Name: "rpc"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	tags, err := client.ListTags(owner, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, tags.Labels, 1)
+	require.Equal(t, "api", tags.Labels[0].Name)
+
+	_, err = client.SetURLTags(owner, &pb.SetURLTagsRequest{Short: "missing", Tags: []string{"api"}})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestShortenerServer_GetQR(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-qr.ru", Alias: "grpc-qr"})
+	require.NoError(t, err)
+
+	img, err := client.GetQR(ctx, &pb.QRRequest{Short: "grpc-qr"})
+	require.NoError(t, err)
+	require.Equal(t, "image/png", img.ContentType)
+	require.Equal(t, "\x89PNG", string(img.Image[:4]))
+	require.NotEmpty(t, img.Etag)
+
+	margin := int32(0)
+	img, err = client.GetQR(ctx, &pb.QRRequest{Short: "grpc-qr", Format: "svg", Ec: "h", Size: 512, Margin: &margin})
+	require.NoError(t, err)
+	require.Equal(t, "image/svg+xml", img.ContentType)
+	require.Contains(t, string(img.Image), `width="512"`)
+
+	_, err = client.GetQR(ctx, &pb.QRRequest{Short: "grpc-qr", Format: "gif"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GetQR(ctx, &pb.QRRequest{Short: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestShortenerServer_Preview(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-preview.ru", Alias: "grpc-preview", Title: "Preview", Preview: true})
+	require.NoError(t, err)
+
+	resp, err := client.GetURL(ctx, &pb.Short{Short: "grpc-preview"})
+	require.NoError(t, err)
+	require.True(t, resp.Preview)
+	require.Equal(t, "Preview", resp.Title)
+
+	url, err := client.SetURLPreview(owner, &pb.SetURLPreviewRequest{Short: "grpc-preview"})
+	require.NoError(t, err)
+	require.False(t, url.Preview)
+	resp, err = client.GetURL(ctx, &pb.Short{Short: "grpc-preview"})
+	require.NoError(t, err)
+	require.False(t, resp.Preview)
+
+	_, err = client.SetURLPreview(owner, &pb.SetURLPreviewRequest{Short: "missing", Preview: true})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestShortenerServer_Password(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-password.ru", Alias: "grpc-password", Password: "secret"})
+	require.NoError(t, err)
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-password-short.ru", Password: "abc"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetURL(ctx, &pb.Short{Short: "grpc-password"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.GetURL(metadata.AppendToOutgoingContext(ctx, "password", "wrong"), &pb.Short{Short: "grpc-password"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	resp, err := client.GetURL(metadata.AppendToOutgoingContext(ctx, "password", "secret"), &pb.Short{Short: "grpc-password"})
+	require.NoError(t, err)
+	require.Equal(t, "https://grpc-password.ru", resp.Long)
+
+	url, err := client.SetURLPassword(owner, &pb.SetURLPasswordRequest{Short: "grpc-password"})
+	require.NoError(t, err)
+	require.False(t, url.Protected)
+	_, err = client.GetURL(ctx, &pb.Short{Short: "grpc-password"})
+	require.NoError(t, err)
+
+	_, err = client.SetURLPassword(owner, &pb.SetURLPasswordRequest{Short: "grpc-password", Password: "another"})
+	require.NoError(t, err)
+	for i := 0; i < module.PasswordAttempts; i++ {
+		_, err = client.GetURL(metadata.AppendToOutgoingContext(ctx, "password", "wrong"), &pb.Short{Short: "grpc-password"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+	_, err = client.GetURL(metadata.AppendToOutgoingContext(ctx, "password", "another"), &pb.Short{Short: "grpc-password"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestShortenerServer_DeletedProtected(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	short, err := client.PostURL(owner, &pb.Long{Long: "https://grpc-deleted.ru", Alias: "grpc-deleted", Password: "secret"})
+	require.NoError(t, err)
+	_, err = client.DeleteBatchByUser(owner, &pb.RequestDeleteBatch{Shorts: []*pb.Short{short}})
+	require.NoError(t, err)
+
+	// удаленная ссылка не требует пароля и не расходует попытки
+	for i := 0; i <= module.PasswordAttempts; i++ {
+		resp, err := client.GetURL(metadata.AppendToOutgoingContext(ctx, "password", "wrong"), &pb.Short{Short: "grpc-deleted"})
+		require.NoError(t, err)
+		require.True(t, resp.Deleted)
+		require.Empty(t, resp.Long)
+	}
+	resp, err := client.GetURL(ctx, &pb.Short{Short: "grpc-deleted"})
+	require.NoError(t, err)
+	require.True(t, resp.Deleted)
+}
+
+func TestShortenerServer_MaxClicks(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-clicks.ru", Alias: "grpc-clicks", MaxClicks: 2})
+	require.NoError(t, err)
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-clicks-wrong.ru", MaxClicks: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.GetURL(ctx, &pb.Short{Short: "grpc-clicks"})
+		require.NoError(t, err)
+		require.Equal(t, "https://grpc-clicks.ru", resp.Long)
+	}
+	_, err = client.GetURL(ctx, &pb.Short{Short: "grpc-clicks"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetQR(ctx, &pb.QRRequest{Short: "grpc-clicks"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestShortenerServer_Schedule(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	launch := timestamppb.New(time.Now().Add(time.Hour))
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-soon.ru", Alias: "grpc-soon", NotBefore: launch})
+	require.NoError(t, err)
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-fallback.ru", Alias: "grpc-fallback", NotBefore: launch,
+		FallbackUrl: "https://grpc-fallback.ru/wait"})
+	require.NoError(t, err)
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-schedule.ru", FallbackUrl: "https://grpc-schedule.ru/wait"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetURL(ctx, &pb.Short{Short: "grpc-soon"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	resp, err := client.GetURL(ctx, &pb.Short{Short: "grpc-fallback"})
+	require.NoError(t, err)
+	require.Equal(t, "https://grpc-fallback.ru/wait", resp.Long)
+	require.True(t, launch.AsTime().Equal(resp.NotBefore.AsTime()))
+
+	end := timestamppb.New(time.Now().Add(2 * time.Hour).Truncate(time.Second))
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-until.ru", Alias: "grpc-until", NotAfter: end})
+	require.NoError(t, err)
+	url, err := client.SetURLPreview(owner, &pb.SetURLPreviewRequest{Short: "grpc-until"})
+	require.NoError(t, err)
+	require.True(t, end.AsTime().Equal(url.ExpiresAt.AsTime()))
+}
+
+func TestShortenerServer_Rules(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-rules.ru", Alias: "grpc-rules"})
+	require.NoError(t, err)
+
+	url, err := client.SetURLRules(owner, &pb.SetURLRulesRequest{Short: "grpc-rules", Rules: []*pb.RedirectRule{
+		{Platform: "Android", Long: "https://play.google.com/store/apps/details?id=app"},
+		{Country: "fr", Language: "fr", Long: "https://grpc-rules.fr"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, url.Rules, 2)
+	require.Equal(t, "android", url.Rules[0].Platform)
+	require.Equal(t, "FR", url.Rules[1].Country)
+
+	_, err = client.SetURLRules(owner, &pb.SetURLRulesRequest{Short: "grpc-rules", Rules: []*pb.RedirectRule{
+		{Long: "https://grpc-rules.ru/any"}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.SetURLRules(owner, &pb.SetURLRulesRequest{Short: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	url, err = client.SetURLRules(owner, &pb.SetURLRulesRequest{Short: "grpc-rules"})
+	require.NoError(t, err)
+	require.Empty(t, url.Rules)
+}
+
+func TestShortenerServer_Variants(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithContextDialer(dialer()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewShortenerClient(conn)
+	owner := metadata.AppendToOutgoingContext(ctx, "id", "12345",
+		"token", "f5d1cf1a06e1c9e562ea9203c56bf9556012b4cc56d26d19f2d9537e2af64c6d")
+	_, err = client.PostURL(owner, &pb.Long{Long: "https://grpc-variants.ru", Alias: "grpc-variants"})
+	require.NoError(t, err)
+
+	url, err := client.SetURLVariants(owner, &pb.SetURLVariantsRequest{Short: "grpc-variants", Variants: []*pb.Variant{
+		{Name: "A", Long: "https://grpc-variants.ru/a", Weight: 1},
+		{Name: "b", Long: "https://grpc-variants.ru/b", Weight: 1},
+	}})
+	require.NoError(t, err)
+	require.Len(t, url.Variants, 2)
+	require.Equal(t, "a", url.Variants[0].Name)
+
+	first, err := client.GetURL(owner, &pb.Short{Short: "grpc-variants"})
+	require.NoError(t, err)
+	require.Contains(t, []string{"a", "b"}, first.Variant)
+	require.Equal(t, "https://grpc-variants.ru/"+first.Variant, first.Long)
+	second, err := client.GetURL(owner, &pb.Short{Short: "grpc-variants"})
+	require.NoError(t, err)
+	require.Equal(t, first.Long, second.Long)
+
+	stats, err := client.GetURLStats(owner, &pb.Short{Short: "grpc-variants"})
+	require.NoError(t, err)
+	require.Len(t, stats.Variants, 1)
+	require.Equal(t, first.Variant, stats.Variants[0].Variant)
+	require.Equal(t, int64(2), stats.Variants[0].Clicks)
+
+	_, err = client.SetURLVariants(owner, &pb.SetURLVariantsRequest{Short: "grpc-variants", Variants: []*pb.Variant{
+		{Name: "a", Long: "https://grpc-variants.ru/a", Weight: 1}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.SetURLVariants(owner, &pb.SetURLVariantsRequest{Short: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	url, err = client.SetURLVariants(owner, &pb.SetURLVariantsRequest{Short: "grpc-variants"})
+	require.NoError(t, err)
+	require.Empty(t, url.Variants)
+}
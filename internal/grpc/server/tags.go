@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	"github.com/Spear5030/yapshrtnr/internal/pb"
+)
+
+// label переводит метку или папку в сообщение pb.Label
+func label(name string, links int, createdAt time.Time) *pb.Label {
+	return &pb.Label{Name: name, Links: int64(links), CreatedAt: timestamppb.New(createdAt)}
+}
+
+// labelError переводит ошибку хранилища в статус gRPC
+func (s *ShortenerServer) labelError(method string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrTagExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	s.logger.Error(method+" error", zap.Error(err))
+	return status.Error(codes.Internal, err.Error())
+}
+
+// ListTags возвращает метки текущего пользователя с количеством ссылок
+func (s *ShortenerServer) ListTags(ctx context.Context, in *emptypb.Empty) (*pb.ResponseLabels, error) {
+	tags, err := s.Storage.ListTags(ctx, getUserByMD(ctx))
+	if err != nil {
+		return nil, s.labelError("ListTags", err)
+	}
+	response := &pb.ResponseLabels{}
+	for _, tag := range tags {
+		response.Labels = append(response.Labels, label(tag.Name, tag.Links, tag.CreatedAt))
+	}
+	return response, nil
+}
+
+// CreateTag создает метку текущего пользователя. Если метка уже есть - AlreadyExists
+func (s *ShortenerServer) CreateTag(ctx context.Context, in *pb.Label) (*pb.Label, error) {
+	name, err := module.NormalizeTag(in.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	tag, err := s.Storage.CreateTag(ctx, getUserByMD(ctx), name)
+	if err != nil {
+		return nil, s.labelError("CreateTag", err)
+	}
+	return label(tag.Name, tag.Links, tag.CreatedAt), nil
+}
+
+// RenameTag переименовывает метку текущего пользователя вместе с ее ссылками.
+// Если метки нет - NotFound, если новое имя занято - AlreadyExists
+func (s *ShortenerServer) RenameTag(ctx context.Context, in *pb.RenameTagRequest) (*pb.Label, error) {
+	newName, err := module.NormalizeTag(in.GetNewName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	tag, err := s.Storage.RenameTag(ctx, getUserByMD(ctx), in.GetName(), newName)
+	if err != nil {
+		return nil, s.labelError("RenameTag", err)
+	}
+	return label(tag.Name, tag.Links, tag.CreatedAt), nil
+}
+
+// DeleteTag удаляет метку текущего пользователя и снимает ее со ссылок. Если метки нет - NotFound
+func (s *ShortenerServer) DeleteTag(ctx context.Context, in *pb.Label) (*emptypb.Empty, error) {
+	if err := s.Storage.DeleteTag(ctx, getUserByMD(ctx), in.GetName()); err != nil {
+		return nil, s.labelError("DeleteTag", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListFolders возвращает папки текущего пользователя с количеством ссылок
+func (s *ShortenerServer) ListFolders(ctx context.Context, in *emptypb.Empty) (*pb.ResponseLabels, error) {
+	folders, err := s.Storage.ListFolders(ctx, getUserByMD(ctx))
+	if err != nil {
+		return nil, s.labelError("ListFolders", err)
+	}
+	response := &pb.ResponseLabels{}
+	for _, folder := range folders {
+		response.Labels = append(response.Labels, label(folder.Name, folder.Links, folder.CreatedAt))
+	}
+	return response, nil
+}
+
+// DeleteFolder удаляет папку текущего пользователя, ссылки из нее остаются вне папок. Если папки нет - NotFound
+func (s *ShortenerServer) DeleteFolder(ctx context.Context, in *pb.Label) (*emptypb.Empty, error) {
+	if err := s.Storage.DeleteFolder(ctx, getUserByMD(ctx), in.GetName()); err != nil {
+		return nil, s.labelError("DeleteFolder", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// SetURLTags заменяет метки ссылки текущего пользователя, новые метки создаются.
+// Для чужих, удаленных и несуществующих ссылок - NotFound
+func (s *ShortenerServer) SetURLTags(ctx context.Context, in *pb.SetURLTagsRequest) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	_, tags, err := module.NormalizeMeta("", in.GetTags())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	url, err := s.Storage.SetURLTags(ctx, getUserByMD(ctx), in.GetShort(), tags)
+	if err != nil {
+		return nil, s.labelError("SetURLTags", err)
+	}
+	return s.url(url), nil
+}
+
+// SetURLFolder переносит ссылку текущего пользователя в папку, пустое имя - вне папок. Новая папка создается.
+// Для чужих, удаленных и несуществующих ссылок - NotFound
+func (s *ShortenerServer) SetURLFolder(ctx context.Context, in *pb.SetURLFolderRequest) (*pb.URL, error) {
+	if len(in.GetShort()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing short url")
+	}
+	folder, err := module.NormalizeFolder(in.GetFolder())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	url, err := s.Storage.SetURLFolder(ctx, getUserByMD(ctx), in.GetShort(), folder)
+	if err != nil {
+		return nil, s.labelError("SetURLFolder", err)
+	}
+	return s.url(url), nil
+}
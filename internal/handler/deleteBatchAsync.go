@@ -2,11 +2,33 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
 )
 
+type deleteJobID struct {
+	JobID string `json:"job_id"`
+}
+
+type deleteJobResult struct {
+	JobID     string    `json:"job_id"`
+	Status    string    `json:"status"`
+	Deleted   []string  `json:"deleted"`
+	Skipped   []string  `json:"skipped"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // DeleteBatchByUser Пакетное удаление ссылок пользователя.
+// Возвращает 202 с идентификатором задачи, состояние которой отдает GetDeleteJob
 func (h *Handler) DeleteBatchByUser(w http.ResponseWriter, r *http.Request) {
 	cookie, err := r.Cookie("id")
 	if err != nil {
@@ -23,10 +45,59 @@ func (h *Handler) DeleteBatchByUser(w http.ResponseWriter, r *http.Request) {
 		var shorts []string
 		if err = json.Unmarshal(b, &shorts); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 		user := cookie.Value
-		h.Storage.DeleteURLs(r.Context(), user, shorts)
+		jobID, err := h.Storage.DeleteURLs(r.Context(), user, shorts)
+		if err != nil {
+			h.logger.Error("DeleteURLs error", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resJSON, err := json.Marshal(deleteJobID{JobID: jobID})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Location", "/api/user/jobs/"+jobID)
 		w.WriteHeader(http.StatusAccepted)
+		w.Write(resJSON)
+		return
 	}
 	w.WriteHeader(http.StatusBadRequest)
 }
+
+// GetDeleteJob возвращает JSON с состоянием задачи удаления текущего пользователя:
+// queued, running, done или failed, а после выполнения - удаленные и пропущенные ссылки. Для чужих задач - 404
+func (h *Handler) GetDeleteJob(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	job, err := h.Storage.GetDeleteJob(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && job.User != user) {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("GetDeleteJob error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res := deleteJobResult{
+		JobID:     job.ID,
+		Status:    string(job.Status),
+		Deleted:   append(make([]string, 0, len(job.Deleted)), job.Deleted...),
+		Skipped:   append(make([]string, 0, len(job.Skipped)), job.Skipped...),
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(resJSON)
+}
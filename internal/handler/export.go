@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// exportPageSize ссылок в одной выборке из хранилища при выгрузке
+const exportPageSize = 1000
+
+var exportHeader = []string{"short_url", "original_url", "title", "tags", "folder", "created_at", "updated_at",
+	"deleted", "deleted_at", "expires_at", "clicks"}
+
+type exportLink struct {
+	Short     string     `json:"short_url"`
+	Long      string     `json:"original_url"`
+	Title     string     `json:"title"`
+	Tags      []string   `json:"tags"`
+	Folder    string     `json:"folder"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	Deleted   bool       `json:"deleted"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Clicks    int        `json:"clicks"`
+}
+
+// exportWriter пишет выгружаемые ссылки в одном из форматов
+type exportWriter interface {
+	write(link exportLink) error
+	close() error
+}
+
+// ExportURLs выгружает все ссылки текущего пользователя, включая удаленные и истекшие, в формате
+// csv, ndjson или json (по умолчанию). Ссылки читаются из хранилища страницами и сразу пишутся в ответ
+func (h *Handler) ExportURLs(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	var contentType string
+	switch format {
+	case "csv":
+		contentType = "text/csv; charset=utf-8"
+	case "ndjson":
+		contentType = "application/x-ndjson"
+	case "json":
+		contentType = "application/json"
+	default:
+		http.Error(w, "format must be csv, ndjson or json", http.StatusBadRequest)
+		return
+	}
+	// первая страница читается до заголовков, чтобы ошибка хранилища вернулась кодом ответа
+	page, err := h.Storage.ListURLs(r.Context(), user, domain.ListQuery{Limit: exportPageSize})
+	if err != nil {
+		h.logger.Error("ExportURLs ListURLs error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="links.`+format+`"`)
+
+	buf := bufio.NewWriter(w)
+	var out exportWriter
+	switch format {
+	case "csv":
+		out = newCSVExport(buf)
+	case "ndjson":
+		out = &ndjsonExport{enc: json.NewEncoder(buf)}
+	default:
+		out = &jsonExport{buf: buf}
+	}
+	for {
+		for _, info := range page {
+			if err = out.write(h.exportLink(info)); err != nil {
+				h.logger.Info("ExportURLs write error", zap.Error(err))
+				return
+			}
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+		query := domain.ListQuery{After: page[len(page)-1].Cursor(), Limit: exportPageSize}
+		if page, err = h.Storage.ListURLs(r.Context(), user, query); err != nil {
+			// ответ уже начат, поэтому выгрузка просто обрывается
+			h.logger.Error("ExportURLs ListURLs error", zap.Error(err))
+			return
+		}
+	}
+	if err = out.close(); err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		h.logger.Info("ExportURLs write error", zap.Error(err))
+	}
+}
+
+func (h *Handler) exportLink(info domain.LinkInfo) exportLink {
+	return exportLink{
+		Short:     h.BaseURL + "/" + info.Short,
+		Long:      info.Long,
+		Title:     info.Title,
+		Tags:      append(make([]string, 0, len(info.Tags)), info.Tags...),
+		Folder:    info.Folder,
+		CreatedAt: optionalTime(info.CreatedAt),
+		UpdatedAt: optionalTime(info.UpdatedAt),
+		Deleted:   info.Deleted,
+		DeletedAt: optionalTime(info.DeletedAt),
+		ExpiresAt: optionalTime(info.ExpiresAt),
+		Clicks:    info.Clicks,
+	}
+}
+
+// optionalTime переводит нулевое время в nil, чтобы в JSON было null
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+type csvExport struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVExport(buf *bufio.Writer) *csvExport {
+	return &csvExport{w: csv.NewWriter(buf)}
+}
+
+// writeHeader пишет заголовок перед первой строкой, а для пустой выгрузки - при закрытии
+func (e *csvExport) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.w.Write(exportHeader)
+}
+
+func (e *csvExport) write(link exportLink) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.w.Write([]string{link.Short, link.Long, link.Title, strings.Join(link.Tags, ";"), link.Folder,
+		csvTime(link.CreatedAt), csvTime(link.UpdatedAt), strconv.FormatBool(link.Deleted), csvTime(link.DeletedAt), csvTime(link.ExpiresAt),
+		strconv.Itoa(link.Clicks)})
+}
+
+func (e *csvExport) close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type ndjsonExport struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonExport) write(link exportLink) error {
+	return e.enc.Encode(link)
+}
+
+func (e *ndjsonExport) close() error {
+	return nil
+}
+
+// jsonExport пишет массив JSON поэлементно, не собирая его в памяти
+type jsonExport struct {
+	buf   *bufio.Writer
+	count int
+}
+
+func (e *jsonExport) write(link exportLink) error {
+	sep := ","
+	if e.count == 0 {
+		sep = "["
+	}
+	e.count++
+	if _, err := e.buf.WriteString(sep); err != nil {
+		return err
+	}
+	b, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	_, err = e.buf.Write(b)
+	return err
+}
+
+func (e *jsonExport) close() error {
+	if e.count == 0 {
+		_, err := e.buf.WriteString("[]")
+		return err
+	}
+	_, err := e.buf.WriteString("]")
+	return err
+}
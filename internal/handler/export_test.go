@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_ExportURLs(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "cccccccc", Long: "http://c.ru", User: "user2"}))
+	h.Storage.DeleteURLs(ctx, "user1", []string{"bbbbbbbb"})
+	h.Storage.RecordClick(ctx, domain.Click{Short: "aaaaaaaa"})
+
+	export := func(user, format string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/user/urls/export?format="+format, nil)
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		h.ExportURLs(w, req)
+		return w
+	}
+
+	w := export("user1", "json")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var links []exportLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &links))
+	require.Len(t, links, 2)
+	require.Equal(t, "http://localhost:8080/aaaaaaaa", links[0].Short)
+	require.Equal(t, 1, links[0].Clicks)
+	require.NotNil(t, links[0].CreatedAt)
+	require.Nil(t, links[0].DeletedAt)
+	require.True(t, links[1].Deleted)
+	require.NotNil(t, links[1].DeletedAt)
+
+	w = export("user1", "csv")
+	require.Equal(t, http.StatusOK, w.Code)
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, exportHeader, records[0])
+	require.Equal(t, "http://a.ru", records[1][1])
+	require.Equal(t, "true", records[2][7])
+
+	w = export("user1", "ndjson")
+	require.Equal(t, http.StatusOK, w.Code)
+	scanner := bufio.NewScanner(w.Body)
+	lines := 0
+	for scanner.Scan() {
+		var link exportLink
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &link))
+		lines++
+	}
+	require.Equal(t, 2, lines)
+
+	require.Equal(t, "[]", export("user3", "json").Body.String())
+	require.Equal(t, http.StatusBadRequest, export("user1", "xml").Code)
+}
+
+func TestHandler_ExportURLsPages(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	for i := 0; i < exportPageSize+5; i++ {
+		require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: fmt.Sprintf("s%05d", i), Long: fmt.Sprintf("http://%d.ru", i), User: "user1"}))
+	}
+	req := httptest.NewRequest("GET", "/api/user/urls/export", nil)
+	req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+	w := httptest.NewRecorder()
+	h.ExportURLs(w, req)
+	var links []exportLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &links))
+	require.Len(t, links, exportPageSize+5)
+	require.Equal(t, "http://localhost:8080/s01004", links[len(links)-1].Short)
+}
@@ -14,11 +14,15 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
 	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/geoip"
 	"github.com/Spear5030/yapshrtnr/internal/module"
 	pckgstorage "github.com/Spear5030/yapshrtnr/internal/storage"
 )
@@ -30,34 +34,104 @@ type Handler struct {
 	BaseURL       string
 	SecretKey     string
 	trustedSubnet net.IPNet
+	shortener     *module.Shortener
+	gracePeriod   time.Duration // срок восстановления удаленных ссылок
+	unlocks       *module.Throttle
+	geo           *geoip.DB // nil - страна посетителя неизвестна, правила по стране не срабатывают
 }
 
 type storage interface {
-	SetURL(ctx context.Context, user, short, long string) error
-	GetURL(ctx context.Context, short string) (string, bool)
-	GetURLsByUser(ctx context.Context, user string) (urls map[string]string)
+	SetURL(ctx context.Context, url domain.URL) error
+	GetURL(ctx context.Context, short string) (domain.URL, error)
 	SetBatchURLs(ctx context.Context, urls []domain.URL) error
-	DeleteURLs(ctx context.Context, user string, shorts []string)
+	DeleteURLs(ctx context.Context, user string, shorts []string) (string, error)
+	GetDeleteJob(ctx context.Context, id string) (domain.DeleteJob, error)
+	ListURLs(ctx context.Context, user string, query domain.ListQuery) ([]domain.LinkInfo, error)
 	GetUsersCount(ctx context.Context) (int, error)
 	GetUrlsCount(ctx context.Context) (int, error)
+	RecordClick(ctx context.Context, click domain.Click)
+	GetClickStats(ctx context.Context, short string) (domain.ClickStats, error)
+	UpdateURL(ctx context.Context, user, short, long string) (domain.URL, error)
+	GetURLRevisions(ctx context.Context, short string) ([]domain.Revision, error)
+	RestoreURL(ctx context.Context, user, short string, deletedAfter time.Time) (domain.URL, error)
+	ListTags(ctx context.Context, user string) ([]domain.Tag, error)
+	CreateTag(ctx context.Context, user, name string) (domain.Tag, error)
+	RenameTag(ctx context.Context, user, name, newName string) (domain.Tag, error)
+	DeleteTag(ctx context.Context, user, name string) error
+	ListFolders(ctx context.Context, user string) ([]domain.Folder, error)
+	DeleteFolder(ctx context.Context, user, name string) error
+	SetURLTags(ctx context.Context, user, short string, tags []string) (domain.URL, error)
+	SetURLFolder(ctx context.Context, user, short, folder string) (domain.URL, error)
+	SetURLPreview(ctx context.Context, user, short string, preview bool) (domain.URL, error)
+	SetURLPassword(ctx context.Context, user, short, hash string) (domain.URL, error)
+	SetURLRules(ctx context.Context, user, short string, rules []domain.RedirectRule) (domain.URL, error)
+	SetURLVariants(ctx context.Context, user, short string, variants []domain.Variant) (domain.URL, error)
+	UseURL(ctx context.Context, short string) (domain.URL, error)
 }
 
 type link struct {
-	Short string `json:"short_url"`
-	Long  string `json:"original_url"`
+	Short     string                `json:"short_url"`
+	Long      string                `json:"original_url"`
+	Title     string                `json:"title,omitempty"`
+	Tags      []string              `json:"tags,omitempty"`
+	Folder    string                `json:"folder,omitempty"`
+	Preview   bool                  `json:"preview,omitempty"`
+	Protected bool                  `json:"protected,omitempty"` // переход требует пароль
+	MaxClicks int                   `json:"max_clicks,omitempty"`
+	Used      int                   `json:"clicks_used,omitempty"` // переходов учтено в пределах max_clicks
+	NotBefore *time.Time            `json:"not_before,omitempty"`
+	ExpiresAt *time.Time            `json:"expires_at,omitempty"` // конец срока действия, в том числе заданный как not_after
+	Fallback  string                `json:"fallback_url,omitempty"`
+	Rules     []domain.RedirectRule `json:"rules,omitempty"`
+	Variants  []domain.Variant      `json:"variants,omitempty"`
+	CreatedAt *time.Time            `json:"created_at,omitempty"`
+	UpdatedAt *time.Time            `json:"updated_at,omitempty"`
+	DeletedAt *time.Time            `json:"deleted_at,omitempty"`
 }
 
 type input struct {
+	URL       string    `json:"url"`
+	Alias     string    `json:"alias,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Preview   bool      `json:"preview,omitempty"`      // показывать страницу предпросмотра вместо перехода
+	Password  string    `json:"password,omitempty"`     // пароль для перехода, 4-72 байта
+	MaxClicks int       `json:"max_clicks,omitempty"`   // число переходов, 0 - без ограничения
+	TTL       int64     `json:"ttl,omitempty"`          // срок действия в секундах
+	ExpiresAt time.Time `json:"expires_at,omitempty"`   // момент истечения в RFC3339
+	NotBefore time.Time `json:"not_before,omitempty"`   // начало окна активности в RFC3339
+	NotAfter  time.Time `json:"not_after,omitempty"`    // конец окна активности, другое имя expires_at
+	Fallback  string    `json:"fallback_url,omitempty"` // куда вести до начала окна вместо страницы "скоро"
+}
+
+type patchInput struct {
 	URL string `json:"url"`
 }
 
+type revision struct {
+	OldLong   string    `json:"old_url"`
+	NewLong   string    `json:"new_url"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
 type result struct {
 	Result string `json:"result"`
 }
 
 type batchInput struct {
-	Long          string `json:"original_url"`
-	CorrelationID string `json:"correlation_id"`
+	Long          string    `json:"original_url"`
+	CorrelationID string    `json:"correlation_id"`
+	Alias         string    `json:"alias,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+	Preview       bool      `json:"preview,omitempty"`
+	Password      string    `json:"password,omitempty"`
+	MaxClicks     int       `json:"max_clicks,omitempty"`
+	TTL           int64     `json:"ttl,omitempty"`
+	ExpiresAt     time.Time `json:"expires_at,omitempty"`
+	NotBefore     time.Time `json:"not_before,omitempty"`
+	NotAfter      time.Time `json:"not_after,omitempty"`
+	Fallback      string    `json:"fallback_url,omitempty"`
 }
 
 type batchTmp struct {
@@ -76,14 +150,44 @@ type statsResult struct {
 	Users int `json:"users"`
 }
 
-// New возвращает Handler
-func New(logger *zap.Logger, storage storage, baseURL string, key string, trustedSubnet net.IPNet) *Handler {
+type dailyClicks struct {
+	Date   string `json:"date"`
+	Clicks int    `json:"clicks"`
+}
+
+type variantClicks struct {
+	Variant string `json:"variant"`
+	Clicks  int    `json:"clicks"`
+	Unique  int    `json:"unique"`
+}
+
+type referrerClicks struct {
+	Referrer string `json:"referrer"`
+	Clicks   int    `json:"clicks"`
+}
+
+type urlStatsResult struct {
+	Short        string           `json:"short_url"`
+	Total        int              `json:"total"`
+	Unique       int              `json:"unique"`
+	TopReferrers []referrerClicks `json:"top_referrers"`
+	Daily        []dailyClicks    `json:"daily"`
+	Variants     []variantClicks  `json:"variants,omitempty"`
+}
+
+// New возвращает Handler. geo может быть nil
+func New(logger *zap.Logger, storage storage, baseURL string, key string, trustedSubnet net.IPNet, gen module.Generator,
+	gracePeriod time.Duration, geo *geoip.DB) *Handler {
 	return &Handler{
 		logger:        logger,
 		Storage:       storage,
 		BaseURL:       baseURL,
 		SecretKey:     key,
 		trustedSubnet: trustedSubnet,
+		shortener:     module.NewShortener(gen),
+		gracePeriod:   gracePeriod,
+		unlocks:       module.NewThrottle(module.PasswordAttempts, module.PasswordWindow),
+		geo:           geo,
 	}
 }
 
@@ -96,18 +200,21 @@ func (h *Handler) PostURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	h.logger.Info("will shorting URL", zap.String("long", string(b)))
-	short, err := module.ShortingURL(string(b))
-	if err != nil {
-		h.logger.Info("Error shorting", zap.String("err", err.Error()))
-		http.Error(w, err.Error(), http.StatusBadRequest)
-	}
 	user, err := getUserIDFROMCookie(r)
 	if err != nil {
 		h.logger.Info("Error getUserID", zap.String("err", err.Error()))
 		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	err = h.Storage.SetURL(r.Context(), user, short, string(b))
+	short, err := h.shortener.Short(string(b), "", func(short string) error {
+		return h.Storage.SetURL(r.Context(), domain.URL{Short: short, Long: string(b), User: user})
+	})
+	if errors.Is(err, module.ErrWrongURL) {
+		h.logger.Info("Error shorting", zap.String("err", err.Error()))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	var de *pckgstorage.DuplicationError
 	status := http.StatusCreated
@@ -131,20 +238,118 @@ func (h *Handler) PostURL(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetURL получает сокращенную ссылку из URL. Возвращает полную ссылку и Redirect
+// GetURL получает сокращенную ссылку из URL. Возвращает полную ссылку и Redirect.
+// Для защищенных ссылок без cookie разблокировки - форма пароля, для ссылок с предпросмотром без подписанного токена continue -
+// страница предпросмотра. До начала окна активности - запасной URL или страница "скоро".
+// Адрес перехода выбирается по правилам ссылки, если не подошло ни одно - по вариантам A/B-разделения
+// с учетом варианта в статистике, без вариантов - полная ссылка.
+// Для ссылок с max_clicks переход учитывается атомарно в хранилище.
+// Для удаленных, истекших и исчерпавших переходы ссылок - 410
 func (h *Handler) GetURL(w http.ResponseWriter, r *http.Request) {
 	short := strings.TrimLeft(r.URL.Path, "/")
-	v, deleted := h.Storage.GetURL(r.Context(), short)
-	if deleted {
+	url, err := h.Storage.GetURL(r.Context(), short)
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "Wrong ID", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("GetURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	if url.Deleted || url.Expired(now) || url.Exhausted() {
 		w.WriteHeader(http.StatusGone)
 		return
 	}
-	if len(v) > 0 {
-		w.Header().Set("Location", v)
-		w.WriteHeader(http.StatusTemporaryRedirect)
+	if url.Pending(now) {
+		h.comingSoon(w, r, url, now)
+		return
+	}
+	if url.Protected() && !h.unlocked(r, url) {
+		h.renderUnlock(w, http.StatusOK, url, "")
 		return
 	}
-	http.Error(w, "Wrong ID", http.StatusBadRequest)
+	long, variant := h.destination(w, r, url)
+	url.Long = long
+	if url.Preview && !h.continued(r, short) {
+		h.renderPreview(w, url)
+		return
+	}
+	// переход учитывается только при перенаправлении: форма пароля и предпросмотр его не расходуют
+	if url.MaxClicks > 0 {
+		_, err = h.Storage.UseURL(r.Context(), short)
+		if errors.Is(err, domain.ErrClicksExhausted) || errors.Is(err, domain.ErrNotFound) {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		if err != nil {
+			h.logger.Error("UseURL error", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	// контекст запроса завершится раньше записи перехода
+	h.Storage.RecordClick(context.Background(), domain.Click{
+		Short:     short,
+		Time:      time.Now().UTC(),
+		Referrer:  r.Referer(),
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r),
+		Variant:   variant,
+	})
+	w.Header().Set("Location", url.Long)
+	w.WriteHeader(http.StatusTemporaryRedirect)
+}
+
+// GetURLStats возвращает JSON со статистикой переходов по ссылке текущего пользователя:
+// всего, уникальных посетителей, основные источники, по дням и по вариантам A/B-разделения.
+// Для чужих и несуществующих ссылок - 404
+func (h *Handler) GetURLStats(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	short := chi.URLParam(r, "id")
+	url, err := h.Storage.GetURL(r.Context(), short)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && url.User != user) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("GetURLStats GetURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	stats, err := h.Storage.GetClickStats(r.Context(), short)
+	if err != nil {
+		h.logger.Error("GetClickStats error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res := urlStatsResult{
+		Short:        h.BaseURL + "/" + short,
+		Total:        stats.Total,
+		Unique:       stats.Unique,
+		TopReferrers: make([]referrerClicks, 0, len(stats.TopReferrers)),
+		Daily:        make([]dailyClicks, 0, len(stats.Daily)),
+	}
+	for _, referrer := range stats.TopReferrers {
+		res.TopReferrers = append(res.TopReferrers, referrerClicks{Referrer: referrer.Referrer, Clicks: referrer.Clicks})
+	}
+	for _, day := range stats.Daily {
+		res.Daily = append(res.Daily, dailyClicks{Date: day.Date.Format("2006-01-02"), Clicks: day.Clicks})
+	}
+	for _, variant := range stats.Variants {
+		res.Variants = append(res.Variants, variantClicks{Variant: variant.Variant, Clicks: variant.Clicks, Unique: variant.Unique})
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(resJSON)
 }
 
 // PingDB проверяет соединение с PostgreSQL
@@ -170,39 +375,86 @@ func (h *Handler) PostBatch(w http.ResponseWriter, r *http.Request) {
 	inputs := make([]batchInput, 0)
 	if err = json.Unmarshal(b, &inputs); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	user, err := getUserIDFROMCookie(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	tmps := make([]batchTmp, 0, len(inputs))
-	urls := make([]domain.URL, 0, len(inputs))
-	for _, url := range inputs {
-		tmpShort, errInput := module.ShortingURL(url.Long)
-		if errInput != nil {
-			http.Error(w, errInput.Error(), http.StatusBadRequest)
+	longs := make([]string, 0, len(inputs))
+	aliases := make([]string, 0, len(inputs))
+	expires := make([]time.Time, 0, len(inputs))
+	now := time.Now()
+	for i, url := range inputs {
+		expiresAt, errExpiry := module.NotAfter(url.ExpiresAt, url.NotAfter)
+		if errExpiry == nil {
+			expiresAt, errExpiry = module.ExpiresAt(url.TTL, expiresAt, now)
+		}
+		if errExpiry == nil {
+			errExpiry = module.ValidateSchedule(url.NotBefore, expiresAt, url.Fallback)
+		}
+		if errExpiry != nil {
+			http.Error(w, errExpiry.Error(), http.StatusBadRequest)
+			return
 		}
+		if inputs[i].Title, inputs[i].Tags, err = module.NormalizeMeta(url.Title, url.Tags); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// дальше в Password хранится хэш
+		if inputs[i].Password, err = module.HashPassword(url.Password); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = module.ValidateMaxClicks(url.MaxClicks); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		longs = append(longs, url.Long)
+		aliases = append(aliases, url.Alias)
+		expires = append(expires, expiresAt)
+	}
+	shorts, err := h.shortener.ShortBatch(longs, aliases, func(shorts []string) error {
+		urls := make([]domain.URL, 0, len(inputs))
+		for i, url := range inputs {
+			urls = append(urls, domain.URL{
+				User:      user,
+				Short:     shorts[i],
+				Long:      url.Long,
+				ExpiresAt: expires[i],
+				Title:     url.Title,
+				Tags:      url.Tags,
+				Preview:   url.Preview,
+				Password:  url.Password,
+				MaxClicks: url.MaxClicks,
+				NotBefore: url.NotBefore.UTC(),
+				Fallback:  url.Fallback,
+			})
+		}
+		return h.Storage.SetBatchURLs(r.Context(), urls)
+	})
+	if errors.Is(err, module.ErrAliasTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.logger.Info(err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tmps := make([]batchTmp, 0, len(inputs))
+	for i, url := range inputs {
 		tmps = append(tmps, batchTmp{
-			Short:         tmpShort,
+			Short:         shorts[i],
 			Long:          url.Long,
 			CorrelationID: url.CorrelationID,
 		})
-		urls = append(urls, domain.URL{
-			User:  user,
-			Short: tmpShort,
-			Long:  url.Long,
-		})
-		fmt.Println(tmpShort, ":", url.Long)
 	}
 
 	result := make([]batchResult, len(inputs))
-	err = h.Storage.SetBatchURLs(r.Context(), urls)
-	if err != nil {
-		h.logger.Info(err.Error())
-		http.Error(w, err.Error(), http.StatusBadRequest)
-	}
 	for i, urlEnt := range tmps {
 		result[i] = batchResult{
 			Short:         fmt.Sprintf("%s/%s", h.BaseURL, urlEnt.Short),
@@ -227,17 +479,53 @@ func (h *Handler) PostJSON(w http.ResponseWriter, r *http.Request) {
 	urlEnt := input{}
 	if errUnmarshal := json.Unmarshal(b, &urlEnt); errUnmarshal != nil {
 		http.Error(w, errUnmarshal.Error(), http.StatusBadRequest)
+		return
 	}
-	short, err := module.ShortingURL(urlEnt.URL)
+	user, err := getUserIDFROMCookie(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	expiresAt, err := module.NotAfter(urlEnt.ExpiresAt, urlEnt.NotAfter)
+	if err == nil {
+		expiresAt, err = module.ExpiresAt(urlEnt.TTL, expiresAt, time.Now())
+	}
+	if err == nil {
+		err = module.ValidateSchedule(urlEnt.NotBefore, expiresAt, urlEnt.Fallback)
 	}
-	user, err := getUserIDFROMCookie(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	title, tags, err := module.NormalizeMeta(urlEnt.Title, urlEnt.Tags)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	password, err := module.HashPassword(urlEnt.Password)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = module.ValidateMaxClicks(urlEnt.MaxClicks); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	err = h.Storage.SetURL(r.Context(), user, short, urlEnt.URL)
+	short, err := h.shortener.Short(urlEnt.URL, urlEnt.Alias, func(short string) error {
+		return h.Storage.SetURL(r.Context(), domain.URL{Short: short, Long: urlEnt.URL, User: user, ExpiresAt: expiresAt,
+			Title: title, Tags: tags, Preview: urlEnt.Preview, Password: password, MaxClicks: urlEnt.MaxClicks,
+			NotBefore: urlEnt.NotBefore.UTC(), Fallback: urlEnt.Fallback})
+	})
+	if module.IsInputError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, module.ErrAliasTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	res := result{}
 
 	var de *pckgstorage.DuplicationError
@@ -260,23 +548,47 @@ func (h *Handler) PostJSON(w http.ResponseWriter, r *http.Request) {
 	w.Write(resJSON)
 }
 
-// GetURLsByUser возвращает JSON с массивом ссылок, которые созданы текущим пользователем
+// GetURLsByUser возвращает JSON с массивом ссылок, которые созданы текущим пользователем. Страница задается
+// параметрами limit и cursor, порядок - sort (short, created, clicks) и order (asc, desc), фильтры - status
+// (active, deleted, all), domain и q (поиск по подстроке). Курсор следующей страницы - в заголовке X-Next-Cursor
 func (h *Handler) GetURLsByUser(w http.ResponseWriter, r *http.Request) {
 	cookie, err := r.Cookie("id")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	urls := h.Storage.GetURLsByUser(r.Context(), cookie.Value)
-	if len(urls) == 0 {
+	params := r.URL.Query()
+	limit := 0
+	if s := params.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil {
+			http.Error(w, module.ErrWrongListQuery.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	query, err := module.NewListQuery(limit, params.Get("cursor"), params.Get("sort"), params.Get("order"),
+		params.Get("status"), params.Get("domain"), params.Get("q"), params["tag"], params.Get("folder"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := h.Storage.ListURLs(r.Context(), cookie.Value, query)
+	if err != nil {
+		h.logger.Error("GetURLsByUser ListURLs error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(page) == 0 {
 		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if next := module.NextCursor(query, page); next != "" {
+		params.Set("cursor", next)
+		w.Header().Set("X-Next-Cursor", next)
+		w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, params.Encode()))
 	}
-	var one link
-	var links []link
-	for key, value := range urls {
-		one.Short = h.BaseURL + "/" + key
-		one.Long = value
-		links = append(links, one)
+	links := make([]link, 0, len(page))
+	for _, info := range page {
+		links = append(links, h.link(info.URL))
 	}
 	resJSON, err := json.Marshal(links)
 	if err != nil {
@@ -285,6 +597,138 @@ func (h *Handler) GetURLsByUser(w http.ResponseWriter, r *http.Request) {
 	w.Write(resJSON)
 }
 
+// PatchURL меняет полный URL ссылки текущего пользователя. Возвращает JSON со ссылкой,
+// 404 для чужих и несуществующих ссылок, 409 если новый URL уже сокращен другой ссылкой
+func (h *Handler) PatchURL(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	in := patchInput{}
+	if err = json.Unmarshal(b, &in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = module.ValidateURL(in.URL); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	url, err := h.Storage.UpdateURL(r.Context(), user, chi.URLParam(r, "id"), in.URL)
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	var de *pckgstorage.DuplicationError
+	if errors.As(err, &de) {
+		http.Error(w, fmt.Sprintf("url already shortened as %s/%s", h.BaseURL, de.Duplication), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.logger.Error("UpdateURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.logger.Info("UpdateURL", zap.String("short", url.Short), zap.String("long", url.Long))
+	resJSON, err := json.Marshal(h.link(url))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(resJSON)
+}
+
+// RestoreURL восстанавливает удаленную ссылку текущего пользователя в течение срока восстановления.
+// Возвращает JSON со ссылкой, 404 для чужих, несуществующих и удаленных раньше срока ссылок
+func (h *Handler) RestoreURL(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	url, err := h.Storage.RestoreURL(r.Context(), user, chi.URLParam(r, "id"), time.Now().Add(-h.gracePeriod))
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("RestoreURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resJSON, err := json.Marshal(h.link(url))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(resJSON)
+}
+
+// GetURLRevisions возвращает JSON с историей изменений ссылки текущего пользователя от старых к новым.
+// Для чужих и несуществующих ссылок - 404
+func (h *Handler) GetURLRevisions(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	short := chi.URLParam(r, "id")
+	url, err := h.Storage.GetURL(r.Context(), short)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && url.User != user) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("GetURLRevisions GetURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	revisions, err := h.Storage.GetURLRevisions(r.Context(), short)
+	if err != nil {
+		h.logger.Error("GetURLRevisions error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res := make([]revision, 0, len(revisions))
+	for _, rev := range revisions {
+		res = append(res, revision{OldLong: rev.OldLong, NewLong: rev.NewLong, ChangedAt: rev.ChangedAt})
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(resJSON)
+}
+
+// link возвращает ссылку для ответа
+func (h *Handler) link(url domain.URL) link {
+	return link{
+		Short:     h.BaseURL + "/" + url.Short,
+		Long:      url.Long,
+		Title:     url.Title,
+		Tags:      url.Tags,
+		Folder:    url.Folder,
+		Preview:   url.Preview,
+		Protected: url.Protected(),
+		MaxClicks: url.MaxClicks,
+		Used:      url.Uses,
+		NotBefore: optionalTime(url.NotBefore),
+		ExpiresAt: optionalTime(url.ExpiresAt),
+		Fallback:  url.Fallback,
+		Rules:     url.Rules,
+		Variants:  url.Variants,
+		CreatedAt: optionalTime(url.CreatedAt),
+		UpdatedAt: optionalTime(url.UpdatedAt),
+		DeletedAt: optionalTime(url.DeletedAt),
+	}
+}
+
 // GetInternalStats возвращает JSON со статистикой, если запрос идет из доверенных подсетей
 func (h *Handler) GetInternalStats(w http.ResponseWriter, r *http.Request) {
 	ip := r.Header.Get("X-Real-IP")
@@ -402,6 +846,18 @@ func verifyCookies(cookies []*http.Cookie, secretKey string) bool {
 	return hmac.Equal(h.Sum(nil), token)
 }
 
+// clientIP возвращает адрес клиента: из X-Real-IP, если запрос пришел через прокси, иначе из RemoteAddr
+func clientIP(r *http.Request) string {
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func getUserIDFROMCookie(r *http.Request) (string, error) {
 	cookie, err := r.Cookie("id")
 	if err != nil {
@@ -1,18 +1,25 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
 	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
 	"github.com/Spear5030/yapshrtnr/pkg/logger"
 	"net"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func BenchmarkHandler_PostURLMemory(b *testing.B) {
 	cfg, _ := config.New()
 	lg, _ := logger.New(true)
-	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
 	r := httptest.NewRequest("Post", "/", nil)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -26,8 +33,8 @@ func BenchmarkHandler_PostURLMemory(b *testing.B) {
 func BenchmarkHandler_PostURLFile(b *testing.B) {
 	cfg, _ := config.New()
 	lg, _ := logger.New(true)
-	s, _ := testStorage.NewFileStorage("bench.base")
-	h := New(lg, s, cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	s, _ := testStorage.NewFileStorage("bench.base", cfg.CompactSize, cfg.SnapshotInterval)
+	h := New(lg, s, cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
 	r := httptest.NewRequest("Post", "/", nil)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -37,3 +44,60 @@ func BenchmarkHandler_PostURLFile(b *testing.B) {
 		h.PostURL(w, r)
 	}
 }
+
+func BenchmarkHandler_PostJSONMemoryParallel(b *testing.B) {
+	cfg, _ := config.New()
+	lg, _ := logger.New(false)
+	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r := httptest.NewRequest("POST", "/api/shorten", strings.NewReader(`{"url":"http://longlonglong.lg"}`))
+			r.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+			h.PostJSON(httptest.NewRecorder(), r)
+		}
+	})
+}
+
+func BenchmarkHandler_GetURLMemoryParallel(b *testing.B) {
+	cfg, _ := config.New()
+	lg, _ := logger.New(false)
+	s := testStorage.NewMemoryStorage()
+	h := New(lg, s, cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	shorts := make([]string, 1000)
+	for i := range shorts {
+		shorts[i] = fmt.Sprintf("short%03d", i)
+		_ = s.SetURL(context.Background(), domain.URL{Short: shorts[i], Long: "http://longlonglong.lg", User: "user1"})
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			r := httptest.NewRequest("GET", "/"+shorts[i%len(shorts)], nil)
+			h.GetURL(httptest.NewRecorder(), r)
+			i++
+		}
+	})
+}
+
+func BenchmarkHandler_MixedMemoryParallel(b *testing.B) {
+	cfg, _ := config.New()
+	lg, _ := logger.New(false)
+	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%10 == 0 {
+				r := httptest.NewRequest("POST", "/api/shorten", strings.NewReader(`{"url":"http://longlonglong.lg"}`))
+				r.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+				h.PostJSON(httptest.NewRecorder(), r)
+			} else {
+				r := httptest.NewRequest("GET", "/api/user/urls", nil)
+				r.AddCookie(&http.Cookie{Name: "id", Value: "user2"})
+				h.GetURLsByUser(httptest.NewRecorder(), r)
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+// TestHandler_ConcurrentMemory нагружает хранилище в памяти одновременными запросами.
+// Запускать с go test -race
+func TestHandler_ConcurrentMemory(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(false)
+	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+
+	const workers, perWorker = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			user := fmt.Sprintf("user%d", w%2)
+			for i := 0; i < perWorker; i++ {
+				req := httptest.NewRequest("POST", "/api/shorten", strings.NewReader(fmt.Sprintf(`{"url":"http://site%d-%d.ru"}`, w, i)))
+				req.AddCookie(&http.Cookie{Name: "id", Value: user})
+				rec := httptest.NewRecorder()
+				h.PostJSON(rec, req)
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				short := strings.TrimPrefix(rec.Body.String(), `{"result":"`+cfg.BaseURL+"/")
+				short = strings.TrimSuffix(short, `"}`)
+				req = httptest.NewRequest("GET", "/"+short, nil)
+				rec = httptest.NewRecorder()
+				h.GetURL(rec, req)
+				assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+
+				req = httptest.NewRequest("GET", "/api/user/urls", nil)
+				req.AddCookie(&http.Cookie{Name: "id", Value: user})
+				h.GetURLsByUser(httptest.NewRecorder(), req)
+
+				if i%5 == 0 {
+					req = httptest.NewRequest("DELETE", "/api/user/urls", strings.NewReader(`["`+short+`"]`))
+					req.AddCookie(&http.Cookie{Name: "id", Value: user})
+					h.DeleteBatchByUser(httptest.NewRecorder(), req)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	urls, err := h.Storage.GetUrlsCount(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, workers*perWorker, urls)
+}
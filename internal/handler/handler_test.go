@@ -1,14 +1,22 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
 	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
 	"github.com/Spear5030/yapshrtnr/pkg/logger"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	neturl "net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestHandler_GetInternalStats(t *testing.T) {
@@ -16,7 +24,7 @@ func TestHandler_GetInternalStats(t *testing.T) {
 	require.NoError(t, err)
 	lg, _ := logger.New(true)
 	_, IPNet, _ := net.ParseCIDR("127.0.0.0/8")
-	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, *IPNet)
+	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, *IPNet, module.RandomGenerator{}, time.Hour, nil)
 	req := httptest.NewRequest("GET", "/api/internal/stats", nil)
 
 	w := httptest.NewRecorder()
@@ -41,7 +49,7 @@ func TestHandler_GetInternalStatsWithoutCIDR(t *testing.T) {
 	cfg, err := config.New()
 	require.NoError(t, err)
 	lg, _ := logger.New(true)
-	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	h := New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
 	req := httptest.NewRequest("GET", "/api/internal/stats", nil)
 
 	w := httptest.NewRecorder()
@@ -62,3 +70,349 @@ func TestHandler_GetInternalStatsWithoutCIDR(t *testing.T) {
 	require.Equal(t, http.StatusForbidden, w.Code)
 
 }
+
+func TestHandler_GetURLsByUser(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "cccccccc", Long: "http://c.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user1"}))
+	h.Storage.DeleteURLs(ctx, "user1", []string{"bbbbbbbb"})
+
+	req := httptest.NewRequest("GET", "/api/user/urls", nil)
+	req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+	w := httptest.NewRecorder()
+	h.GetURLsByUser(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	requireLinks(t, `[{"short_url":"http://localhost:8080/aaaaaaaa","original_url":"http://a.ru"},
+		{"short_url":"http://localhost:8080/cccccccc","original_url":"http://c.ru"}]`, w.Body.String())
+
+	req = httptest.NewRequest("GET", "/api/user/urls", nil)
+	req.AddCookie(&http.Cookie{Name: "id", Value: "user2"})
+	w = httptest.NewRecorder()
+	h.GetURLsByUser(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestHandler_GetURLsByUserPages(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru/go", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "cccccccc", Long: "http://c.ru/go", User: "user1"}))
+	h.Storage.RecordClick(ctx, domain.Click{Short: "cccccccc"})
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+		w := httptest.NewRecorder()
+		h.GetURLsByUser(w, req)
+		return w
+	}
+	w := get("/api/user/urls?limit=2&sort=clicks&order=desc")
+	require.Equal(t, http.StatusOK, w.Code)
+	// при равенстве ключа порядок по короткому идентификатору тоже обратный
+	requireLinks(t, `[{"short_url":"http://localhost:8080/cccccccc","original_url":"http://c.ru/go"},
+		{"short_url":"http://localhost:8080/bbbbbbbb","original_url":"http://b.ru"}]`, w.Body.String())
+	next := w.Header().Get("X-Next-Cursor")
+	require.NotEmpty(t, next)
+	require.Contains(t, w.Header().Get("Link"), `rel="next"`)
+
+	w = get("/api/user/urls?limit=2&sort=clicks&order=desc&cursor=" + next)
+	require.Equal(t, http.StatusOK, w.Code)
+	requireLinks(t, `[{"short_url":"http://localhost:8080/aaaaaaaa","original_url":"http://a.ru/go"}]`, w.Body.String())
+	require.Empty(t, w.Header().Get("X-Next-Cursor"))
+
+	w = get("/api/user/urls?q=GO&domain=c.ru")
+	requireLinks(t, `[{"short_url":"http://localhost:8080/cccccccc","original_url":"http://c.ru/go"}]`, w.Body.String())
+	require.Equal(t, http.StatusBadRequest, get("/api/user/urls?sort=long").Code)
+	require.Equal(t, http.StatusBadRequest, get("/api/user/urls?limit=x").Code)
+	require.Equal(t, http.StatusBadRequest, get("/api/user/urls?cursor="+next).Code, "курсор другой сортировки")
+}
+
+func TestHandler_PostJSONAlias(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/shorten", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+		w := httptest.NewRecorder()
+		h.PostJSON(w, req)
+		return w
+	}
+	w := post(`{"url":"http://a.ru","alias":"spring-sale"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"result":"http://localhost:8080/spring-sale"}`, w.Body.String())
+
+	w = post(`{"url":"http://b.ru","alias":"spring-sale"}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = post(`{"url":"http://b.ru","alias":"ping"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = post(`{"url":"http://b.ru","alias":"no spaces"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_PostJSONMeta(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/shorten", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+		w := httptest.NewRecorder()
+		h.PostJSON(w, req)
+		return w
+	}
+	w := post(`{"url":"http://a.ru","alias":"promo","title":" Весенняя распродажа ","tags":["Sale","sale","spring"]}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	url, err := h.Storage.GetURL(context.Background(), "promo")
+	require.NoError(t, err)
+	require.Equal(t, "Весенняя распродажа", url.Title)
+	require.Equal(t, []string{"sale", "spring"}, url.Tags)
+	require.False(t, url.CreatedAt.IsZero())
+
+	require.Equal(t, http.StatusBadRequest, post(`{"url":"http://b.ru","tags":["no spaces"]}`).Code)
+	require.Equal(t, http.StatusBadRequest, post(`{"url":"http://b.ru","title":"`+strings.Repeat("a", 201)+`"}`).Code)
+}
+
+func TestHandler_GetURLExpired(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/shorten", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+		w := httptest.NewRecorder()
+		h.PostJSON(w, req)
+		return w
+	}
+	require.Equal(t, http.StatusCreated, post(`{"url":"http://a.ru","alias":"fresh","ttl":3600}`).Code)
+	require.Equal(t, http.StatusBadRequest, post(`{"url":"http://b.ru","ttl":-1}`).Code)
+	require.Equal(t, http.StatusBadRequest, post(`{"url":"http://b.ru","expires_at":"2001-01-01T00:00:00Z"}`).Code)
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "expired", Long: "http://c.ru", User: "user1",
+		ExpiresAt: time.Now().Add(-time.Second)}))
+
+	get := func(short string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.GetURL(w, httptest.NewRequest("GET", "/"+short, nil))
+		return w
+	}
+	w := get("fresh")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	require.Equal(t, "http://a.ru", w.Header().Get("Location"))
+	require.Equal(t, http.StatusGone, get("expired").Code)
+	require.Equal(t, http.StatusBadRequest, get("unknown").Code)
+}
+
+func TestHandler_GetURLStats(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1"}))
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/aaaaaaaa", nil)
+		req.Header.Set("Referer", "http://ref.ru")
+		h.GetURL(httptest.NewRecorder(), req)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/user/urls/{id}/stats", h.GetURLStats)
+	stats := func(user, short string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/user/urls/"+short+"/stats", nil)
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	w := stats("user1", "aaaaaaaa")
+	require.Equal(t, http.StatusOK, w.Code)
+	today := time.Now().UTC().Format("2006-01-02")
+	require.JSONEq(t, `{"short_url":"http://localhost:8080/aaaaaaaa","total":3,"unique":1,
+		"top_referrers":[{"referrer":"http://ref.ru","clicks":3}],"daily":[{"date":"`+today+`","clicks":3}]}`, w.Body.String())
+
+	require.Equal(t, http.StatusNotFound, stats("user2", "aaaaaaaa").Code)
+	require.Equal(t, http.StatusNotFound, stats("user1", "bbbbbbbb").Code)
+}
+
+func TestHandler_PatchURL(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1"}))
+
+	r := chi.NewRouter()
+	r.Patch("/api/user/urls/{id}", h.PatchURL)
+	r.Get("/api/user/urls/{id}/revisions", h.GetURLRevisions)
+	do := func(method, user, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	w := do("PATCH", "user1", "/api/user/urls/aaaaaaaa", `{"url":"http://b.ru"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	requireLinks(t, `{"short_url":"http://localhost:8080/aaaaaaaa","original_url":"http://b.ru"}`, w.Body.String())
+
+	require.Equal(t, http.StatusBadRequest, do("PATCH", "user1", "/api/user/urls/aaaaaaaa", `{"url":"not a url"}`).Code)
+	require.Equal(t, http.StatusNotFound, do("PATCH", "user2", "/api/user/urls/aaaaaaaa", `{"url":"http://c.ru"}`).Code)
+	require.Equal(t, http.StatusNotFound, do("PATCH", "user1", "/api/user/urls/bbbbbbbb", `{"url":"http://c.ru"}`).Code)
+
+	w = httptest.NewRecorder()
+	h.GetURL(w, httptest.NewRequest("GET", "/aaaaaaaa", nil))
+	require.Equal(t, "http://b.ru", w.Header().Get("Location"))
+
+	w = do("GET", "user1", "/api/user/urls/aaaaaaaa/revisions", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var revisions []revision
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+	require.Len(t, revisions, 1)
+	require.Equal(t, "http://a.ru", revisions[0].OldLong)
+	require.Equal(t, "http://b.ru", revisions[0].NewLong)
+	require.Equal(t, http.StatusNotFound, do("GET", "user2", "/api/user/urls/aaaaaaaa/revisions", "").Code)
+}
+
+func TestHandler_RestoreURL(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1"}))
+	h.Storage.DeleteURLs(ctx, "user1", []string{"aaaaaaaa"})
+
+	r := chi.NewRouter()
+	r.Post("/api/user/urls/{id}/restore", h.RestoreURL)
+	restore := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/user/urls/aaaaaaaa/restore", nil)
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	require.Equal(t, http.StatusNotFound, restore("user2").Code)
+	w := restore("user1")
+	require.Equal(t, http.StatusOK, w.Code)
+	requireLinks(t, `{"short_url":"http://localhost:8080/aaaaaaaa","original_url":"http://a.ru"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.GetURL(w, httptest.NewRequest("GET", "/aaaaaaaa", nil))
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+}
+
+func TestHandler_DeleteJob(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user2"}))
+
+	r := chi.NewRouter()
+	r.Delete("/api/user/urls", h.DeleteBatchByUser)
+	r.Get("/api/user/jobs/{id}", h.GetDeleteJob)
+	do := func(method, user, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	w := do("DELETE", "user1", "/api/user/urls", `["aaaaaaaa","bbbbbbbb"]`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var accepted deleteJobID
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+	require.NotEmpty(t, accepted.JobID)
+	require.Equal(t, "/api/user/jobs/"+accepted.JobID, w.Header().Get("Location"))
+
+	w = do("GET", "user1", "/api/user/jobs/"+accepted.JobID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var job deleteJobResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	require.Equal(t, "done", job.Status)
+	require.Equal(t, []string{"aaaaaaaa"}, job.Deleted)
+	require.Equal(t, []string{"bbbbbbbb"}, job.Skipped)
+
+	require.Equal(t, http.StatusNotFound, do("GET", "user2", "/api/user/jobs/"+accepted.JobID, "").Code)
+	require.Equal(t, http.StatusNotFound, do("GET", "user1", "/api/user/jobs/unknown", "").Code)
+}
+
+// requireLinks сравнивает ссылки из ответа с ожидаемыми без учета меток времени, проверяя только их наличие
+func requireLinks(t *testing.T, expected, actual string) {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(actual), &v))
+	links, ok := v.([]any)
+	if !ok {
+		links = []any{v}
+	}
+	for _, l := range links {
+		m := l.(map[string]any)
+		require.NotEmpty(t, m["created_at"])
+		require.NotEmpty(t, m["updated_at"])
+		delete(m, "created_at")
+		delete(m, "updated_at")
+		delete(m, "deleted_at")
+	}
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(b))
+}
+
+func TestHandler_GetURLMaxClicks(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/shorten", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+		w := httptest.NewRecorder()
+		h.PostJSON(w, req)
+		return w
+	}
+	require.Equal(t, http.StatusCreated, post(`{"url":"http://a.ru","alias":"once","max_clicks":1}`).Code)
+	require.Equal(t, http.StatusCreated, post(`{"url":"http://b.ru","alias":"preview","max_clicks":1,"preview":true}`).Code)
+	require.Equal(t, http.StatusBadRequest, post(`{"url":"http://c.ru","max_clicks":-1}`).Code)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.GetURL(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+	w := get("/once")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	require.Equal(t, "http://a.ru", w.Header().Get("Location"))
+	require.Equal(t, http.StatusGone, get("/once").Code)
+
+	// страница предпросмотра переход не расходует
+	require.Equal(t, http.StatusOK, get("/preview").Code)
+	continued := "/preview?continue=" + neturl.QueryEscape(h.continueToken("preview", time.Now()))
+	require.Equal(t, http.StatusTemporaryRedirect, get(continued).Code)
+	require.Equal(t, http.StatusGone, get(continued).Code)
+
+	url, err := h.Storage.GetURL(context.Background(), "once")
+	require.NoError(t, err)
+	res := h.link(url)
+	require.Equal(t, 1, res.MaxClicks)
+	require.Equal(t, 1, res.Used)
+}
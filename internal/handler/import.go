@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	pckgstorage "github.com/Spear5030/yapshrtnr/internal/storage"
+)
+
+const (
+	importChunkSize = 500     // строк в одном вызове SetBatchURLs
+	importMaxLine   = 1 << 20 // максимальная длина строки NDJSON
+)
+
+// Результаты строки импорта
+const (
+	importCreated   = "created"
+	importDuplicate = "duplicate"
+	importInvalid   = "invalid"
+	importFailed    = "error"
+)
+
+// importRow строка импорта. err - ошибка разбора строки, такая строка не сохраняется
+type importRow struct {
+	row int
+	input
+	err error
+}
+
+type importResult struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	Short  string `json:"short_url,omitempty"`
+	Long   string `json:"original_url,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PostImport импортирует ссылки из CSV (text/csv) или NDJSON (application/x-ndjson) с необязательными алиасами.
+// Тело читается потоком и сохраняется пакетами по importChunkSize строк. Ответ - NDJSON с результатом каждой строки:
+// created, duplicate, invalid или error. После ошибки хранилища импорт прекращается
+func (h *Handler) PostImport(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var next func() (importRow, error)
+	switch mediaType {
+	case "text/csv":
+		next = csvRows(r.Body)
+	case "application/x-ndjson", "application/ndjson":
+		next = ndjsonRows(r.Body)
+	default:
+		http.Error(w, "content type must be text/csv or application/x-ndjson", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	write := func(chunk []importRow) bool {
+		results, err := h.importChunk(r.Context(), user, chunk)
+		for _, res := range results {
+			enc.Encode(res)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return err == nil
+	}
+	chunk := make([]importRow, 0, importChunkSize)
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			write(chunk)
+			enc.Encode(importResult{Row: row.row, Status: importFailed, Error: err.Error()})
+			return
+		}
+		chunk = append(chunk, row)
+		if len(chunk) == importChunkSize {
+			if !write(chunk) {
+				return
+			}
+			chunk = chunk[:0]
+		}
+	}
+	write(chunk)
+}
+
+// importChunk сохраняет пакет строк одним SetBatchURLs. Если пакет не сохраняется целиком, например из-за занятого
+// алиаса, строки сохраняются по одной, чтобы получить результат каждой. Ошибка хранилища прерывает пакет:
+// возвращаются результаты до строки с ошибкой включительно
+func (h *Handler) importChunk(ctx context.Context, user string, rows []importRow) ([]importResult, error) {
+	results := make([]importResult, len(rows))
+	valid := make([]int, 0, len(rows))
+	urls := make([]domain.URL, 0, len(rows))
+	longs := make([]string, 0, len(rows))
+	aliases := make([]string, 0, len(rows))
+	now := time.Now()
+	for i, row := range rows {
+		results[i] = importResult{Row: row.row, Long: row.URL}
+		err := row.err
+		var expiresAt time.Time
+		if err == nil {
+			expiresAt, err = module.NotAfter(row.ExpiresAt, row.NotAfter)
+		}
+		if err == nil {
+			expiresAt, err = module.ExpiresAt(row.TTL, expiresAt, now)
+		}
+		if err == nil {
+			err = module.ValidateSchedule(row.NotBefore, expiresAt, row.Fallback)
+		}
+		if err == nil {
+			err = module.ValidateURL(row.URL)
+		}
+		if err == nil && row.Alias != "" {
+			err = module.ValidateAlias(row.Alias)
+		}
+		var title string
+		var tags []string
+		if err == nil {
+			title, tags, err = module.NormalizeMeta(row.Title, row.Tags)
+		}
+		var password string
+		if err == nil {
+			password, err = module.HashPassword(row.Password)
+		}
+		if err == nil {
+			err = module.ValidateMaxClicks(row.MaxClicks)
+		}
+		if err != nil {
+			results[i].Status = importInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+		valid = append(valid, i)
+		urls = append(urls, domain.URL{Long: row.URL, User: user, ExpiresAt: expiresAt, Title: title, Tags: tags,
+			Preview: row.Preview, Password: password, MaxClicks: row.MaxClicks,
+			NotBefore: row.NotBefore.UTC(), Fallback: row.Fallback})
+		longs = append(longs, row.URL)
+		aliases = append(aliases, row.Alias)
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	shorts, err := h.shortener.ShortBatch(longs, aliases, func(shorts []string) error {
+		for i := range urls {
+			urls[i].Short = shorts[i]
+		}
+		return h.Storage.SetBatchURLs(ctx, urls)
+	})
+	if err == nil {
+		for j, i := range valid {
+			results[i].Status = importCreated
+			results[i].Short = fmt.Sprintf("%s/%s", h.BaseURL, shorts[j])
+		}
+		return results, nil
+	}
+
+	for j, i := range valid {
+		url := urls[j]
+		short, err := h.shortener.Short(url.Long, aliases[j], func(short string) error {
+			url.Short = short
+			return h.Storage.SetURL(ctx, url)
+		})
+		var de *pckgstorage.DuplicationError
+		switch {
+		case err == nil:
+			results[i].Status = importCreated
+			results[i].Short = fmt.Sprintf("%s/%s", h.BaseURL, short)
+		case errors.Is(err, module.ErrAliasTaken):
+			results[i].Status = importDuplicate
+			results[i].Error = err.Error()
+		case errors.As(err, &de):
+			results[i].Status = importDuplicate
+			results[i].Short = fmt.Sprintf("%s/%s", h.BaseURL, de.Duplication)
+		case module.IsInputError(err):
+			results[i].Status = importInvalid
+			results[i].Error = err.Error()
+		default:
+			h.logger.Error("PostImport SetURL error", zap.Error(err))
+			results[i].Status = importFailed
+			results[i].Error = err.Error()
+			return results[:i+1], err
+		}
+	}
+	return results, nil
+}
+
+// csvRows читает CSV построчно. Если первая запись - заголовок с колонкой url или original_url, колонки ищутся
+// по именам url, alias, title, tags (через точку с запятой), preview, password, max_clicks, ttl, expires_at,
+// not_before, not_after и fallback_url. Время - в RFC 3339. Без заголовка первая колонка - URL, вторая - алиас
+func csvRows(body io.Reader) func() (importRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	columns := map[string]int{"url": 0, "alias": 1}
+	first := true
+	row := 0
+	return func() (importRow, error) {
+		for {
+			record, err := reader.Read()
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				first = false
+				row++
+				return importRow{row: row, err: err}, nil
+			}
+			if err != nil {
+				return importRow{row: row + 1}, err
+			}
+			if first {
+				first = false
+				if header, ok := csvHeader(record); ok {
+					columns = header
+					continue
+				}
+			}
+			row++
+			return csvRow(row, record, columns), nil
+		}
+	}
+}
+
+// csvHeader возвращает номера колонок, если запись - заголовок
+func csvHeader(record []string) (map[string]int, bool) {
+	columns := make(map[string]int)
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "original_url" {
+			name = "url"
+		}
+		columns[name] = i
+	}
+	_, ok := columns["url"]
+	return columns, ok
+}
+
+func csvRow(row int, record []string, columns map[string]int) importRow {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	res := importRow{row: row}
+	res.URL = field("url")
+	res.Alias = field("alias")
+	res.Title = field("title")
+	if tags := field("tags"); tags != "" {
+		res.Tags = strings.Split(tags, ";")
+	}
+	if preview := field("preview"); preview != "" {
+		if res.Preview, res.err = strconv.ParseBool(preview); res.err != nil {
+			return res
+		}
+	}
+	res.Password = field("password")
+	if maxClicks := field("max_clicks"); maxClicks != "" {
+		if res.MaxClicks, res.err = strconv.Atoi(maxClicks); res.err != nil {
+			return res
+		}
+	}
+	if ttl := field("ttl"); ttl != "" {
+		if res.TTL, res.err = strconv.ParseInt(ttl, 10, 64); res.err != nil {
+			return res
+		}
+	}
+	res.Fallback = field("fallback_url")
+	for name, dst := range map[string]*time.Time{
+		"expires_at": &res.ExpiresAt,
+		"not_before": &res.NotBefore,
+		"not_after":  &res.NotAfter,
+	} {
+		if value := field(name); value != "" {
+			if *dst, res.err = time.Parse(time.RFC3339, value); res.err != nil {
+				return res
+			}
+		}
+	}
+	return res
+}
+
+// ndjsonRows читает NDJSON построчно, пустые строки пропускаются. Каждая строка - объект как в /api/shorten
+func ndjsonRows(body io.Reader) func() (importRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), importMaxLine)
+	row := 0
+	return func() (importRow, error) {
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			row++
+			res := importRow{row: row}
+			res.err = json.Unmarshal(line, &res.input)
+			return res, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return importRow{row: row + 1}, err
+		}
+		return importRow{}, io.EOF
+	}
+}
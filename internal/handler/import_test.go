@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func postImport(t *testing.T, h *Handler, contentType, body string) []importResult {
+	req := httptest.NewRequest("POST", "/api/shorten/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+	w := httptest.NewRecorder()
+	h.PostImport(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	var results []importResult
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var res importResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &res))
+		results = append(results, res)
+	}
+	return results
+}
+
+func TestHandler_PostImport(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "taken", Long: "http://taken.ru", User: "user2"}))
+
+	t.Run("csv", func(t *testing.T) {
+		body := "Alias,URL\nlegacy-1,http://a.ru\n,http://b.ru\nbad alias,http://c.ru\ntaken,http://d.ru\n,not a url\n"
+		results := postImport(t, h, "text/csv; charset=utf-8", body)
+		require.Len(t, results, 5)
+		require.Equal(t, importResult{Row: 1, Status: importCreated, Short: "http://localhost:8080/legacy-1", Long: "http://a.ru"}, results[0])
+		require.Equal(t, importCreated, results[1].Status)
+		require.Equal(t, importInvalid, results[2].Status)
+		require.Equal(t, importDuplicate, results[3].Status)
+		require.Equal(t, importInvalid, results[4].Status)
+		url, err := h.Storage.GetURL(context.Background(), "legacy-1")
+		require.NoError(t, err)
+		require.Equal(t, "user1", url.User)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		body := `{"url":"http://e.ru","alias":"legacy-2"}` + "\n\n{broken\n" + `{"url":"http://f.ru","ttl":-1}` + "\n"
+		results := postImport(t, h, "application/x-ndjson", body)
+		require.Len(t, results, 3)
+		require.Equal(t, importCreated, results[0].Status)
+		require.Equal(t, importInvalid, results[1].Status)
+		require.Equal(t, 2, results[1].Row)
+		require.Equal(t, importInvalid, results[2].Status)
+	})
+
+	t.Run("preview", func(t *testing.T) {
+		results := postImport(t, h, "application/x-ndjson", `{"url":"http://g.ru","alias":"legacy-3","preview":true}`+"\n")
+		require.Equal(t, importCreated, results[0].Status)
+		results = postImport(t, h, "text/csv", "url,alias,preview\nhttp://h.ru,legacy-4,true\nhttp://i.ru,,maybe\n")
+		require.Equal(t, importCreated, results[0].Status)
+		require.Equal(t, importInvalid, results[1].Status)
+		for _, short := range []string{"legacy-3", "legacy-4"} {
+			url, err := h.Storage.GetURL(context.Background(), short)
+			require.NoError(t, err)
+			require.True(t, url.Preview, short)
+		}
+	})
+
+	t.Run("password", func(t *testing.T) {
+		results := postImport(t, h, "application/x-ndjson",
+			`{"url":"http://j.ru","alias":"legacy-5","password":"secret123"}`+"\n"+`{"url":"http://k.ru","password":"123"}`+"\n")
+		require.Equal(t, importCreated, results[0].Status)
+		require.Equal(t, importInvalid, results[1].Status)
+		results = postImport(t, h, "text/csv", "url,alias,password\nhttp://l.ru,legacy-6,secret123\n")
+		require.Equal(t, importCreated, results[0].Status)
+		for _, short := range []string{"legacy-5", "legacy-6"} {
+			url, err := h.Storage.GetURL(context.Background(), short)
+			require.NoError(t, err)
+			require.True(t, module.CheckPassword(url.Password, "secret123"), short)
+		}
+	})
+
+	t.Run("max clicks", func(t *testing.T) {
+		results := postImport(t, h, "application/x-ndjson",
+			`{"url":"http://m.ru","alias":"legacy-7","max_clicks":1}`+"\n"+`{"url":"http://n.ru","max_clicks":-1}`+"\n")
+		require.Equal(t, importCreated, results[0].Status)
+		require.Equal(t, importInvalid, results[1].Status)
+		results = postImport(t, h, "text/csv", "url,alias,max_clicks\nhttp://o.ru,legacy-8,3\nhttp://p.ru,,many\n")
+		require.Equal(t, importCreated, results[0].Status)
+		require.Equal(t, importInvalid, results[1].Status)
+		for short, maxClicks := range map[string]int{"legacy-7": 1, "legacy-8": 3} {
+			url, err := h.Storage.GetURL(context.Background(), short)
+			require.NoError(t, err)
+			require.Equal(t, maxClicks, url.MaxClicks, short)
+		}
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		notBefore := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		notAfter := notBefore.Add(time.Hour)
+		results := postImport(t, h, "application/x-ndjson", fmt.Sprintf(
+			`{"url":"http://q.ru","alias":"legacy-9","not_before":%q,"not_after":%q,"fallback_url":"http://wait.ru"}`+"\n"+
+				`{"url":"http://r.ru","not_before":%[2]q,"not_after":%[1]q}`+"\n"+
+				`{"url":"http://s.ru","fallback_url":"http://wait.ru"}`+"\n",
+			notBefore.Format(time.RFC3339), notAfter.Format(time.RFC3339)))
+		require.Equal(t, importCreated, results[0].Status)
+		require.Equal(t, importInvalid, results[1].Status)
+		require.Equal(t, importInvalid, results[2].Status)
+		results = postImport(t, h, "text/csv", fmt.Sprintf("url,alias,not_before,not_after,fallback_url\n"+
+			"http://t.ru,legacy-10,%s,%s,http://wait.ru\nhttp://u.ru,,soon,,\n",
+			notBefore.Format(time.RFC3339), notAfter.Format(time.RFC3339)))
+		require.Equal(t, importCreated, results[0].Status)
+		require.Equal(t, importInvalid, results[1].Status)
+		for _, short := range []string{"legacy-9", "legacy-10"} {
+			url, err := h.Storage.GetURL(context.Background(), short)
+			require.NoError(t, err)
+			require.True(t, notBefore.Equal(url.NotBefore), short)
+			require.True(t, notAfter.Equal(url.ExpiresAt), short)
+			require.Equal(t, "http://wait.ru", url.Fallback, short)
+		}
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		var body strings.Builder
+		for i := 0; i < importChunkSize+10; i++ {
+			fmt.Fprintf(&body, "http://chunk.ru/%d\n", i)
+		}
+		results := postImport(t, h, "text/csv", body.String())
+		require.Len(t, results, importChunkSize+10)
+		for i, res := range results {
+			require.Equal(t, i+1, res.Row)
+			require.Equal(t, importCreated, res.Status)
+		}
+	})
+
+	req := httptest.NewRequest("POST", "/api/shorten/import", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+	w := httptest.NewRecorder()
+	h.PostImport(w, req)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+const (
+	// continueParam - параметр перехода со страницы предпросмотра, значение - подписанный токен
+	continueParam = "continue"
+	// continueTTL - срок действия токена перехода со страницы предпросмотра
+	continueTTL = 10 * time.Minute
+)
+
+//go:embed templates/*.html
+var templates embed.FS
+
+var (
+	previewTemplate = template.Must(template.ParseFS(templates, "templates/preview.html"))
+	unlockTemplate  = template.Must(template.ParseFS(templates, "templates/unlock.html"))
+	soonTemplate    = template.Must(template.ParseFS(templates, "templates/soon.html"))
+)
+
+type previewPage struct {
+	Short    string
+	Long     string
+	Host     string
+	Title    string
+	Continue string
+}
+
+type urlPreviewInput struct {
+	Preview bool `json:"preview"`
+}
+
+// GetPreview отдает страницу предпросмотра ссылки с адресом назначения, названием и кнопкой перехода
+// независимо от флага предпросмотра. До начала окна активности адрес назначения не раскрывается.
+// 404 для несуществующих, 410 для удаленных, истекших и исчерпавших переходы ссылок
+func (h *Handler) GetPreview(w http.ResponseWriter, r *http.Request) {
+	url, err := h.Storage.GetURL(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("GetPreview GetURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	if url.Deleted || url.Expired(now) || url.Exhausted() {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	if url.Pending(now) {
+		h.comingSoon(w, r, url, now)
+		return
+	}
+	// адрес назначения защищенной ссылки показывается только после ввода пароля
+	if url.Protected() && !h.unlocked(r, url) {
+		h.renderUnlock(w, http.StatusOK, url, "")
+		return
+	}
+	url.Long, _ = h.destination(w, r, url)
+	h.renderPreview(w, url)
+}
+
+// continueSign подписывает переход со страницы предпросмотра ссылки short до момента expires
+func continueSign(secretKey, short string, expires int64) []byte {
+	h := hmac.New(sha256.New, []byte(secretKey))
+	fmt.Fprintf(h, "continue|%s|%d", short, expires)
+	return h.Sum(nil)
+}
+
+// continueToken возвращает токен перехода со страницы предпросмотра: срок в секундах Unix и HMAC через точку
+func (h *Handler) continueToken(short string, now time.Time) string {
+	expires := now.Add(continueTTL).Unix()
+	return fmt.Sprintf("%d.%x", expires, continueSign(h.SecretKey, short, expires))
+}
+
+// continued проверяет токен перехода со страницы предпросмотра. Без верного токена, например по ссылке
+// с ?continue=1, снова показывается предпросмотр
+func (h *Handler) continued(r *http.Request, short string) bool {
+	token := r.URL.Query().Get(continueParam)
+	return token != "" && validSigned(token, func(expires int64) []byte {
+		return continueSign(h.SecretKey, short, expires)
+	})
+}
+
+// renderPreview отвечает страницей предпросмотра. Переход со страницы идет через короткую ссылку,
+// чтобы он попал в статистику, с подписанным токеном на continueTTL
+func (h *Handler) renderPreview(w http.ResponseWriter, url domain.URL) {
+	page := previewPage{
+		Short: strings.TrimPrefix(strings.TrimPrefix(h.BaseURL, "https://"), "http://") + "/" + url.Short,
+		Long:  url.Long,
+		Host:  url.Long,
+		Title: url.Title,
+		Continue: h.BaseURL + "/" + url.Short + "?" + continueParam + "=" +
+			neturl.QueryEscape(h.continueToken(url.Short, time.Now())),
+	}
+	if u, err := neturl.Parse(url.Long); err == nil && u.Host != "" {
+		page.Host = u.Hostname()
+	}
+	h.renderHTML(w, http.StatusOK, previewTemplate, page)
+}
+
+// renderHTML отвечает страницей из шаблона. Страницы зависят от состояния ссылки и не кэшируются
+func (h *Handler) renderHTML(w http.ResponseWriter, status int, tmpl *template.Template, data any) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		h.logger.Error("template error", zap.String("template", tmpl.Name()), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// PutURLPreview включает или выключает предпросмотр ссылки текущего пользователя. Возвращает JSON со ссылкой,
+// 404 для чужих, удаленных и несуществующих ссылок
+func (h *Handler) PutURLPreview(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in urlPreviewInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	url, err := h.Storage.SetURLPreview(r.Context(), user, chi.URLParam(r, "id"), in.Preview)
+	h.writeLink(w, url, err)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_Preview(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "https://docs.example.com/page?q=<b>",
+		User: "user1", Title: "Docs <script>", Preview: true}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "cccccccc", Long: "http://c.ru", User: "user1",
+		ExpiresAt: time.Now().Add(-time.Minute)}))
+
+	r := chi.NewRouter()
+	r.Get("/{id}", h.GetURL)
+	r.Get("/{id}+", h.GetPreview)
+	r.Put("/api/user/urls/{id}/preview", h.PutURLPreview)
+	do := func(method, user, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("GET", "user2", "/aaaaaaaa", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	page := w.Body.String()
+	require.Contains(t, page, "docs.example.com")
+	require.Contains(t, page, "Docs &lt;script&gt;")
+	require.NotContains(t, page, "<script>")
+
+	// без подписанного токена, в том числе с чужим или просроченным, снова показывается предпросмотр
+	for _, token := range []string{"1", h.continueToken("bbbbbbbb", time.Now()), h.continueToken("aaaaaaaa", time.Now().Add(-continueTTL))} {
+		require.Equal(t, http.StatusOK, do("GET", "user2", "/aaaaaaaa?continue="+neturl.QueryEscape(token), "").Code, token)
+	}
+	token := h.continueToken("aaaaaaaa", time.Now())
+	require.Contains(t, page, `href="http://localhost:8080/aaaaaaaa?continue=`+neturl.QueryEscape(token)+`"`)
+	w = do("GET", "user2", "/aaaaaaaa?continue="+neturl.QueryEscape(token), "")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	require.Equal(t, "https://docs.example.com/page?q=<b>", w.Header().Get("Location"))
+
+	// страница предпросмотра доступна и для ссылок без флага
+	require.Equal(t, http.StatusTemporaryRedirect, do("GET", "user2", "/bbbbbbbb", "").Code)
+	w = do("GET", "user2", "/bbbbbbbb+", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "http://b.ru")
+	require.Equal(t, http.StatusNotFound, do("GET", "user2", "/dddddddd+", "").Code)
+	require.Equal(t, http.StatusGone, do("GET", "user2", "/cccccccc+", "").Code)
+
+	require.Equal(t, http.StatusNotFound, do("PUT", "user2", "/api/user/urls/aaaaaaaa/preview", `{"preview":false}`).Code)
+	w = do("PUT", "user1", "/api/user/urls/aaaaaaaa/preview", `{"preview":false}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), `"preview"`)
+	require.Equal(t, http.StatusTemporaryRedirect, do("GET", "user2", "/aaaaaaaa", "").Code)
+	w = do("PUT", "user1", "/api/user/urls/bbbbbbbb/preview", `{"preview":true}`)
+	require.Contains(t, w.Body.String(), `"preview":true`)
+	require.Equal(t, http.StatusOK, do("GET", "user2", "/bbbbbbbb", "").Code)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+)
+
+// qrMaxAge - срок кэширования QR-кода. Изображение зависит только от адреса и параметров
+const qrMaxAge = 24 * time.Hour
+
+// GetQR отдает QR-код короткой ссылки в PNG или SVG. Параметры запроса: size в пикселях, format png или svg,
+// ec - уровень коррекции l, m, q или h, margin - отступ в модулях.
+// 400 при неверных параметрах, 404 для несуществующих, 410 для удаленных, истекших и исчерпавших переходы ссылок
+func (h *Handler) GetQR(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	size, margin := 0, -1
+	var err error
+	if s := query.Get("size"); s != "" {
+		if size, err = strconv.Atoi(s); err != nil {
+			http.Error(w, module.ErrWrongQR.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if s := query.Get("margin"); s != "" {
+		if margin, err = strconv.Atoi(s); err != nil || margin < 0 {
+			http.Error(w, module.ErrWrongQR.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	opts, err := module.NewQROptions(size, query.Get("format"), query.Get("ec"), margin)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	short := chi.URLParam(r, "id")
+	url, err := h.Storage.GetURL(r.Context(), short)
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("GetQR GetURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	if url.Deleted || url.Expired(now) || url.Exhausted() {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+
+	content := h.BaseURL + "/" + short
+	etag := opts.ETag(content)
+	maxAge := qrMaxAge
+	if !url.ExpiresAt.IsZero() && url.ExpiresAt.Sub(now) < maxAge {
+		maxAge = url.ExpiresAt.Sub(now)
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	img, err := module.RenderQR(content, opts)
+	if err != nil {
+		h.logger.Error("RenderQR error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", opts.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(img)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(img)
+}
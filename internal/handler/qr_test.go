@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_GetQR(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user1",
+		ExpiresAt: time.Now().Add(-time.Minute)}))
+
+	r := chi.NewRouter()
+	r.Get("/{id}/qr", h.GetQR)
+	do := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/aaaaaaaa/qr?size=300", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	require.Equal(t, "public, max-age=86400", w.Header().Get("Cache-Control"))
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+	require.NoError(t, err)
+	require.LessOrEqual(t, img.Bounds().Dx(), 300)
+
+	w = do("/aaaaaaaa/qr?size=300", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, w.Code)
+	require.Empty(t, w.Body.Bytes())
+
+	w = do("/aaaaaaaa/qr?format=svg&ec=h&margin=0", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	require.True(t, strings.HasPrefix(w.Body.String(), "<?xml"))
+	require.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	for _, query := range []string{"size=abc", "size=10", "format=gif", "ec=x", "margin=-1"} {
+		require.Equal(t, http.StatusBadRequest, do("/aaaaaaaa/qr?"+query, nil).Code, query)
+	}
+	require.Equal(t, http.StatusNotFound, do("/cccccccc/qr", nil).Code)
+	require.Equal(t, http.StatusGone, do("/bbbbbbbb/qr", nil).Code)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+)
+
+type urlRulesInput struct {
+	Rules []domain.RedirectRule `json:"rules"`
+}
+
+// destination выбирает адрес перехода по правилам ссылки для посетителя запроса, а если не подошло ни одно -
+// по вариантам A/B-разделения. Возвращает адрес и имя варианта, пустое без вариантов. Страна определяется
+// по базе GeoIP, только если она задана и есть правила по стране. Ответ зависит от заголовков и cookie посетителя,
+// поэтому для ссылок с правилами и вариантами ставится Vary
+func (h *Handler) destination(w http.ResponseWriter, r *http.Request, url domain.URL) (string, string) {
+	if len(url.Rules) == 0 && len(url.Variants) == 0 {
+		return url.Long, ""
+	}
+	var country string
+	if len(url.Rules) > 0 {
+		w.Header().Add("Vary", "User-Agent, Accept-Language")
+	}
+	if h.geo != nil && module.NeedsCountry(url.Rules) {
+		if ip := net.ParseIP(clientIP(r)); ip != nil {
+			var err error
+			if country, err = h.geo.Country(ip); err != nil {
+				h.logger.Info("GeoIP lookup error", zap.String("IP", ip.String()), zap.Error(err))
+			}
+		}
+	}
+	visitor := module.NewVisitor(r.UserAgent(), r.Header.Get("Accept-Language"), country)
+	if len(url.Variants) > 0 {
+		w.Header().Add("Vary", "Cookie")
+		visitor.ID = visitorID(r)
+	}
+	return module.Destination(url, visitor)
+}
+
+// PutURLRules заменяет упорядоченный список правил перенаправления ссылки текущего пользователя,
+// пустой список убирает правила. Возвращает JSON со ссылкой, 400 для неверных правил,
+// 404 для чужих, удаленных и несуществующих ссылок
+func (h *Handler) PutURLRules(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in urlRulesInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	rules, err := module.NormalizeRules(in.Rules)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	url, err := h.Storage.SetURLRules(r.Context(), user, chi.URLParam(r, "id"), rules)
+	h.writeLink(w, url, err)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_Rules(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "aaaaaaaa", Long: "https://example.com", User: "user1"}))
+
+	r := chi.NewRouter()
+	r.Get("/{id}", h.GetURL)
+	r.Put("/api/user/urls/{id}/rules", h.PutURLRules)
+	put := func(user, short, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/api/user/urls/"+short+"/rules", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	get := func(userAgent, acceptLanguage string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/aaaaaaaa", nil)
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	rules := `{"rules":[
+		{"platform":"iOS","url":"https://apps.apple.com/app/id1"},
+		{"platform":"android","url":"https://play.google.com/store/apps/details?id=app"},
+		{"country":"de","url":"https://example.de"},
+		{"language":"ru","url":"https://example.com/ru"}]}`
+	require.Equal(t, http.StatusNotFound, put("user2", "aaaaaaaa", rules).Code)
+	require.Equal(t, http.StatusBadRequest, put("user1", "aaaaaaaa", `{"rules":[{"url":"https://example.com/any"}]}`).Code)
+	require.Equal(t, http.StatusBadRequest, put("user1", "aaaaaaaa", `{"rules":[{"platform":"ios","url":"bad"}]}`).Code)
+	w := put("user1", "aaaaaaaa", rules)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `{"platform":"ios","url":"https://apps.apple.com/app/id1"}`)
+	require.Contains(t, w.Body.String(), `{"country":"DE","url":"https://example.de"}`)
+
+	for _, tt := range []struct {
+		userAgent, acceptLanguage, want string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_5 like Mac OS X)", "ru-RU", "https://apps.apple.com/app/id1"},
+		{"Mozilla/5.0 (Linux; Android 13; Pixel 7)", "", "https://play.google.com/store/apps/details?id=app"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64)", "ru-RU,ru;q=0.9,en;q=0.8", "https://example.com/ru"},
+		// без базы GeoIP правила по стране не срабатывают
+		{"Mozilla/5.0 (X11; Linux x86_64)", "de-DE", "https://example.com"},
+	} {
+		w = get(tt.userAgent, tt.acceptLanguage)
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		require.Equal(t, tt.want, w.Header().Get("Location"), tt.userAgent)
+		require.Equal(t, "User-Agent, Accept-Language", w.Header().Get("Vary"))
+	}
+
+	w = put("user1", "aaaaaaaa", `{"rules":[]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), `"rules"`)
+	w = get("Mozilla/5.0 (iPhone; CPU iPhone OS 16_5 like Mac OS X)", "")
+	require.Equal(t, "https://example.com", w.Header().Get("Location"))
+	require.Empty(t, w.Header().Get("Vary"))
+}
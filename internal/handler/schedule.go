@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+type soonPage struct {
+	Short     string
+	Title     string
+	NotBefore string
+	Launch    string
+}
+
+// comingSoon отвечает за ссылку, окно активности которой еще не открылось: перенаправляет на запасной URL,
+// если он задан, иначе отдает страницу "скоро" с 503 и Retry-After до начала окна. Переход не учитывается
+func (h *Handler) comingSoon(w http.ResponseWriter, r *http.Request, url domain.URL, now time.Time) {
+	if url.Fallback != "" {
+		w.Header().Set("Cache-Control", "no-store")
+		http.Redirect(w, r, url.Fallback, http.StatusTemporaryRedirect)
+		return
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(url.NotBefore.Sub(now).Seconds())+1))
+	h.renderHTML(w, http.StatusServiceUnavailable, soonTemplate, soonPage{
+		Short:     strings.TrimPrefix(strings.TrimPrefix(h.BaseURL, "https://"), "http://") + "/" + url.Short,
+		Title:     url.Title,
+		NotBefore: url.NotBefore.UTC().Format(time.RFC3339),
+		Launch:    url.NotBefore.UTC().Format("January 2, 2006 15:04 MST"),
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_Schedule(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+
+	r := chi.NewRouter()
+	r.Post("/api/shorten", h.PostJSON)
+	r.Get("/{id}", h.GetURL)
+	r.Get("/{id}+", h.GetPreview)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	launch := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	end := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	post := func(body string) int {
+		return do("POST", "/api/shorten", body).Code
+	}
+	require.Equal(t, http.StatusCreated, post(fmt.Sprintf(`{"url":"http://a.ru","alias":"soon","title":"Sale","not_before":%q,"not_after":%q}`,
+		launch, end)))
+	require.Equal(t, http.StatusCreated, post(fmt.Sprintf(`{"url":"http://b.ru","alias":"fallback","not_before":%q,"fallback_url":"http://b.ru/wait"}`,
+		launch)))
+	require.Equal(t, http.StatusBadRequest, post(fmt.Sprintf(`{"url":"http://c.ru","not_before":%q,"not_after":%q}`, end, launch)))
+	require.Equal(t, http.StatusBadRequest, post(fmt.Sprintf(`{"url":"http://c.ru","not_after":%q,"expires_at":%q}`, end, end)))
+	require.Equal(t, http.StatusBadRequest, post(`{"url":"http://c.ru","fallback_url":"http://c.ru/wait"}`))
+
+	w := do("GET", "/soon", "")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	retry, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	require.InDelta(t, 3600, retry, 5)
+	require.Contains(t, w.Body.String(), "Sale")
+	require.Contains(t, w.Body.String(), launch)
+	require.NotContains(t, w.Body.String(), "http://a.ru")
+	require.Equal(t, http.StatusServiceUnavailable, do("GET", "/soon+", "").Code)
+
+	w = do("GET", "/fallback", "")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	require.Equal(t, "http://b.ru/wait", w.Header().Get("Location"))
+
+	url, err := h.Storage.GetURL(context.Background(), "soon")
+	require.NoError(t, err)
+	require.Equal(t, end, url.ExpiresAt.Format(time.RFC3339))
+	b, err := json.Marshal(h.link(url))
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"not_before":"`+launch+`"`)
+	require.Contains(t, string(b), `"expires_at":"`+end+`"`)
+
+	// после начала окна ссылка работает как обычно
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "started", Long: "http://d.ru", User: "user1",
+		NotBefore: time.Now().Add(-time.Minute), Fallback: "http://d.ru/wait"}))
+	w = do("GET", "/started", "")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	require.Equal(t, "http://d.ru", w.Header().Get("Location"))
+}
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+)
+
+type label struct {
+	Name      string    `json:"name"`
+	Links     int       `json:"links"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type labelInput struct {
+	Name string `json:"name"`
+}
+
+type urlTagsInput struct {
+	Tags []string `json:"tags"`
+}
+
+type urlFolderInput struct {
+	Folder string `json:"folder"`
+}
+
+// nameParam возвращает имя метки или папки из пути. Имя может содержать экранированные символы
+func nameParam(r *http.Request) string {
+	name := chi.URLParam(r, "name")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		return unescaped
+	}
+	return name
+}
+
+// decodeBody читает JSON тела запроса в v. При ошибке отвечает 400 и возвращает false
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	b, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeJSON отвечает JSON с кодом status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	resJSON, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(resJSON)
+}
+
+// GetTags возвращает JSON с метками текущего пользователя и количеством их ссылок. 204 если меток нет
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tags, err := h.Storage.ListTags(r.Context(), user)
+	if err != nil {
+		h.logger.Error("ListTags error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(tags) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	res := make([]label, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, label{Name: tag.Name, Links: tag.Links, CreatedAt: tag.CreatedAt})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// PostTag создает метку текущего пользователя. Возвращает 201 с меткой, 409 если метка уже есть
+func (h *Handler) PostTag(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in labelInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	name, err := module.NormalizeTag(in.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tag, err := h.Storage.CreateTag(r.Context(), user, name)
+	if errors.Is(err, domain.ErrTagExists) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.logger.Error("CreateTag error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, label{Name: tag.Name, Links: tag.Links, CreatedAt: tag.CreatedAt})
+}
+
+// PatchTag переименовывает метку текущего пользователя вместе с ее ссылками.
+// 404 если метки нет, 409 если новое имя занято
+func (h *Handler) PatchTag(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in labelInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	newName, err := module.NormalizeTag(in.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tag, err := h.Storage.RenameTag(r.Context(), user, nameParam(r), newName)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "tag not found", http.StatusNotFound)
+		return
+	case errors.Is(err, domain.ErrTagExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		h.logger.Error("RenameTag error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, label{Name: tag.Name, Links: tag.Links, CreatedAt: tag.CreatedAt})
+}
+
+// DeleteTag удаляет метку текущего пользователя и снимает ее со ссылок. 204 при успехе, 404 если метки нет
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = h.Storage.DeleteTag(r.Context(), user, nameParam(r))
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "tag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("DeleteTag error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFolders возвращает JSON с папками текущего пользователя и количеством их ссылок. 204 если папок нет
+func (h *Handler) GetFolders(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	folders, err := h.Storage.ListFolders(r.Context(), user)
+	if err != nil {
+		h.logger.Error("ListFolders error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(folders) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	res := make([]label, 0, len(folders))
+	for _, folder := range folders {
+		res = append(res, label{Name: folder.Name, Links: folder.Links, CreatedAt: folder.CreatedAt})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// DeleteFolder удаляет папку текущего пользователя, ссылки из нее остаются вне папок. 204 при успехе, 404 если папки нет
+func (h *Handler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = h.Storage.DeleteFolder(r.Context(), user, nameParam(r))
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "folder not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("DeleteFolder error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PutURLTags заменяет метки ссылки текущего пользователя, новые метки создаются. Возвращает JSON со ссылкой,
+// 404 для чужих, удаленных и несуществующих ссылок
+func (h *Handler) PutURLTags(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in urlTagsInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	_, tags, err := module.NormalizeMeta("", in.Tags)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	url, err := h.Storage.SetURLTags(r.Context(), user, chi.URLParam(r, "id"), tags)
+	h.writeLink(w, url, err)
+}
+
+// PutURLFolder переносит ссылку текущего пользователя в папку, пустое имя - вне папок. Новая папка создается.
+// Возвращает JSON со ссылкой, 404 для чужих, удаленных и несуществующих ссылок
+func (h *Handler) PutURLFolder(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in urlFolderInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	folder, err := module.NormalizeFolder(in.Folder)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	url, err := h.Storage.SetURLFolder(r.Context(), user, chi.URLParam(r, "id"), folder)
+	h.writeLink(w, url, err)
+}
+
+// writeLink отвечает ссылкой после ее изменения или ошибкой хранилища
+func (h *Handler) writeLink(w http.ResponseWriter, url domain.URL, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("update url error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.link(url))
+}
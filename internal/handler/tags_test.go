@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_Tags(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1", Tags: []string{"go"}}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user1"}))
+
+	r := chi.NewRouter()
+	r.Get("/api/user/urls", h.GetURLsByUser)
+	r.Put("/api/user/urls/{id}/tags", h.PutURLTags)
+	r.Put("/api/user/urls/{id}/folder", h.PutURLFolder)
+	r.Get("/api/user/tags", h.GetTags)
+	r.Post("/api/user/tags", h.PostTag)
+	r.Patch("/api/user/tags/{name}", h.PatchTag)
+	r.Delete("/api/user/tags/{name}", h.DeleteTag)
+	r.Get("/api/user/folders", h.GetFolders)
+	r.Delete("/api/user/folders/{name}", h.DeleteFolder)
+	do := func(method, user, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "user1", "/api/user/tags", `{"name":"News"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, http.StatusConflict, do("POST", "user1", "/api/user/tags", `{"name":"go"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do("POST", "user1", "/api/user/tags", `{"name":"two words"}`).Code)
+
+	w = do("PUT", "user1", "/api/user/urls/bbbbbbbb/tags", `{"tags":["go","News"]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var l link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &l))
+	require.Equal(t, []string{"go", "news"}, l.Tags)
+	require.Equal(t, http.StatusNotFound, do("PUT", "user2", "/api/user/urls/bbbbbbbb/tags", `{"tags":["go"]}`).Code)
+
+	w = do("GET", "user1", "/api/user/tags", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var tags []label
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+	require.Len(t, tags, 2)
+	require.Equal(t, "go", tags[0].Name)
+	require.Equal(t, 2, tags[0].Links)
+	require.Equal(t, http.StatusNoContent, do("GET", "user2", "/api/user/tags", "").Code)
+
+	w = do("PATCH", "user1", "/api/user/tags/go", `{"name":"golang"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, http.StatusConflict, do("PATCH", "user1", "/api/user/tags/golang", `{"name":"news"}`).Code)
+	require.Equal(t, http.StatusNotFound, do("PATCH", "user1", "/api/user/tags/go", `{"name":"go2"}`).Code)
+
+	w = do("GET", "user1", "/api/user/urls?tag=golang&tag=news", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	requireLinks(t, `[{"short_url":"http://localhost:8080/bbbbbbbb","original_url":"http://b.ru","tags":["golang","news"]}]`,
+		w.Body.String())
+
+	w = do("PUT", "user1", "/api/user/urls/aaaaaaaa/folder", `{"folder":"Рабочие ссылки"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = do("GET", "user1", "/api/user/urls?folder="+"%D0%A0%D0%B0%D0%B1%D0%BE%D1%87%D0%B8%D0%B5%20%D1%81%D1%81%D1%8B%D0%BB%D0%BA%D0%B8", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	requireLinks(t, `[{"short_url":"http://localhost:8080/aaaaaaaa","original_url":"http://a.ru","tags":["golang"],
+		"folder":"Рабочие ссылки"}]`, w.Body.String())
+	w = do("GET", "user1", "/api/user/folders", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"links":1`)
+
+	require.Equal(t, http.StatusNoContent,
+		do("DELETE", "user1", "/api/user/folders/%D0%A0%D0%B0%D0%B1%D0%BE%D1%87%D0%B8%D0%B5%20%D1%81%D1%81%D1%8B%D0%BB%D0%BA%D0%B8", "").Code)
+	require.Equal(t, http.StatusNotFound, do("DELETE", "user1", "/api/user/folders/old", "").Code)
+	require.Equal(t, http.StatusNoContent, do("DELETE", "user1", "/api/user/tags/golang", "").Code)
+	require.Equal(t, http.StatusNotFound, do("DELETE", "user1", "/api/user/tags/golang", "").Code)
+	url, _ := h.Storage.GetURL(ctx, "aaaaaaaa")
+	require.Empty(t, url.Tags)
+	require.Empty(t, url.Folder)
+}
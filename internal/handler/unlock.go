@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+)
+
+// unlockTTL - срок действия cookie разблокировки защищенной ссылки
+const unlockTTL = 10 * time.Minute
+
+type unlockPage struct {
+	Short  string
+	Action string
+	Error  string
+}
+
+type urlPasswordInput struct {
+	Password string `json:"password"`
+}
+
+// unlockCookieName возвращает имя cookie разблокировки ссылки
+func unlockCookieName(short string) string {
+	return "unlock_" + short
+}
+
+// unlockSign подписывает разблокировку ссылки до момента expires. В подпись входит хэш пароля,
+// поэтому смена пароля отзывает выданные cookie
+func unlockSign(secretKey string, url domain.URL, expires int64) []byte {
+	h := hmac.New(sha256.New, []byte(secretKey))
+	fmt.Fprintf(h, "%s|%s|%d", url.Short, url.Password, expires)
+	return h.Sum(nil)
+}
+
+// unlocked проверяет cookie разблокировки ссылки
+func (h *Handler) unlocked(r *http.Request, url domain.URL) bool {
+	cookie, err := r.Cookie(unlockCookieName(url.Short))
+	if err != nil {
+		return false
+	}
+	return validSigned(cookie.Value, func(expires int64) []byte {
+		return unlockSign(h.SecretKey, url, expires)
+	})
+}
+
+// validSigned проверяет значение вида срок.HMAC: срок в секундах Unix не истек, а подпись совпадает с sign
+func validSigned(value string, sign func(expires int64) []byte) bool {
+	expiresPart, signPart, ok := strings.Cut(value, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiresPart, 10, 64)
+	if err != nil || time.Now().Unix() >= expires {
+		return false
+	}
+	got, err := hex.DecodeString(signPart)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(sign(expires), got)
+}
+
+// renderUnlock отвечает формой ввода пароля ссылки
+func (h *Handler) renderUnlock(w http.ResponseWriter, status int, url domain.URL, message string) {
+	h.renderHTML(w, status, unlockTemplate, unlockPage{
+		Short:  url.Short,
+		Action: h.BaseURL + "/" + url.Short,
+		Error:  message,
+	})
+}
+
+// PostUnlock проверяет пароль защищенной ссылки из формы. При верном пароле ставит подписанную cookie
+// разблокировки и перенаправляет на короткую ссылку с 303. Неверный пароль - форма с 403.
+// Попытка учитывается до проверки пароля: после PasswordAttempts попыток без успеха за PasswordWindow
+// попытки по ссылке, в том числе параллельные, отклоняются с 429.
+// 404 для несуществующих, 410 для удаленных, истекших и исчерпавших переходы ссылок
+func (h *Handler) PostUnlock(w http.ResponseWriter, r *http.Request) {
+	short := chi.URLParam(r, "id")
+	url, err := h.Storage.GetURL(r.Context(), short)
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "url not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("PostUnlock GetURL error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	if url.Deleted || url.Expired(now) || url.Exhausted() {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	location := h.BaseURL + "/" + short
+	if !url.Protected() {
+		http.Redirect(w, r, location, http.StatusSeeOther)
+		return
+	}
+	if retry, ok := h.unlocks.Allow(short, now); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+		h.renderUnlock(w, http.StatusTooManyRequests, url, "Too many attempts, try again later.")
+		return
+	}
+	if !module.CheckPassword(url.Password, r.PostFormValue("password")) {
+		h.renderUnlock(w, http.StatusForbidden, url, "Wrong password.")
+		return
+	}
+	h.unlocks.Reset(short)
+	expires := now.Add(unlockTTL).Unix()
+	http.SetCookie(w, &http.Cookie{
+		Name:     unlockCookieName(short),
+		Value:    fmt.Sprintf("%d.%x", expires, unlockSign(h.SecretKey, url, expires)),
+		Path:     "/", // и для страницы предпросмотра /{id}+
+		MaxAge:   int(unlockTTL.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, location, http.StatusSeeOther)
+}
+
+// PutURLPassword задает пароль ссылки текущего пользователя, пустой пароль снимает защиту. Возвращает JSON со ссылкой,
+// 404 для чужих, удаленных и несуществующих ссылок
+func (h *Handler) PutURLPassword(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in urlPasswordInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	hash, err := module.HashPassword(in.Password)
+	if module.IsInputError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("HashPassword error", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	url, err := h.Storage.SetURLPassword(r.Context(), user, chi.URLParam(r, "id"), hash)
+	h.writeLink(w, url, err)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_Unlock(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	hash, err := module.HashPassword("secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1", Password: hash}))
+	require.NoError(t, h.Storage.SetURL(ctx, domain.URL{Short: "bbbbbbbb", Long: "http://b.ru", User: "user1", Password: hash,
+		Preview: true}))
+
+	r := chi.NewRouter()
+	r.Get("/{id}", h.GetURL)
+	r.Post("/{id}", h.PostUnlock)
+	r.Get("/{id}+", h.GetPreview)
+	r.Put("/api/user/urls/{id}/password", h.PutURLPassword)
+	do := func(method, path, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+		if method == http.MethodPost {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	form := func(password string) string {
+		return url.Values{"password": {password}}.Encode()
+	}
+
+	w := do("GET", "/aaaaaaaa", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `action="http://localhost:8080/aaaaaaaa"`)
+	require.NotContains(t, w.Body.String(), "a.ru")
+	require.NotContains(t, do("GET", "/aaaaaaaa+", "").Body.String(), "http://a.ru")
+
+	require.Equal(t, http.StatusForbidden, do("POST", "/aaaaaaaa", form("wrong")).Code)
+	w = do("POST", "/aaaaaaaa", form("secret"))
+	require.Equal(t, http.StatusSeeOther, w.Code)
+	require.Equal(t, "http://localhost:8080/aaaaaaaa", w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	unlock := cookies[0]
+	require.Equal(t, "unlock_aaaaaaaa", unlock.Name)
+	require.True(t, unlock.HttpOnly)
+
+	w = do("GET", "/aaaaaaaa", "", unlock)
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	require.Equal(t, "http://a.ru", w.Header().Get("Location"))
+	require.Contains(t, do("GET", "/aaaaaaaa+", "", unlock).Body.String(), "http://a.ru")
+
+	// cookie одной ссылки не открывает другую, подделанная подпись не проходит
+	require.Equal(t, http.StatusOK, do("GET", "/bbbbbbbb", "", &http.Cookie{Name: "unlock_bbbbbbbb", Value: unlock.Value}).Code)
+	forged := &http.Cookie{Name: unlock.Name, Value: unlock.Value[:len(unlock.Value)-1] + "0"}
+	if forged.Value == unlock.Value {
+		forged.Value = unlock.Value[:len(unlock.Value)-1] + "1"
+	}
+	require.Equal(t, http.StatusOK, do("GET", "/aaaaaaaa", "", forged).Code)
+
+	// после разблокировки действует предпросмотр
+	w = do("POST", "/bbbbbbbb", form("secret"))
+	require.Equal(t, http.StatusSeeOther, w.Code)
+	w = do("GET", "/bbbbbbbb", "", w.Result().Cookies()[0])
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "?continue=")
+
+	// смена пароля отзывает cookie
+	w = do("PUT", "/api/user/urls/aaaaaaaa/password", `{"password":"another"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"protected":true`)
+	require.NotContains(t, w.Body.String(), "$2a$")
+	require.Equal(t, http.StatusOK, do("GET", "/aaaaaaaa", "", unlock).Code)
+	require.Equal(t, http.StatusBadRequest, do("PUT", "/api/user/urls/aaaaaaaa/password", `{"password":"abc"}`).Code)
+	w = do("PUT", "/api/user/urls/aaaaaaaa/password", `{"password":""}`)
+	require.NotContains(t, w.Body.String(), `"protected"`)
+	require.Equal(t, http.StatusTemporaryRedirect, do("GET", "/aaaaaaaa", "").Code)
+	require.Equal(t, http.StatusSeeOther, do("POST", "/aaaaaaaa", form("")).Code)
+	require.Equal(t, http.StatusNotFound, do("POST", "/cccccccc", form("secret")).Code)
+}
+
+func TestHandler_UnlockThrottle(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	hash, err := module.HashPassword("secret")
+	require.NoError(t, err)
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "aaaaaaaa", Long: "http://a.ru", User: "user1",
+		Password: hash}))
+
+	r := chi.NewRouter()
+	r.Post("/{id}", h.PostUnlock)
+	do := func(password string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/aaaaaaaa", strings.NewReader(url.Values{"password": {password}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	for i := 0; i < module.PasswordAttempts; i++ {
+		require.Equal(t, http.StatusForbidden, do("wrong").Code)
+	}
+	w := do("secret")
+	require.Equal(t, http.StatusTooManyRequests, w.Code, "верный пароль тоже отклоняется до конца окна")
+	require.NotEmpty(t, w.Header().Get("Retry-After"))
+}
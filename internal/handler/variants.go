@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+)
+
+type urlVariantsInput struct {
+	Variants []domain.Variant `json:"variants"`
+}
+
+// visitorID возвращает постоянный идентификатор посетителя для выбора варианта: cookie id, которую CheckCookies
+// ставит каждому посетителю. Без cookie - IP и User-Agent, чтобы повторный переход попал в тот же вариант
+func visitorID(r *http.Request) string {
+	if id, err := getUserIDFROMCookie(r); err == nil && id != "" {
+		return id
+	}
+	return clientIP(r) + "|" + r.UserAgent()
+}
+
+// PutURLVariants заменяет варианты A/B-разделения ссылки текущего пользователя, пустой список убирает разделение.
+// Возвращает JSON со ссылкой, 400 для неверных вариантов, 404 для чужих, удаленных и несуществующих ссылок
+func (h *Handler) PutURLVariants(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserIDFROMCookie(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var in urlVariantsInput
+	if !decodeBody(w, r, &in) {
+		return
+	}
+	variants, err := module.NormalizeVariants(in.Variants)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	url, err := h.Storage.SetURLVariants(r.Context(), user, chi.URLParam(r, "id"), variants)
+	h.writeLink(w, url, err)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+	"github.com/Spear5030/yapshrtnr/internal/module"
+	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
+	"github.com/Spear5030/yapshrtnr/pkg/logger"
+)
+
+func TestHandler_Variants(t *testing.T) {
+	cfg, err := config.New()
+	require.NoError(t, err)
+	lg, _ := logger.New(true)
+	h := New(lg, testStorage.NewMemoryStorage(), "http://localhost:8080", cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
+	require.NoError(t, h.Storage.SetURL(context.Background(), domain.URL{Short: "aaaaaaaa", Long: "https://example.com", User: "user1"}))
+
+	r := chi.NewRouter()
+	r.Get("/{id}", h.GetURL)
+	r.Get("/api/user/urls/{id}/stats", h.GetURLStats)
+	r.Put("/api/user/urls/{id}/variants", h.PutURLVariants)
+	put := func(user, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/api/user/urls/aaaaaaaa/variants", strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "id", Value: user})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	get := func(visitor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/aaaaaaaa", nil)
+		req.AddCookie(&http.Cookie{Name: "id", Value: visitor})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	variants := `{"variants":[
+		{"name":"Control","url":"https://example.com/a","weight":3},
+		{"name":"new","url":"https://example.com/b","weight":1}]}`
+	require.Equal(t, http.StatusNotFound, put("user2", variants).Code)
+	require.Equal(t, http.StatusBadRequest, put("user1", `{"variants":[{"name":"a","url":"https://example.com/a","weight":1}]}`).Code)
+	require.Equal(t, http.StatusBadRequest, put("user1", `{"variants":[
+		{"name":"a","url":"https://example.com/a","weight":1},{"name":"b","url":"bad","weight":1}]}`).Code)
+	w := put("user1", variants)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `{"name":"control","url":"https://example.com/a","weight":3}`)
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		visitor := fmt.Sprintf("visitor%d", i)
+		w = get(visitor)
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		location := w.Header().Get("Location")
+		require.Equal(t, location, get(visitor).Header().Get("Location"), "visitor keeps the variant")
+		require.Contains(t, w.Header().Values("Vary"), "Cookie")
+		counts[location] += 2
+	}
+	require.InDelta(t, 600, counts["https://example.com/a"], 80)
+	require.InDelta(t, 200, counts["https://example.com/b"], 80)
+
+	req := httptest.NewRequest("GET", "/api/user/urls/aaaaaaaa/stats", nil)
+	req.AddCookie(&http.Cookie{Name: "id", Value: "user1"})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats urlStatsResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	require.Equal(t, 800, stats.Total)
+	require.Equal(t, []variantClicks{
+		{Variant: "control", Clicks: counts["https://example.com/a"], Unique: 1},
+		{Variant: "new", Clicks: counts["https://example.com/b"], Unique: 1},
+	}, stats.Variants)
+
+	w = put("user1", `{"variants":[]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), `"variants"`)
+	require.Equal(t, "https://example.com", get("visitor1").Header().Get("Location"))
+}
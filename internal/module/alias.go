@@ -0,0 +1,43 @@
+package module
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrWrongAlias алиас не проходит проверку символов и длины
+	ErrWrongAlias = errors.New("module: alias must be 3-64 characters of a-z, A-Z, 0-9, '-' or '_'")
+	// ErrReservedAlias алиас совпадает с зарезервированным словом
+	ErrReservedAlias = errors.New("module: alias is reserved")
+	// ErrAliasTaken алиас уже занят
+	ErrAliasTaken = errors.New("module: alias already taken")
+)
+
+var aliasRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{3,64}$`)
+
+// reservedAliases первые сегменты путей роутера и слова, которые могут ими стать
+var reservedAliases = map[string]struct{}{
+	"api":      {},
+	"debug":    {},
+	"ping":     {},
+	"static":   {},
+	"admin":    {},
+	"internal": {},
+	"user":     {},
+	"shorten":  {},
+	"health":   {},
+	"metrics":  {},
+}
+
+// ValidateAlias проверяет пользовательский алиас короткой ссылки
+func ValidateAlias(alias string) error {
+	if !aliasRegexp.MatchString(alias) {
+		return ErrWrongAlias
+	}
+	if _, ok := reservedAliases[strings.ToLower(alias)]; ok {
+		return ErrReservedAlias
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+func TestValidateAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		want  error
+	}{
+		{alias: "spring-sale", want: nil},
+		{alias: "Promo_2023", want: nil},
+		{alias: "ab", want: ErrWrongAlias},
+		{alias: "spring sale", want: ErrWrongAlias},
+		{alias: "spring+", want: ErrWrongAlias},
+		{alias: "ping", want: ErrReservedAlias},
+		{alias: "API", want: ErrReservedAlias},
+		{alias: "debug", want: ErrReservedAlias},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateAlias(tt.alias))
+		})
+	}
+}
+
+func TestShortener_Alias(t *testing.T) {
+	s := NewShortener(RandomGenerator{})
+	short, err := s.Short("http://a.ru", "spring-sale", func(short string) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "spring-sale", short)
+
+	_, err = s.Short("http://a.ru", "spring-sale", func(short string) error {
+		return domain.NewShortExistsError(short)
+	})
+	require.ErrorIs(t, err, ErrAliasTaken)
+
+	shorts, err := s.ShortBatch([]string{"http://a.ru", "http://b.ru"}, []string{"", "summer"}, func(shorts []string) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "summer", shorts[1])
+
+	_, err = s.ShortBatch([]string{"http://a.ru", "http://b.ru"}, []string{"", "summer"}, func(shorts []string) error {
+		return domain.NewShortExistsError(shorts[1])
+	})
+	require.ErrorIs(t, err, ErrAliasTaken)
+
+	_, err = s.ShortBatch([]string{"http://a.ru", "http://b.ru"}, []string{"same", "same"}, func(shorts []string) error { return nil })
+	require.ErrorIs(t, err, ErrAliasTaken)
+}
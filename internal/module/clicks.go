@@ -0,0 +1,18 @@
+package module
+
+import "errors"
+
+// ErrWrongMaxClicks ограничение переходов по ссылке не проходит проверку
+var ErrWrongMaxClicks = errors.New("module: max_clicks must be 0-1000000")
+
+// maxClicksLimit - наибольшее ограничение переходов по ссылке
+const maxClicksLimit = 1000000
+
+// ValidateMaxClicks проверяет ограничение переходов по ссылке. 0 - без ограничения, 1 - одноразовая ссылка.
+// Ошибка - ErrWrongMaxClicks
+func ValidateMaxClicks(maxClicks int) error {
+	if maxClicks < 0 || maxClicks > maxClicksLimit {
+		return ErrWrongMaxClicks
+	}
+	return nil
+}
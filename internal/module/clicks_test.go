@@ -0,0 +1,18 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMaxClicks(t *testing.T) {
+	for _, n := range []int{0, 1, 100, maxClicksLimit} {
+		require.NoError(t, ValidateMaxClicks(n))
+	}
+	for _, n := range []int{-1, maxClicksLimit + 1} {
+		err := ValidateMaxClicks(n)
+		require.ErrorIs(t, err, ErrWrongMaxClicks)
+		require.True(t, IsInputError(err))
+	}
+}
@@ -0,0 +1,31 @@
+package module
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrWrongExpiry некорректный срок действия ссылки
+var ErrWrongExpiry = errors.New("module: expiry must be either positive ttl or expires_at in the future")
+
+// MaxTTL наибольший ttl в секундах: больший не помещается в time.Duration
+const MaxTTL = int64(math.MaxInt64 / int64(time.Second))
+
+// ExpiresAt вычисляет момент истечения ссылки по ttl в секундах или по абсолютному времени expiresAt.
+// Можно задать только одно из двух, ttl - не больше MaxTTL. Если не задано ничего - возвращает нулевое время, ссылка бессрочная
+func ExpiresAt(ttl int64, expiresAt time.Time, now time.Time) (time.Time, error) {
+	switch {
+	case ttl == 0 && expiresAt.IsZero():
+		return time.Time{}, nil
+	case ttl != 0 && !expiresAt.IsZero():
+		return time.Time{}, ErrWrongExpiry
+	case ttl < 0 || ttl > MaxTTL:
+		return time.Time{}, ErrWrongExpiry
+	case ttl > 0:
+		return now.Add(time.Duration(ttl) * time.Second).UTC(), nil
+	case !expiresAt.After(now):
+		return time.Time{}, ErrWrongExpiry
+	}
+	return expiresAt.UTC(), nil
+}
@@ -0,0 +1,42 @@
+package module
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiresAt(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		ttl       int64
+		expiresAt time.Time
+		want      time.Time
+		wantErr   bool
+	}{
+		{name: "no expiry"},
+		{name: "ttl", ttl: 60, want: now.Add(time.Minute)},
+		{name: "absolute", expiresAt: now.Add(time.Hour), want: now.Add(time.Hour)},
+		{name: "negative ttl", ttl: -1, wantErr: true},
+		{name: "max ttl", ttl: MaxTTL, want: now.Add(time.Duration(MaxTTL) * time.Second)},
+		{name: "ttl overflow", ttl: MaxTTL + 1, wantErr: true},
+		{name: "huge ttl", ttl: math.MaxInt64, wantErr: true},
+		{name: "in the past", expiresAt: now.Add(-time.Second), wantErr: true},
+		{name: "both", ttl: 60, expiresAt: now.Add(time.Hour), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpiresAt(tt.ttl, tt.expiresAt, now)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrWrongExpiry)
+				require.True(t, IsInputError(err))
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package module
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	symBytes    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	shortLength = 8
+)
+
+// Стратегии генерации коротких идентификаторов
+const (
+	StrategyRandom  = "random"
+	StrategyCounter = "counter"
+	StrategyHash    = "hash"
+)
+
+// Generator генерирует короткий идентификатор для URL. attempt - номер попытки, растет при коллизиях
+type Generator interface {
+	Generate(longURL string, attempt int) (string, error)
+}
+
+// NewGenerator возвращает генератор по названию стратегии. Пустая строка - StrategyRandom
+func NewGenerator(strategy string) (Generator, error) {
+	switch strategy {
+	case "", StrategyRandom:
+		return RandomGenerator{}, nil
+	case StrategyCounter:
+		return NewCounterGenerator(uint64(time.Now().UnixMilli())), nil
+	case StrategyHash:
+		return HashGenerator{}, nil
+	}
+	return nil, fmt.Errorf("module: unknown short id strategy %q", strategy)
+}
+
+// RandomGenerator случайный идентификатор из crypto/rand
+type RandomGenerator struct{}
+
+// Generate возвращает shortLength случайных символов base62
+func (RandomGenerator) Generate(longURL string, attempt int) (string, error) {
+	b := make([]byte, shortLength)
+	buf := make([]byte, shortLength*2)
+	for i := 0; i < len(b); {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, r := range buf {
+			// отбрасываем байты >= 248, чтобы символы были равновероятны (248 = 62*4)
+			if r >= 248 || i == len(b) {
+				continue
+			}
+			b[i] = symBytes[int(r)%len(symBytes)]
+			i++
+		}
+	}
+	return string(b), nil
+}
+
+// CounterGenerator идентификатор - значение счетчика в base62. Значения не повторяются в пределах процесса.
+// Начальное значение стоит брать из времени запуска, чтобы не пересекаться с прошлыми запусками
+type CounterGenerator struct {
+	counter uint64
+}
+
+// NewCounterGenerator возвращает CounterGenerator, начинающий со start
+func NewCounterGenerator(start uint64) *CounterGenerator {
+	return &CounterGenerator{counter: start}
+}
+
+// Generate возвращает следующее значение счетчика
+func (g *CounterGenerator) Generate(longURL string, attempt int) (string, error) {
+	return base62(atomic.AddUint64(&g.counter, 1), 0), nil
+}
+
+// HashGenerator идентификатор - первые символы sha256 от URL. Для повторных попыток к URL добавляется номер попытки
+type HashGenerator struct{}
+
+// Generate возвращает shortLength символов base62 от хеша URL
+func (HashGenerator) Generate(longURL string, attempt int) (string, error) {
+	data := longURL
+	if attempt > 0 {
+		data += "#" + strconv.Itoa(attempt)
+	}
+	sum := sha256.Sum256([]byte(data))
+	return base62(binary.BigEndian.Uint64(sum[:8]), shortLength), nil
+}
+
+// base62 кодирует n. Если width > 0 - результат ровно width символов
+func base62(n uint64, width int) string {
+	var b []byte
+	for n > 0 && (width == 0 || len(b) < width) {
+		b = append(b, symBytes[n%uint64(len(symBytes))])
+		n /= uint64(len(symBytes))
+	}
+	for len(b) < width || len(b) == 0 {
+		b = append(b, symBytes[0])
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
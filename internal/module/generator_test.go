@@ -0,0 +1,76 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+func TestNewGenerator(t *testing.T) {
+	for _, strategy := range []string{"", StrategyRandom, StrategyCounter, StrategyHash} {
+		gen, err := NewGenerator(strategy)
+		require.NoError(t, err)
+		short, err := gen.Generate("http://longlonglonglogn.com/", 0)
+		require.NoError(t, err)
+		assert.Regexp(t, "^[a-zA-Z0-9]+$", short)
+	}
+	_, err := NewGenerator("sequence")
+	require.Error(t, err)
+}
+
+func TestGenerators(t *testing.T) {
+	short, err := RandomGenerator{}.Generate("http://a.ru", 0)
+	require.NoError(t, err)
+	assert.Len(t, short, shortLength)
+
+	counter := NewCounterGenerator(0)
+	first, _ := counter.Generate("http://a.ru", 0)
+	second, _ := counter.Generate("http://a.ru", 0)
+	assert.Equal(t, "b", first)
+	assert.Equal(t, "c", second)
+
+	hash := HashGenerator{}
+	first, _ = hash.Generate("http://a.ru", 0)
+	second, _ = hash.Generate("http://a.ru", 0)
+	assert.Len(t, first, shortLength)
+	assert.Equal(t, first, second)
+	second, _ = hash.Generate("http://a.ru", 1)
+	assert.NotEqual(t, first, second)
+}
+
+func TestShortener_RetryOnCollision(t *testing.T) {
+	s := NewShortener(HashGenerator{})
+	taken, _ := HashGenerator{}.Generate("http://a.ru", 0)
+	var tried []string
+	short, err := s.Short("http://a.ru", "", func(short string) error {
+		tried = append(tried, short)
+		if short == taken {
+			return domain.ErrShortExists
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, taken, short)
+	assert.Len(t, tried, 2)
+
+	_, err = s.Short("http://a.ru", "", func(short string) error {
+		return domain.ErrShortExists
+	})
+	require.ErrorIs(t, err, domain.ErrShortExists)
+
+	_, err = s.Short("not a url", "", func(short string) error { return nil })
+	require.ErrorIs(t, err, ErrWrongURL)
+
+	shorts, err := s.ShortBatch([]string{"http://a.ru", "http://b.ru"}, nil, func(shorts []string) error {
+		if shorts[0] == taken {
+			return domain.ErrShortExists
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, shorts, 2)
+	assert.NotEqual(t, taken, shorts[0])
+}
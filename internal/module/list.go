@@ -0,0 +1,105 @@
+package module
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// Размер страницы списка ссылок
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// ErrWrongListQuery некорректные параметры выборки ссылок
+var ErrWrongListQuery = errors.New("module: wrong list query")
+
+// cursor содержимое курсора. Сортировка сохраняется, чтобы курсор не применялся к другой выборке
+type cursor struct {
+	Sort      domain.ListSort `json:"o"`
+	Desc      bool            `json:"d,omitempty"`
+	Short     string          `json:"s"`
+	CreatedAt time.Time       `json:"t,omitempty"`
+	Clicks    int             `json:"c,omitempty"`
+}
+
+// NewListQuery собирает параметры выборки ссылок из значений запроса. Пустые значения - по умолчанию:
+// DefaultListLimit ссылок, сортировка по короткому идентификатору по возрастанию, только активные ссылки.
+// sort - short, created или clicks; order - asc или desc; status - active, deleted или all.
+// tags и folder отбирают ссылки со всеми метками и из папки. Ошибки - ErrWrongListQuery
+func NewListQuery(limit int, after, sort, order, status, host, search string, tags []string, folder string) (domain.ListQuery, error) {
+	query := domain.ListQuery{
+		Limit:  limit,
+		Sort:   domain.ListSort(sort),
+		Status: domain.ListStatus(status),
+		Domain: host,
+		Search: search,
+	}
+	var err error
+	if _, query.Tags, err = NormalizeMeta("", tags); err != nil {
+		return query, ErrWrongListQuery
+	}
+	if query.Folder, err = NormalizeFolder(folder); err != nil {
+		return query, ErrWrongListQuery
+	}
+	switch {
+	case limit == 0:
+		query.Limit = DefaultListLimit
+	case limit < 0 || limit > MaxListLimit:
+		return query, ErrWrongListQuery
+	}
+	switch query.Sort {
+	case "":
+		query.Sort = domain.SortShort
+	case domain.SortShort, domain.SortCreated, domain.SortClicks:
+	default:
+		return query, ErrWrongListQuery
+	}
+	switch order {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return query, ErrWrongListQuery
+	}
+	switch query.Status {
+	case "":
+		query.Status = domain.StatusActive
+	case domain.StatusActive, domain.StatusDeleted, domain.StatusAll:
+	default:
+		return query, ErrWrongListQuery
+	}
+	if after == "" {
+		return query, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(after)
+	if err != nil {
+		return query, ErrWrongListQuery
+	}
+	var c cursor
+	if err = json.Unmarshal(b, &c); err != nil || c.Short == "" || c.Sort != query.Sort || c.Desc != query.Desc {
+		return query, ErrWrongListQuery
+	}
+	query.After = domain.ListCursor{Short: c.Short, CreatedAt: c.CreatedAt, Clicks: c.Clicks}
+	return query, nil
+}
+
+// NextCursor возвращает курсор следующей страницы, если страница заполнена целиком, иначе пустую строку
+func NextCursor(query domain.ListQuery, page []domain.LinkInfo) string {
+	if len(page) == 0 || len(page) < query.Limit {
+		return ""
+	}
+	last := page[len(page)-1].Cursor()
+	b, _ := json.Marshal(cursor{
+		Sort:      query.Sort,
+		Desc:      query.Desc,
+		Short:     last.Short,
+		CreatedAt: last.CreatedAt,
+		Clicks:    last.Clicks,
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,66 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+func TestNewListQuery(t *testing.T) {
+	query, err := NewListQuery(0, "", "", "", "", "", "", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, domain.ListQuery{Limit: DefaultListLimit, Sort: domain.SortShort, Status: domain.StatusActive}, query)
+
+	query, err = NewListQuery(0, "", "", "", "", "", "", []string{"Work", "work", "docs"}, " Проекты ")
+	require.NoError(t, err)
+	require.Equal(t, []string{"work", "docs"}, query.Tags)
+	require.Equal(t, "Проекты", query.Folder)
+	_, err = NewListQuery(0, "", "", "", "", "", "", []string{"no spaces"}, "")
+	require.ErrorIs(t, err, ErrWrongListQuery)
+
+	tests := []struct {
+		name                string
+		limit               int
+		sort, order, status string
+	}{
+		{name: "negative limit", limit: -1},
+		{name: "limit too big", limit: MaxListLimit + 1},
+		{name: "unknown sort", sort: "long"},
+		{name: "unknown order", order: "up"},
+		{name: "unknown status", status: "expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewListQuery(tt.limit, "", tt.sort, tt.order, tt.status, "", "", nil, "")
+			require.ErrorIs(t, err, ErrWrongListQuery)
+			require.True(t, IsInputError(err))
+		})
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	query, err := NewListQuery(2, "", "created", "desc", "all", "", "", nil, "")
+	require.NoError(t, err)
+	created := time.Date(2023, 5, 1, 12, 0, 0, 123456000, time.UTC)
+	page := []domain.LinkInfo{
+		{URL: domain.URL{Short: "short002"}},
+		{URL: domain.URL{Short: "short001", CreatedAt: created}, Clicks: 3},
+	}
+	require.Empty(t, NextCursor(query, page[:1]), "неполная страница - последняя")
+	next := NextCursor(query, page)
+	require.NotEmpty(t, next)
+
+	query, err = NewListQuery(2, next, "created", "desc", "all", "", "", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, "short001", query.After.Short)
+	require.True(t, created.Equal(query.After.CreatedAt))
+
+	// курсор другой сортировки и мусор отклоняются
+	_, err = NewListQuery(2, next, "clicks", "desc", "all", "", "", nil, "")
+	require.ErrorIs(t, err, ErrWrongListQuery)
+	_, err = NewListQuery(2, "!!!", "", "", "", "", "", nil, "")
+	require.ErrorIs(t, err, ErrWrongListQuery)
+}
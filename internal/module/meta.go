@@ -0,0 +1,77 @@
+package module
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrWrongMeta заголовок или теги ссылки не проходят проверку
+	ErrWrongMeta = errors.New("module: title must be up to 200 characters, tags up to 20 of 1-32 letters, digits, '-' or '_'")
+	// ErrWrongFolder имя папки не проходит проверку
+	ErrWrongFolder = errors.New("module: folder must be 1-64 printable characters")
+)
+
+const (
+	maxTitleLength  = 200
+	maxTags         = 20
+	maxFolderLength = 64
+)
+
+var tagRegexp = regexp.MustCompile(`^[\p{L}\p{N}_-]{1,32}$`)
+
+// NormalizeMeta проверяет заголовок и теги ссылки. Заголовок обрезается по краям, теги приводятся к нижнему регистру,
+// повторы убираются с сохранением порядка. Ошибка - ErrWrongMeta
+func NormalizeMeta(title string, tags []string) (string, []string, error) {
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		return "", nil, ErrWrongMeta
+	}
+	if len(tags) == 0 {
+		return title, nil, nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagRegexp.MatchString(tag) {
+			return "", nil, ErrWrongMeta
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return "", nil, ErrWrongMeta
+	}
+	return title, normalized, nil
+}
+
+// NormalizeTag проверяет имя метки и приводит его к нижнему регистру. Ошибка - ErrWrongMeta
+func NormalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !tagRegexp.MatchString(name) {
+		return "", ErrWrongMeta
+	}
+	return name, nil
+}
+
+// NormalizeFolder проверяет имя папки, обрезая его по краям. Пустое имя допустимо и означает "вне папок".
+// Ошибка - ErrWrongFolder
+func NormalizeFolder(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxFolderLength {
+		return "", ErrWrongFolder
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return "", ErrWrongFolder
+		}
+	}
+	return name, nil
+}
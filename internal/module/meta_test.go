@@ -0,0 +1,57 @@
+package module
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMeta(t *testing.T) {
+	title, tags, err := NormalizeMeta("  Документация  ", []string{"Go", "docs", "go", "Справка"})
+	require.NoError(t, err)
+	require.Equal(t, "Документация", title)
+	require.Equal(t, []string{"go", "docs", "справка"}, tags)
+
+	_, tags, err = NormalizeMeta("", nil)
+	require.NoError(t, err)
+	require.Nil(t, tags)
+
+	tests := []struct {
+		name  string
+		title string
+		tags  []string
+	}{
+		{name: "long title", title: strings.Repeat("я", maxTitleLength+1)},
+		{name: "empty tag", tags: []string{""}},
+		{name: "tag with space", tags: []string{"two words"}},
+		{name: "too many tags", tags: strings.Split("a b c d e f g h i j k l m n o p q r s t u", " ")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NormalizeMeta(tt.title, tt.tags)
+			require.ErrorIs(t, err, ErrWrongMeta)
+			require.True(t, IsInputError(err))
+		})
+	}
+}
+
+func TestNormalizeTagAndFolder(t *testing.T) {
+	tag, err := NormalizeTag(" Go ")
+	require.NoError(t, err)
+	require.Equal(t, "go", tag)
+	_, err = NormalizeTag("two words")
+	require.ErrorIs(t, err, ErrWrongMeta)
+
+	folder, err := NormalizeFolder("  Рабочие ссылки ")
+	require.NoError(t, err)
+	require.Equal(t, "Рабочие ссылки", folder)
+	folder, err = NormalizeFolder("")
+	require.NoError(t, err)
+	require.Empty(t, folder)
+	_, err = NormalizeFolder(strings.Repeat("я", maxFolderLength+1))
+	require.ErrorIs(t, err, ErrWrongFolder)
+	_, err = NormalizeFolder("a\tb")
+	require.ErrorIs(t, err, ErrWrongFolder)
+	require.True(t, IsInputError(err))
+}
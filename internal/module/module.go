@@ -3,23 +3,128 @@ package module
 
 import (
 	"errors"
-	"math/rand"
 
 	"github.com/asaskevich/govalidator"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
 )
 
-var errURLshorting = errors.New("handler: wrong URL")
+// ErrWrongURL ошибка валидации сокращаемого URL
+var ErrWrongURL = errors.New("handler: wrong URL")
+
+// maxAttempts количество попыток сохранения при коллизии коротких идентификаторов
+const maxAttempts = 5
+
+var defaultShortener = NewShortener(RandomGenerator{})
 
 // ShortingURL Сокращение и валидация URL.
 func ShortingURL(longURL string) (string, error) {
-	b := make([]byte, 8)
+	if err := ValidateURL(longURL); err != nil {
+		return "", err
+	}
+	return defaultShortener.gen.Generate(longURL, 0)
+}
+
+// ValidateURL проверяет сокращаемый URL. Возвращает ErrWrongURL
+func ValidateURL(longURL string) error {
 	if !govalidator.IsURL(longURL) {
-		return "", errURLshorting
+		return ErrWrongURL
+	}
+	return nil
+}
+
+// Shortener сокращает URL генератором и повторяет сохранение при коллизии идентификаторов
+type Shortener struct {
+	gen Generator
+}
+
+// NewShortener возвращает Shortener с генератором gen
+func NewShortener(gen Generator) *Shortener {
+	return &Shortener{gen: gen}
+}
+
+// Short валидирует URL и сохраняет его через save под алиасом, а если алиас пустой - под сгенерированным идентификатором.
+// Если save возвращает domain.ErrShortExists, генерируется новый идентификатор, а для алиаса возвращается ErrAliasTaken.
+// Прочие ошибки save возвращаются как есть
+func (s *Shortener) Short(longURL, alias string, save func(short string) error) (string, error) {
+	if err := ValidateURL(longURL); err != nil {
+		return "", err
+	}
+	if alias != "" {
+		if err := ValidateAlias(alias); err != nil {
+			return "", err
+		}
+		err := save(alias)
+		if errors.Is(err, domain.ErrShortExists) {
+			return "", ErrAliasTaken
+		}
+		return alias, err
+	}
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var short string
+		short, err = s.gen.Generate(longURL, attempt)
+		if err != nil {
+			return "", err
+		}
+		err = save(short)
+		if !errors.Is(err, domain.ErrShortExists) {
+			return short, err
+		}
 	}
-	// на stackoverflow есть варианты быстрее, но этот более читабелен. первые два символа - визуальная привязка к домену
-	const symBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	for i := 0; i < len(b); i++ {
-		b[i] = symBytes[rand.Intn(len(symBytes))]
+	return "", err
+}
+
+// ShortBatch сокращает пакет URL. aliases может быть nil, пустой алиас - сгенерировать идентификатор.
+// save сохраняет пакет целиком; при коллизии сгенерированного идентификатора пакет генерируется и сохраняется заново,
+// при коллизии алиаса возвращается ErrAliasTaken
+func (s *Shortener) ShortBatch(longURLs, aliases []string, save func(shorts []string) error) ([]string, error) {
+	isAlias := make(map[string]struct{})
+	for i, longURL := range longURLs {
+		if err := ValidateURL(longURL); err != nil {
+			return nil, err
+		}
+		if i < len(aliases) && aliases[i] != "" {
+			if err := ValidateAlias(aliases[i]); err != nil {
+				return nil, err
+			}
+			if _, ok := isAlias[aliases[i]]; ok {
+				return nil, ErrAliasTaken
+			}
+			isAlias[aliases[i]] = struct{}{}
+		}
+	}
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		shorts := make([]string, len(longURLs))
+		for i, longURL := range longURLs {
+			if i < len(aliases) && aliases[i] != "" {
+				shorts[i] = aliases[i]
+				continue
+			}
+			if shorts[i], err = s.gen.Generate(longURL, attempt); err != nil {
+				return nil, err
+			}
+		}
+		err = save(shorts)
+		var se *domain.ShortExistsError
+		if errors.As(err, &se) {
+			if _, ok := isAlias[se.Short]; ok {
+				return nil, ErrAliasTaken
+			}
+		}
+		if !errors.Is(err, domain.ErrShortExists) {
+			return shorts, err
+		}
 	}
-	return string(b), nil
+	return nil, err
+}
+
+// IsInputError проверяет, что ошибка вызвана некорректными входными данными, а не хранилищем
+func IsInputError(err error) bool {
+	return errors.Is(err, ErrWrongURL) || errors.Is(err, ErrWrongAlias) || errors.Is(err, ErrReservedAlias) ||
+		errors.Is(err, ErrWrongExpiry) || errors.Is(err, ErrWrongListQuery) ||
+		errors.Is(err, ErrWrongMeta) || errors.Is(err, ErrWrongFolder) || errors.Is(err, ErrWrongQR) ||
+		errors.Is(err, ErrWrongPassword) || errors.Is(err, ErrWrongMaxClicks) ||
+		errors.Is(err, ErrWrongSchedule) || errors.Is(err, ErrWrongRules) || errors.Is(err, ErrWrongVariants)
 }
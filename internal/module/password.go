@@ -0,0 +1,97 @@
+package module
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrWrongPassword пароль ссылки не проходит проверку
+var ErrWrongPassword = errors.New("module: password must be 4-72 bytes")
+
+const (
+	minPasswordLength = 4
+	maxPasswordLength = 72 // больше bcrypt не учитывает
+)
+
+// Ограничение подбора пароля: попыток на одну ссылку за окно, успешная попытка сбрасывает счетчик
+const (
+	PasswordAttempts = 5
+	PasswordWindow   = 15 * time.Minute
+)
+
+// HashPassword проверяет пароль ссылки и возвращает его bcrypt-хэш. Пустой пароль - пустой хэш, ссылка без пароля.
+// Ошибка - ErrWrongPassword
+func HashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", ErrWrongPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword сверяет пароль с bcrypt-хэшем
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// Throttle ограничивает число попыток по ключу за окно времени. Окно отсчитывается от первой попытки.
+// Попытка учитывается в Allow до проверки, поэтому параллельные попытки не проходят сверх лимита
+type Throttle struct {
+	mu       sync.Mutex
+	limit    int
+	window   time.Duration
+	attempts map[string]throttleEntry
+}
+
+type throttleEntry struct {
+	count int
+	start time.Time
+}
+
+// throttleSweepSize - размер, при котором из Throttle убираются истекшие окна
+const throttleSweepSize = 10000
+
+// NewThrottle возвращает Throttle, который допускает limit попыток за window
+func NewThrottle(limit int, window time.Duration) *Throttle {
+	return &Throttle{limit: limit, window: window, attempts: make(map[string]throttleEntry)}
+}
+
+// Allow учитывает попытку по ключу на момент now, если лимит не исчерпан. Если исчерпан - попытка не учитывается
+// и возвращается время до конца окна
+func (t *Throttle) Allow(key string, now time.Time) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.attempts[key]
+	if !ok || now.Sub(entry.start) >= t.window {
+		entry = throttleEntry{start: now}
+	}
+	if entry.count >= t.limit {
+		return t.window - now.Sub(entry.start), false
+	}
+	entry.count++
+	t.attempts[key] = entry
+	if len(t.attempts) >= throttleSweepSize {
+		for k, e := range t.attempts {
+			if now.Sub(e.start) >= t.window {
+				delete(t.attempts, k)
+			}
+		}
+	}
+	return 0, true
+}
+
+// Reset сбрасывает попытки по ключу после успешной попытки
+func (t *Throttle) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.attempts, key)
+}
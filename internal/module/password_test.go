@@ -0,0 +1,70 @@
+package module
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("")
+	require.NoError(t, err)
+	require.Empty(t, hash)
+
+	hash, err = HashPassword("secret")
+	require.NoError(t, err)
+	require.NotEqual(t, "secret", hash)
+	require.True(t, CheckPassword(hash, "secret"))
+	require.False(t, CheckPassword(hash, "Secret"))
+	require.False(t, CheckPassword("", ""))
+
+	for _, password := range []string{"abc", strings.Repeat("a", 73)} {
+		_, err = HashPassword(password)
+		require.ErrorIs(t, err, ErrWrongPassword)
+		require.True(t, IsInputError(err))
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	throttle := NewThrottle(2, time.Minute)
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	_, ok := throttle.Allow("abc", now)
+	require.True(t, ok)
+	_, ok = throttle.Allow("abc", now.Add(10*time.Second))
+	require.True(t, ok)
+	retry, ok := throttle.Allow("abc", now.Add(20*time.Second))
+	require.False(t, ok)
+	require.Equal(t, 40*time.Second, retry)
+	_, ok = throttle.Allow("other", now)
+	require.True(t, ok, "ограничение действует по ключу")
+
+	_, ok = throttle.Allow("abc", now.Add(time.Minute))
+	require.True(t, ok, "окно истекло")
+	_, ok = throttle.Allow("abc", now.Add(time.Minute))
+	require.True(t, ok)
+	throttle.Reset("abc")
+	_, ok = throttle.Allow("abc", now.Add(time.Minute))
+	require.True(t, ok, "успешная попытка сбрасывает счетчик")
+}
+
+func TestThrottle_Concurrent(t *testing.T) {
+	throttle := NewThrottle(PasswordAttempts, PasswordWindow)
+	now := time.Now()
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := throttle.Allow("abc", now); ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(PasswordAttempts), allowed.Load())
+}
@@ -0,0 +1,95 @@
+package module
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Spear5030/yapshrtnr/internal/qr"
+)
+
+// ErrWrongQR параметры QR-кода не проходят проверку
+var ErrWrongQR = errors.New("module: qr size must be 64-4096, format png or svg, ec l, m, q or h, margin 0-32")
+
+// Форматы QR-кода
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+const (
+	defaultQRSize   = 256
+	minQRSize       = 64
+	maxQRSize       = 4096
+	defaultQRMargin = 4
+	maxQRMargin     = 32
+)
+
+// QROptions параметры отрисовки QR-кода
+type QROptions struct {
+	Size   int
+	Format string
+	Level  qr.Level
+	Margin int
+}
+
+// NewQROptions проверяет параметры QR-кода. Нулевой размер, пустые формат и уровень коррекции и отрицательный
+// отступ заменяются значениями по умолчанию: 256 пикселей, PNG, уровень M и 4 модуля. Ошибка - ErrWrongQR
+func NewQROptions(size int, format, level string, margin int) (QROptions, error) {
+	opts := QROptions{Size: size, Format: strings.ToLower(format), Level: qr.M, Margin: margin}
+	if opts.Size == 0 {
+		opts.Size = defaultQRSize
+	}
+	if opts.Size < minQRSize || opts.Size > maxQRSize {
+		return QROptions{}, ErrWrongQR
+	}
+	switch opts.Format {
+	case "":
+		opts.Format = QRFormatPNG
+	case QRFormatPNG, QRFormatSVG:
+	default:
+		return QROptions{}, ErrWrongQR
+	}
+	if level != "" {
+		l, err := qr.ParseLevel(level)
+		if err != nil {
+			return QROptions{}, ErrWrongQR
+		}
+		opts.Level = l
+	}
+	if opts.Margin < 0 {
+		opts.Margin = defaultQRMargin
+	}
+	if opts.Margin > maxQRMargin {
+		return QROptions{}, ErrWrongQR
+	}
+	return opts, nil
+}
+
+// ContentType возвращает MIME-тип изображения
+func (o QROptions) ContentType() string {
+	if o.Format == QRFormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// ETag возвращает тег изображения для content. Изображение полностью определяется содержимым и параметрами
+func (o QROptions) ETag(content string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s|%s|%d", content, o.Size, o.Format, o.Level, o.Margin)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// RenderQR рисует QR-код с content
+func RenderQR(content string, opts QROptions) ([]byte, error) {
+	code, err := qr.Encode([]byte(content), opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Format == QRFormatSVG {
+		return code.SVG(opts.Size, opts.Margin), nil
+	}
+	return code.PNG(opts.Size, opts.Margin)
+}
@@ -0,0 +1,62 @@
+package module
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/qr"
+)
+
+func TestNewQROptions(t *testing.T) {
+	opts, err := NewQROptions(0, "", "", -1)
+	require.NoError(t, err)
+	require.Equal(t, QROptions{Size: 256, Format: QRFormatPNG, Level: qr.M, Margin: 4}, opts)
+	require.Equal(t, "image/png", opts.ContentType())
+
+	opts, err = NewQROptions(512, "SVG", "h", 0)
+	require.NoError(t, err)
+	require.Equal(t, QROptions{Size: 512, Format: QRFormatSVG, Level: qr.H, Margin: 0}, opts)
+	require.Equal(t, "image/svg+xml", opts.ContentType())
+
+	tests := []struct {
+		name   string
+		size   int
+		format string
+		level  string
+		margin int
+	}{
+		{name: "small", size: 10},
+		{name: "big", size: 5000},
+		{name: "format", format: "gif"},
+		{name: "level", level: "x"},
+		{name: "margin", margin: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewQROptions(tt.size, tt.format, tt.level, tt.margin)
+			require.ErrorIs(t, err, ErrWrongQR)
+			require.True(t, IsInputError(err))
+		})
+	}
+}
+
+func TestRenderQR(t *testing.T) {
+	opts, err := NewQROptions(0, "", "", -1)
+	require.NoError(t, err)
+	b, err := RenderQR("http://localhost:8080/abc", opts)
+	require.NoError(t, err)
+	_, err = png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+
+	svgOpts := opts
+	svgOpts.Format = QRFormatSVG
+	b, err = RenderQR("http://localhost:8080/abc", svgOpts)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(b), "<?xml"))
+	require.NotEqual(t, opts.ETag("http://localhost:8080/abc"), svgOpts.ETag("http://localhost:8080/abc"))
+	require.Equal(t, opts.ETag("http://localhost:8080/abc"), opts.ETag("http://localhost:8080/abc"))
+}
@@ -0,0 +1,173 @@
+package module
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// ErrWrongRules правила перенаправления не проходят проверку
+var ErrWrongRules = errors.New("module: up to 20 rules, each with url and at least one of platform (ios, android, " +
+	"windows, macos, linux), language or country")
+
+// maxRules - наибольшее число правил перенаправления у ссылки
+const maxRules = 20
+
+// Платформы посетителя для правил перенаправления
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWindows = "windows"
+	PlatformMacOS   = "macos"
+	PlatformLinux   = "linux"
+)
+
+var (
+	languageRegexp = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+	countryRegexp  = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// NormalizeRules проверяет правила перенаправления и приводит условия к единому виду: платформа и язык
+// в нижнем регистре, страна в верхнем. Порядок правил сохраняется. Ошибки - ErrWrongRules, ErrWrongURL
+func NormalizeRules(rules []domain.RedirectRule) ([]domain.RedirectRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	if len(rules) > maxRules {
+		return nil, ErrWrongRules
+	}
+	normalized := make([]domain.RedirectRule, 0, len(rules))
+	for _, rule := range rules {
+		rule.Platform = strings.ToLower(strings.TrimSpace(rule.Platform))
+		rule.Language = strings.ToLower(strings.TrimSpace(rule.Language))
+		rule.Country = strings.ToUpper(strings.TrimSpace(rule.Country))
+		if rule.Platform == "" && rule.Language == "" && rule.Country == "" {
+			return nil, ErrWrongRules
+		}
+		switch rule.Platform {
+		case "", PlatformIOS, PlatformAndroid, PlatformWindows, PlatformMacOS, PlatformLinux:
+		default:
+			return nil, ErrWrongRules
+		}
+		if rule.Language != "" && !languageRegexp.MatchString(rule.Language) ||
+			rule.Country != "" && !countryRegexp.MatchString(rule.Country) {
+			return nil, ErrWrongRules
+		}
+		if err := ValidateURL(rule.Long); err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, rule)
+	}
+	return normalized, nil
+}
+
+// Visitor признаки посетителя, по которым выбирается правило перенаправления или вариант A/B-разделения
+type Visitor struct {
+	Platform string
+	Language string // основной язык в нижнем регистре, например en-us
+	Country  string
+	ID       string // постоянный идентификатор посетителя для выбора варианта, пустой - без вариантов
+}
+
+// NewVisitor определяет платформу по User-Agent и основной язык по Accept-Language. Страна передается готовой
+func NewVisitor(userAgent, acceptLanguage, country string) Visitor {
+	return Visitor{
+		Platform: Platform(userAgent),
+		Language: PreferredLanguage(acceptLanguage),
+		Country:  strings.ToUpper(country),
+	}
+}
+
+// Platform определяет платформу посетителя по User-Agent. Неизвестная платформа - пустая строка
+func Platform(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPad") ||
+		strings.Contains(userAgent, "iPod"):
+		return PlatformIOS
+	case strings.Contains(userAgent, "Android"): // раньше Linux: в User-Agent Android есть и то, и другое
+		return PlatformAndroid
+	case strings.Contains(userAgent, "Windows"):
+		return PlatformWindows
+	case strings.Contains(userAgent, "Macintosh") || strings.Contains(userAgent, "Mac OS X"):
+		return PlatformMacOS
+	case strings.Contains(userAgent, "Linux") || strings.Contains(userAgent, "X11") ||
+		strings.Contains(userAgent, "CrOS"):
+		return PlatformLinux
+	}
+	return ""
+}
+
+// PreferredLanguage возвращает язык с наибольшим весом q из Accept-Language, при равном весе - первый.
+// Языки с q=0 и * не учитываются. Без подходящих языков - пустая строка
+func PreferredLanguage(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var langs []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			var err error
+			if q, err = strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			langs = append(langs, weighted{tag: tag, q: q})
+		}
+	}
+	if len(langs) == 0 {
+		return ""
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	return langs[0].tag
+}
+
+// Match проверяет, что правило подходит посетителю. Язык правила en подходит посетителю с en и en-us
+func Match(rule domain.RedirectRule, v Visitor) bool {
+	if rule.Platform != "" && rule.Platform != v.Platform {
+		return false
+	}
+	if rule.Country != "" && rule.Country != v.Country {
+		return false
+	}
+	if rule.Language != "" && rule.Language != v.Language && !strings.HasPrefix(v.Language, rule.Language+"-") {
+		return false
+	}
+	return true
+}
+
+// Destination возвращает адрес первого подходящего посетителю правила ссылки. Если не подошло ни одно,
+// а у ссылки есть варианты и известен ID посетителя, - адрес и имя выбранного варианта, иначе Long ссылки
+func Destination(url domain.URL, v Visitor) (string, string) {
+	for _, rule := range url.Rules {
+		if Match(rule, v) {
+			return rule.Long, ""
+		}
+	}
+	if len(url.Variants) > 0 && v.ID != "" {
+		variant := PickVariant(url.Short, url.Variants, v.ID)
+		return variant.Long, variant.Name
+	}
+	return url.Long, ""
+}
+
+// NeedsCountry проверяет, что для выбора правила ссылки нужна страна посетителя
+func NeedsCountry(rules []domain.RedirectRule) bool {
+	for _, rule := range rules {
+		if rule.Country != "" {
+			return true
+		}
+	}
+	return false
+}
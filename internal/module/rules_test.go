@@ -0,0 +1,101 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+const (
+	uaIPhone  = "Mozilla/5.0 (iPhone; CPU iPhone OS 16_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Mobile/15E148 Safari/604.1"
+	uaAndroid = "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Mobile Safari/537.36"
+	uaWindows = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36"
+	uaMac     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Safari/605.1.15"
+	uaLinux   = "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/114.0"
+)
+
+func TestPlatform(t *testing.T) {
+	for ua, want := range map[string]string{
+		uaIPhone:      PlatformIOS,
+		uaAndroid:     PlatformAndroid,
+		uaWindows:     PlatformWindows,
+		uaMac:         PlatformMacOS,
+		uaLinux:       PlatformLinux,
+		"curl/8.1.2":  "",
+		"":            "",
+		"Googlebot/2": "",
+	} {
+		require.Equal(t, want, Platform(ua), ua)
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	for header, want := range map[string]string{
+		"ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7": "ru-ru",
+		"en;q=0.5, de-CH":                     "de-ch",
+		"fr;q=0.8, it;q=0.8":                  "fr",
+		"*, es;q=0.1":                         "es",
+		"de;q=0":                              "",
+		"en;q=abc, pt-BR;q=0.2":               "pt-br",
+		"":                                    "",
+	} {
+		require.Equal(t, want, PreferredLanguage(header), header)
+	}
+}
+
+func TestNormalizeRules(t *testing.T) {
+	rules, err := NormalizeRules([]domain.RedirectRule{
+		{Platform: " iOS ", Long: "https://apps.apple.com/app/id1"},
+		{Language: "PT-br", Country: "br", Long: "https://example.com/br"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []domain.RedirectRule{
+		{Platform: "ios", Long: "https://apps.apple.com/app/id1"},
+		{Language: "pt-br", Country: "BR", Long: "https://example.com/br"},
+	}, rules)
+	rules, err = NormalizeRules(nil)
+	require.NoError(t, err)
+	require.Nil(t, rules)
+
+	for name, rule := range map[string]domain.RedirectRule{
+		"no condition":   {Long: "https://example.com"},
+		"wrong platform": {Platform: "symbian", Long: "https://example.com"},
+		"wrong language": {Language: "english", Long: "https://example.com"},
+		"wrong country":  {Country: "RUS", Long: "https://example.com"},
+		"wrong url":      {Platform: "ios", Long: "not url"},
+	} {
+		_, err = NormalizeRules([]domain.RedirectRule{rule})
+		require.Error(t, err, name)
+		require.True(t, IsInputError(err), name)
+	}
+	_, err = NormalizeRules(make([]domain.RedirectRule, maxRules+1))
+	require.ErrorIs(t, err, ErrWrongRules)
+}
+
+func TestDestination(t *testing.T) {
+	url := domain.URL{Long: "https://example.com", Rules: []domain.RedirectRule{
+		{Platform: PlatformIOS, Long: "https://apps.apple.com/app/id1"},
+		{Platform: PlatformAndroid, Long: "https://play.google.com/store/apps/details?id=app"},
+		{Language: "de", Country: "CH", Long: "https://example.com/ch-de"},
+		{Country: "CH", Long: "https://example.com/ch"},
+	}}
+	for _, tt := range []struct {
+		visitor Visitor
+		want    string
+	}{
+		{NewVisitor(uaIPhone, "de-CH", "CH"), "https://apps.apple.com/app/id1"},
+		{NewVisitor(uaAndroid, "", ""), "https://play.google.com/store/apps/details?id=app"},
+		{NewVisitor(uaWindows, "de-CH,de;q=0.9", "ch"), "https://example.com/ch-de"},
+		{NewVisitor(uaWindows, "deu", "CH"), "https://example.com/ch"},
+		{NewVisitor(uaWindows, "fr-CH", "CH"), "https://example.com/ch"},
+		{NewVisitor(uaMac, "de", "DE"), "https://example.com"},
+	} {
+		long, variant := Destination(url, tt.visitor)
+		require.Equal(t, tt.want, long, tt.visitor)
+		require.Empty(t, variant)
+	}
+	require.True(t, NeedsCountry(url.Rules))
+	require.False(t, NeedsCountry(url.Rules[:2]))
+}
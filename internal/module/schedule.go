@@ -0,0 +1,39 @@
+package module
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrWrongSchedule окно активности ссылки не проходит проверку
+var ErrWrongSchedule = errors.New("module: not_before must be before expiry, fallback_url requires not_before")
+
+// NotAfter сводит not_after к expires_at: это одна и та же граница окна активности, задать можно только одну.
+// Ошибка - ErrWrongExpiry
+func NotAfter(expiresAt, notAfter time.Time) (time.Time, error) {
+	if notAfter.IsZero() {
+		return expiresAt, nil
+	}
+	if !expiresAt.IsZero() {
+		return time.Time{}, ErrWrongExpiry
+	}
+	return notAfter, nil
+}
+
+// ValidateSchedule проверяет окно активности ссылки: notBefore раньше истечения, запасной URL задается
+// только вместе с notBefore. Нулевые notBefore и expiresAt - без ограничения. Ошибки - ErrWrongSchedule, ErrWrongURL
+func ValidateSchedule(notBefore, expiresAt time.Time, fallback string) error {
+	if notBefore.IsZero() {
+		if fallback != "" {
+			return ErrWrongSchedule
+		}
+		return nil
+	}
+	if !expiresAt.IsZero() && !notBefore.Before(expiresAt) {
+		return ErrWrongSchedule
+	}
+	if fallback != "" {
+		return ValidateURL(fallback)
+	}
+	return nil
+}
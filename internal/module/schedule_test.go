@@ -0,0 +1,49 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotAfter(t *testing.T) {
+	at := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	got, err := NotAfter(time.Time{}, at)
+	require.NoError(t, err)
+	require.Equal(t, at, got)
+	got, err = NotAfter(at, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, at, got)
+	_, err = NotAfter(at, at)
+	require.ErrorIs(t, err, ErrWrongExpiry)
+}
+
+func TestValidateSchedule(t *testing.T) {
+	launch := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		expiresAt time.Time
+		fallback  string
+		wantErr   error
+	}{
+		{name: "no window"},
+		{name: "not before", notBefore: launch},
+		{name: "window", notBefore: launch, expiresAt: launch.Add(time.Hour), fallback: "https://example.com/soon"},
+		{name: "empty window", notBefore: launch, expiresAt: launch, wantErr: ErrWrongSchedule},
+		{name: "fallback without window", fallback: "https://example.com/soon", wantErr: ErrWrongSchedule},
+		{name: "wrong fallback", notBefore: launch, fallback: "not url", wantErr: ErrWrongURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchedule(tt.notBefore, tt.expiresAt, tt.fallback)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.True(t, IsInputError(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package module
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// ErrWrongVariants варианты A/B-разделения не проходят проверку
+var ErrWrongVariants = errors.New("module: from 2 to 10 variants with unique names of up to 32 characters " +
+	"(a-z, 0-9, _ and -), url and weight from 1 to 10000")
+
+const (
+	// maxVariants - наибольшее число вариантов у ссылки
+	maxVariants = 10
+	// maxVariantWeight - наибольший вес варианта
+	maxVariantWeight = 10000
+)
+
+var variantNameRegexp = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// NormalizeVariants проверяет варианты A/B-разделения и приводит имена к нижнему регистру. Пустой список снимает
+// разделение. Порядок вариантов сохраняется: от него зависит распределение посетителей. Ошибки - ErrWrongVariants, ErrWrongURL
+func NormalizeVariants(variants []domain.Variant) ([]domain.Variant, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	if len(variants) < 2 || len(variants) > maxVariants {
+		return nil, ErrWrongVariants
+	}
+	normalized := make([]domain.Variant, 0, len(variants))
+	names := make(map[string]struct{}, len(variants))
+	for _, variant := range variants {
+		variant.Name = strings.ToLower(strings.TrimSpace(variant.Name))
+		if !variantNameRegexp.MatchString(variant.Name) || variant.Weight < 1 || variant.Weight > maxVariantWeight {
+			return nil, ErrWrongVariants
+		}
+		if _, ok := names[variant.Name]; ok {
+			return nil, ErrWrongVariants
+		}
+		names[variant.Name] = struct{}{}
+		if err := ValidateURL(variant.Long); err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, variant)
+	}
+	return normalized, nil
+}
+
+// PickVariant детерминированно выбирает вариант ссылки short для посетителя visitorID с вероятностью,
+// пропорциональной весу. Один посетитель всегда получает один и тот же вариант, пока варианты не меняются
+func PickVariant(short string, variants []domain.Variant, visitorID string) domain.Variant {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return domain.Variant{}
+	}
+	sum := sha256.Sum256([]byte(short + "|" + visitorID))
+	point := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+	for _, variant := range variants {
+		if point < variant.Weight {
+			return variant
+		}
+		point -= variant.Weight
+	}
+	return variants[len(variants)-1]
+}
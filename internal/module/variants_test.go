@@ -0,0 +1,78 @@
+package module
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+func TestNormalizeVariants(t *testing.T) {
+	variants, err := NormalizeVariants([]domain.Variant{
+		{Name: " Control ", Long: "https://example.com/a", Weight: 70},
+		{Name: "new_landing-2", Long: "https://example.com/b", Weight: 30},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []domain.Variant{
+		{Name: "control", Long: "https://example.com/a", Weight: 70},
+		{Name: "new_landing-2", Long: "https://example.com/b", Weight: 30},
+	}, variants)
+
+	variants, err = NormalizeVariants(nil)
+	require.NoError(t, err)
+	require.Nil(t, variants)
+
+	a := domain.Variant{Name: "a", Long: "https://example.com/a", Weight: 1}
+	for name, variants := range map[string][]domain.Variant{
+		"one variant":    {a},
+		"duplicate name": {a, {Name: "A", Long: "https://example.com/b", Weight: 1}},
+		"empty name":     {a, {Long: "https://example.com/b", Weight: 1}},
+		"wrong name":     {a, {Name: "b c", Long: "https://example.com/b", Weight: 1}},
+		"long name":      {a, {Name: strings.Repeat("b", 33), Long: "https://example.com/b", Weight: 1}},
+		"zero weight":    {a, {Name: "b", Long: "https://example.com/b"}},
+		"big weight":     {a, {Name: "b", Long: "https://example.com/b", Weight: maxVariantWeight + 1}},
+		"wrong url":      {a, {Name: "b", Long: "example", Weight: 1}},
+	} {
+		_, err := NormalizeVariants(variants)
+		require.Error(t, err, name)
+		require.True(t, IsInputError(err), name)
+	}
+	many := make([]domain.Variant, maxVariants+1)
+	for i := range many {
+		many[i] = domain.Variant{Name: strconv.Itoa(i), Long: "https://example.com", Weight: 1}
+	}
+	_, err = NormalizeVariants(many)
+	require.ErrorIs(t, err, ErrWrongVariants)
+}
+
+func TestPickVariant(t *testing.T) {
+	variants := []domain.Variant{
+		{Name: "a", Long: "https://example.com/a", Weight: 3},
+		{Name: "b", Long: "https://example.com/b", Weight: 1},
+	}
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		visitor := strconv.Itoa(i)
+		variant := PickVariant("short", variants, visitor)
+		require.Equal(t, variant, PickVariant("short", variants, visitor), "visitor keeps the variant")
+		counts[variant.Name]++
+	}
+	require.InDelta(t, 3000, counts["a"], 200)
+	require.InDelta(t, 1000, counts["b"], 200)
+
+	url := domain.URL{Short: "short", Long: "https://example.com", Variants: variants,
+		Rules: []domain.RedirectRule{{Platform: PlatformIOS, Long: "https://apps.apple.com/app/id1"}}}
+	long, variant := Destination(url, Visitor{Platform: PlatformIOS, ID: "1"})
+	require.Equal(t, "https://apps.apple.com/app/id1", long)
+	require.Empty(t, variant)
+	long, variant = Destination(url, Visitor{ID: "1"})
+	picked := PickVariant("short", variants, "1")
+	require.Equal(t, picked.Long, long)
+	require.Equal(t, picked.Name, variant)
+	long, variant = Destination(url, Visitor{})
+	require.Equal(t, "https://example.com", long)
+	require.Empty(t, variant)
+}
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -26,8 +27,26 @@ type URL struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Short string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
-	Long  string `protobuf:"bytes,2,opt,name=long,proto3" json:"long,omitempty"`
+	Short     string                 `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Long      string                 `protobuf:"bytes,2,opt,name=long,proto3" json:"long,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// deleted и clicks заполняются только в GetURLsByUser
+	Deleted     bool                   `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Clicks      int64                  `protobuf:"varint,5,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	Title       string                 `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Tags        []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeletedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Folder      string                 `protobuf:"bytes,10,opt,name=folder,proto3" json:"folder,omitempty"`
+	Preview     bool                   `protobuf:"varint,11,opt,name=preview,proto3" json:"preview,omitempty"`
+	Protected   bool                   `protobuf:"varint,12,opt,name=protected,proto3" json:"protected,omitempty"`                     // переход требует пароль
+	MaxClicks   int32                  `protobuf:"varint,13,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`    // 0 - без ограничения
+	ClicksUsed  int32                  `protobuf:"varint,14,opt,name=clicks_used,json=clicksUsed,proto3" json:"clicks_used,omitempty"` // переходов учтено в пределах max_clicks
+	NotBefore   *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	FallbackUrl string                 `protobuf:"bytes,16,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	Rules       []*RedirectRule        `protobuf:"bytes,17,rep,name=rules,proto3" json:"rules,omitempty"`
+	Variants    []*Variant             `protobuf:"bytes,18,rep,name=variants,proto3" json:"variants,omitempty"`
+	ExpiresAt   *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // конец срока действия, в том числе заданный как not_after
 }
 
 func (x *URL) Reset() {
@@ -76,126 +95,154 @@ func (x *URL) GetLong() string {
 	return ""
 }
 
-type Short struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *URL) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
 
-	Short string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+func (x *URL) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
 }
 
-func (x *Short) Reset() {
-	*x = Short{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *URL) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
 	}
+	return 0
 }
 
-func (x *Short) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *URL) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
 }
 
-func (*Short) ProtoMessage() {}
+func (x *URL) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
 
-func (x *Short) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *URL) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use Short.ProtoReflect.Descriptor instead.
-func (*Short) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{1}
+func (x *URL) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
 }
 
-func (x *Short) GetShort() string {
+func (x *URL) GetFolder() string {
 	if x != nil {
-		return x.Short
+		return x.Folder
 	}
 	return ""
 }
 
-type Long struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *URL) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
 
-	Long string `protobuf:"bytes,1,opt,name=long,proto3" json:"long,omitempty"`
+func (x *URL) GetProtected() bool {
+	if x != nil {
+		return x.Protected
+	}
+	return false
 }
 
-func (x *Long) Reset() {
-	*x = Long{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *URL) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
 	}
+	return 0
 }
 
-func (x *Long) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *URL) GetClicksUsed() int32 {
+	if x != nil {
+		return x.ClicksUsed
+	}
+	return 0
 }
 
-func (*Long) ProtoMessage() {}
+func (x *URL) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
 
-func (x *Long) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *URL) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use Long.ProtoReflect.Descriptor instead.
-func (*Long) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{2}
+func (x *URL) GetRules() []*RedirectRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
 }
 
-func (x *Long) GetLong() string {
+func (x *URL) GetVariants() []*Variant {
 	if x != nil {
-		return x.Long
+		return x.Variants
 	}
-	return ""
+	return nil
 }
 
-type StatsResponse struct {
+func (x *URL) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// RedirectRule правило перенаправления. Пустое условие подходит любому посетителю, непустые должны совпасть все
+type RedirectRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Urls  int32 `protobuf:"zigzag32,1,opt,name=urls,proto3" json:"urls,omitempty"`
-	Users int32 `protobuf:"zigzag32,2,opt,name=users,proto3" json:"users,omitempty"`
+	Platform string `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"` // ios, android, windows, macos или linux
+	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"` // основной язык посетителя: en подходит и для en-US
+	Country  string `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`   // код страны ISO 3166-1 alpha-2 по базе GeoIP
+	Long     string `protobuf:"bytes,4,opt,name=long,proto3" json:"long,omitempty"`
 }
 
-func (x *StatsResponse) Reset() {
-	*x = StatsResponse{}
+func (x *RedirectRule) Reset() {
+	*x = RedirectRule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[3]
+		mi := &file_proto_yapshrtnr_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StatsResponse) String() string {
+func (x *RedirectRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatsResponse) ProtoMessage() {}
+func (*RedirectRule) ProtoMessage() {}
 
-func (x *StatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[3]
+func (x *RedirectRule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -206,51 +253,67 @@ func (x *StatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
-func (*StatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use RedirectRule.ProtoReflect.Descriptor instead.
+func (*RedirectRule) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *StatsResponse) GetUrls() int32 {
+func (x *RedirectRule) GetPlatform() string {
 	if x != nil {
-		return x.Urls
+		return x.Platform
 	}
-	return 0
+	return ""
 }
 
-func (x *StatsResponse) GetUsers() int32 {
+func (x *RedirectRule) GetLanguage() string {
 	if x != nil {
-		return x.Users
+		return x.Language
 	}
-	return 0
+	return ""
 }
 
-type GetResponse struct {
+func (x *RedirectRule) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *RedirectRule) GetLong() string {
+	if x != nil {
+		return x.Long
+	}
+	return ""
+}
+
+// Variant вариант A/B-разделения: посетитель попадает в него с вероятностью weight к сумме весов
+type Variant struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Long    string `protobuf:"bytes,1,opt,name=long,proto3" json:"long,omitempty"`
-	Deleted bool   `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Long   string `protobuf:"bytes,2,opt,name=long,proto3" json:"long,omitempty"`
+	Weight int32  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
 }
 
-func (x *GetResponse) Reset() {
-	*x = GetResponse{}
+func (x *Variant) Reset() {
+	*x = Variant{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[4]
+		mi := &file_proto_yapshrtnr_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetResponse) String() string {
+func (x *Variant) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResponse) ProtoMessage() {}
+func (*Variant) ProtoMessage() {}
 
-func (x *GetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[4]
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -261,50 +324,57 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
-func (*GetResponse) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetResponse) GetLong() string {
+func (x *Variant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Variant) GetLong() string {
 	if x != nil {
 		return x.Long
 	}
 	return ""
 }
 
-func (x *GetResponse) GetDeleted() bool {
+func (x *Variant) GetWeight() int32 {
 	if x != nil {
-		return x.Deleted
+		return x.Weight
 	}
-	return false
+	return 0
 }
 
-type RequestBatchURLs struct {
+type Short struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Inputs []*RequestBatchURLsInput `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Short string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
 }
 
-func (x *RequestBatchURLs) Reset() {
-	*x = RequestBatchURLs{}
+func (x *Short) Reset() {
+	*x = Short{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[5]
+		mi := &file_proto_yapshrtnr_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RequestBatchURLs) String() string {
+func (x *Short) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestBatchURLs) ProtoMessage() {}
+func (*Short) ProtoMessage() {}
 
-func (x *RequestBatchURLs) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[5]
+func (x *Short) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,43 +385,54 @@ func (x *RequestBatchURLs) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestBatchURLs.ProtoReflect.Descriptor instead.
-func (*RequestBatchURLs) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Short.ProtoReflect.Descriptor instead.
+func (*Short) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *RequestBatchURLs) GetInputs() []*RequestBatchURLsInput {
+func (x *Short) GetShort() string {
 	if x != nil {
-		return x.Inputs
+		return x.Short
 	}
-	return nil
+	return ""
 }
 
-type ResponseBatchURLs struct {
+type Long struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Outputs []*ResponseBatchURLsOutput `protobuf:"bytes,1,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Long        string                 `protobuf:"bytes,1,opt,name=long,proto3" json:"long,omitempty"`
+	Alias       string                 `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"` // необязательный пользовательский короткий идентификатор
+	Ttl         int64                  `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`    // срок действия в секундах, взаимоисключающий с expires_at
+	ExpiresAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Title       string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`                                 // необязательное название, до 200 символов
+	Tags        []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`                                   // необязательные метки: буквы, цифры, _ и -
+	Preview     bool                   `protobuf:"varint,7,opt,name=preview,proto3" json:"preview,omitempty"`                            // показывать страницу предпросмотра вместо перехода
+	Password    string                 `protobuf:"bytes,8,opt,name=password,proto3" json:"password,omitempty"`                           // необязательный пароль для перехода, 4-72 байта
+	MaxClicks   int32                  `protobuf:"varint,9,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`       // необязательное число переходов, 1 - одноразовая ссылка
+	NotBefore   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`       // необязательное начало окна активности
+	NotAfter    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`          // конец окна активности, другое имя expires_at
+	FallbackUrl string                 `protobuf:"bytes,12,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"` // куда вести до начала окна, только вместе с not_before
 }
 
-func (x *ResponseBatchURLs) Reset() {
-	*x = ResponseBatchURLs{}
+func (x *Long) Reset() {
+	*x = Long{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[6]
+		mi := &file_proto_yapshrtnr_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseBatchURLs) String() string {
+func (x *Long) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseBatchURLs) ProtoMessage() {}
+func (*Long) ProtoMessage() {}
 
-func (x *ResponseBatchURLs) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[6]
+func (x *Long) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -362,43 +443,1464 @@ func (x *ResponseBatchURLs) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseBatchURLs.ProtoReflect.Descriptor instead.
-func (*ResponseBatchURLs) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use Long.ProtoReflect.Descriptor instead.
+func (*Long) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ResponseBatchURLs) GetOutputs() []*ResponseBatchURLsOutput {
+func (x *Long) GetLong() string {
 	if x != nil {
-		return x.Outputs
+		return x.Long
 	}
-	return nil
+	return ""
 }
 
-type RequestDeleteBatch struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Shorts []*Short `protobuf:"bytes,1,rep,name=shorts,proto3" json:"shorts,omitempty"`
+func (x *Long) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
 }
 
-func (x *RequestDeleteBatch) Reset() {
-	*x = RequestDeleteBatch{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Long) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
 	}
+	return 0
 }
 
-func (x *RequestDeleteBatch) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Long) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
 }
 
-func (*RequestDeleteBatch) ProtoMessage() {}
+func (x *Long) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
 
-func (x *RequestDeleteBatch) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[7]
+func (x *Long) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Long) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *Long) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *Long) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *Long) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *Long) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+func (x *Long) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+type StatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Urls  int32 `protobuf:"zigzag32,1,opt,name=urls,proto3" json:"urls,omitempty"`
+	Users int32 `protobuf:"zigzag32,2,opt,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsResponse) ProtoMessage() {}
+
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StatsResponse) GetUrls() int32 {
+	if x != nil {
+		return x.Urls
+	}
+	return 0
+}
+
+func (x *StatsResponse) GetUsers() int32 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
+type URLStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short        string                      `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Total        int64                       `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Unique       int64                       `protobuf:"varint,3,opt,name=unique,proto3" json:"unique,omitempty"` // уникальные посетители по IP и User-Agent
+	TopReferrers []*URLStatsResponseReferrer `protobuf:"bytes,4,rep,name=top_referrers,json=topReferrers,proto3" json:"top_referrers,omitempty"`
+	Daily        []*URLStatsResponsePoint    `protobuf:"bytes,5,rep,name=daily,proto3" json:"daily,omitempty"`
+	Variants     []*URLStatsResponseVariant  `protobuf:"bytes,6,rep,name=variants,proto3" json:"variants,omitempty"` // по вариантам A/B-разделения в порядке имен
+}
+
+func (x *URLStatsResponse) Reset() {
+	*x = URLStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *URLStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLStatsResponse) ProtoMessage() {}
+
+func (x *URLStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLStatsResponse.ProtoReflect.Descriptor instead.
+func (*URLStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *URLStatsResponse) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *URLStatsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *URLStatsResponse) GetUnique() int64 {
+	if x != nil {
+		return x.Unique
+	}
+	return 0
+}
+
+func (x *URLStatsResponse) GetTopReferrers() []*URLStatsResponseReferrer {
+	if x != nil {
+		return x.TopReferrers
+	}
+	return nil
+}
+
+func (x *URLStatsResponse) GetDaily() []*URLStatsResponsePoint {
+	if x != nil {
+		return x.Daily
+	}
+	return nil
+}
+
+func (x *URLStatsResponse) GetVariants() []*URLStatsResponseVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+type UpdateURLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Long  string `protobuf:"bytes,2,opt,name=long,proto3" json:"long,omitempty"`
+}
+
+func (x *UpdateURLRequest) Reset() {
+	*x = UpdateURLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateURLRequest) ProtoMessage() {}
+
+func (x *UpdateURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateURLRequest.ProtoReflect.Descriptor instead.
+func (*UpdateURLRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateURLRequest) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *UpdateURLRequest) GetLong() string {
+	if x != nil {
+		return x.Long
+	}
+	return ""
+}
+
+type Revision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OldLong   string                 `protobuf:"bytes,1,opt,name=old_long,json=oldLong,proto3" json:"old_long,omitempty"`
+	NewLong   string                 `protobuf:"bytes,2,opt,name=new_long,json=newLong,proto3" json:"new_long,omitempty"`
+	ChangedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+}
+
+func (x *Revision) Reset() {
+	*x = Revision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Revision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Revision) ProtoMessage() {}
+
+func (x *Revision) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Revision.ProtoReflect.Descriptor instead.
+func (*Revision) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Revision) GetOldLong() string {
+	if x != nil {
+		return x.OldLong
+	}
+	return ""
+}
+
+func (x *Revision) GetNewLong() string {
+	if x != nil {
+		return x.NewLong
+	}
+	return ""
+}
+
+func (x *Revision) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type ResponseURLRevisions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Revisions []*Revision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+}
+
+func (x *ResponseURLRevisions) Reset() {
+	*x = ResponseURLRevisions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseURLRevisions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseURLRevisions) ProtoMessage() {}
+
+func (x *ResponseURLRevisions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseURLRevisions.ProtoReflect.Descriptor instead.
+func (*ResponseURLRevisions) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ResponseURLRevisions) GetRevisions() []*Revision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type GetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Long    string `protobuf:"bytes,1,opt,name=long,proto3" json:"long,omitempty"`
+	Deleted bool   `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Preview bool   `protobuf:"varint,3,opt,name=preview,proto3" json:"preview,omitempty"` // клиенту стоит показать адрес назначения перед переходом
+	Title   string `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	// заполняется, пока окно активности не открылось: long тогда - запасной URL
+	NotBefore *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	Variant   string                 `protobuf:"bytes,6,opt,name=variant,proto3" json:"variant,omitempty"` // вариант A/B-разделения, в который попал посетитель
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetResponse) GetLong() string {
+	if x != nil {
+		return x.Long
+	}
+	return ""
+}
+
+func (x *GetResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *GetResponse) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *GetResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *GetResponse) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *GetResponse) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+type RequestBatchURLs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Inputs []*RequestBatchURLsInput `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
+}
+
+func (x *RequestBatchURLs) Reset() {
+	*x = RequestBatchURLs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestBatchURLs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestBatchURLs) ProtoMessage() {}
+
+func (x *RequestBatchURLs) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestBatchURLs.ProtoReflect.Descriptor instead.
+func (*RequestBatchURLs) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RequestBatchURLs) GetInputs() []*RequestBatchURLsInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+type ResponseBatchURLs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Outputs []*ResponseBatchURLsOutput `protobuf:"bytes,1,rep,name=outputs,proto3" json:"outputs,omitempty"`
+}
+
+func (x *ResponseBatchURLs) Reset() {
+	*x = ResponseBatchURLs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseBatchURLs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseBatchURLs) ProtoMessage() {}
+
+func (x *ResponseBatchURLs) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseBatchURLs.ProtoReflect.Descriptor instead.
+func (*ResponseBatchURLs) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ResponseBatchURLs) GetOutputs() []*ResponseBatchURLsOutput {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+type RequestDeleteBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shorts []*Short `protobuf:"bytes,1,rep,name=shorts,proto3" json:"shorts,omitempty"`
+}
+
+func (x *RequestDeleteBatch) Reset() {
+	*x = RequestDeleteBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestDeleteBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestDeleteBatch) ProtoMessage() {}
+
+func (x *RequestDeleteBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestDeleteBatch.ProtoReflect.Descriptor instead.
+func (*RequestDeleteBatch) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RequestDeleteBatch) GetShorts() []*Short {
+	if x != nil {
+		return x.Shorts
+	}
+	return nil
+}
+
+type JobID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *JobID) Reset() {
+	*x = JobID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobID) ProtoMessage() {}
+
+func (x *JobID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobID.ProtoReflect.Descriptor instead.
+func (*JobID) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *JobID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status    string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // queued, running, done или failed
+	Deleted   []string               `protobuf:"bytes,3,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	Skipped   []string               `protobuf:"bytes,4,rep,name=skipped,proto3" json:"skipped,omitempty"` // чужие и несуществующие ссылки
+	Error     string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *DeleteJob) Reset() {
+	*x = DeleteJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteJob) ProtoMessage() {}
+
+func (x *DeleteJob) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteJob.ProtoReflect.Descriptor instead.
+func (*DeleteJob) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DeleteJob) GetDeleted() []string {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+func (x *DeleteJob) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+func (x *DeleteJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeleteJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *DeleteJob) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type RequestGetURLsByUser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit  int32    `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`  // 0 - размер страницы по умолчанию
+	Cursor string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor предыдущей страницы
+	Sort   string   `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`     // short, created или clicks
+	Order  string   `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`   // asc или desc
+	Status string   `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // active (по умолчанию), deleted или all
+	Domain string   `protobuf:"bytes,6,opt,name=domain,proto3" json:"domain,omitempty"` // хост полного URL, включая поддомены
+	Search string   `protobuf:"bytes,7,opt,name=search,proto3" json:"search,omitempty"` // подстрока полного URL или короткого идентификатора
+	Tags   []string `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`     // ссылки со всеми метками
+	Folder string   `protobuf:"bytes,9,opt,name=folder,proto3" json:"folder,omitempty"`
+}
+
+func (x *RequestGetURLsByUser) Reset() {
+	*x = RequestGetURLsByUser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestGetURLsByUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestGetURLsByUser) ProtoMessage() {}
+
+func (x *RequestGetURLsByUser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestGetURLsByUser.ProtoReflect.Descriptor instead.
+func (*RequestGetURLsByUser) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RequestGetURLsByUser) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RequestGetURLsByUser) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *RequestGetURLsByUser) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *RequestGetURLsByUser) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *RequestGetURLsByUser) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RequestGetURLsByUser) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RequestGetURLsByUser) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *RequestGetURLsByUser) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *RequestGetURLsByUser) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+type ResponseGetURLsByUser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Urls       []*URL `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // пусто на последней странице
+}
+
+func (x *ResponseGetURLsByUser) Reset() {
+	*x = ResponseGetURLsByUser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseGetURLsByUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseGetURLsByUser) ProtoMessage() {}
+
+func (x *ResponseGetURLsByUser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseGetURLsByUser.ProtoReflect.Descriptor instead.
+func (*ResponseGetURLsByUser) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ResponseGetURLsByUser) GetUrls() []*URL {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *ResponseGetURLsByUser) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// Label метка или папка пользователя
+type Label struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Links     int64                  `protobuf:"varint,2,opt,name=links,proto3" json:"links,omitempty"` // неудаленные ссылки с меткой или в папке
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Label) Reset() {
+	*x = Label{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Label) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Label) ProtoMessage() {}
+
+func (x *Label) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Label.ProtoReflect.Descriptor instead.
+func (*Label) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Label) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Label) GetLinks() int64 {
+	if x != nil {
+		return x.Links
+	}
+	return 0
+}
+
+func (x *Label) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ResponseLabels struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Labels []*Label `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+}
+
+func (x *ResponseLabels) Reset() {
+	*x = ResponseLabels{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseLabels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseLabels) ProtoMessage() {}
+
+func (x *ResponseLabels) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseLabels.ProtoReflect.Descriptor instead.
+func (*ResponseLabels) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResponseLabels) GetLabels() []*Label {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type RenameTagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	NewName string `protobuf:"bytes,2,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+}
+
+func (x *RenameTagRequest) Reset() {
+	*x = RenameTagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameTagRequest) ProtoMessage() {}
+
+func (x *RenameTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameTagRequest.ProtoReflect.Descriptor instead.
+func (*RenameTagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RenameTagRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RenameTagRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+type SetURLTagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short string   `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Tags  []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"` // пустой список снимает все метки
+}
+
+func (x *SetURLTagsRequest) Reset() {
+	*x = SetURLTagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetURLTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLTagsRequest) ProtoMessage() {}
+
+func (x *SetURLTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLTagsRequest.ProtoReflect.Descriptor instead.
+func (*SetURLTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetURLTagsRequest) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *SetURLTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SetURLFolderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short  string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Folder string `protobuf:"bytes,2,opt,name=folder,proto3" json:"folder,omitempty"` // пусто - вне папок
+}
+
+func (x *SetURLFolderRequest) Reset() {
+	*x = SetURLFolderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetURLFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLFolderRequest) ProtoMessage() {}
+
+func (x *SetURLFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLFolderRequest.ProtoReflect.Descriptor instead.
+func (*SetURLFolderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetURLFolderRequest) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *SetURLFolderRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+type SetURLPreviewRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short   string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Preview bool   `protobuf:"varint,2,opt,name=preview,proto3" json:"preview,omitempty"`
+}
+
+func (x *SetURLPreviewRequest) Reset() {
+	*x = SetURLPreviewRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetURLPreviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLPreviewRequest) ProtoMessage() {}
+
+func (x *SetURLPreviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLPreviewRequest.ProtoReflect.Descriptor instead.
+func (*SetURLPreviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetURLPreviewRequest) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *SetURLPreviewRequest) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+type SetURLPasswordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short    string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"` // пусто - снять пароль
+}
+
+func (x *SetURLPasswordRequest) Reset() {
+	*x = SetURLPasswordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetURLPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLPasswordRequest) ProtoMessage() {}
+
+func (x *SetURLPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLPasswordRequest.ProtoReflect.Descriptor instead.
+func (*SetURLPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetURLPasswordRequest) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *SetURLPasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type SetURLRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short string          `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Rules []*RedirectRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"` // по порядку проверки, пусто - убрать правила
+}
+
+func (x *SetURLRulesRequest) Reset() {
+	*x = SetURLRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetURLRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLRulesRequest) ProtoMessage() {}
+
+func (x *SetURLRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLRulesRequest.ProtoReflect.Descriptor instead.
+func (*SetURLRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SetURLRulesRequest) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *SetURLRulesRequest) GetRules() []*RedirectRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type SetURLVariantsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short    string     `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Variants []*Variant `protobuf:"bytes,2,rep,name=variants,proto3" json:"variants,omitempty"` // от 2 до 10, пусто - убрать разделение
+}
+
+func (x *SetURLVariantsRequest) Reset() {
+	*x = SetURLVariantsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetURLVariantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetURLVariantsRequest) ProtoMessage() {}
+
+func (x *SetURLVariantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetURLVariantsRequest.ProtoReflect.Descriptor instead.
+func (*SetURLVariantsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetURLVariantsRequest) GetShort() string {
+	if x != nil {
+		return x.Short
+	}
+	return ""
+}
+
+func (x *SetURLVariantsRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+type QRRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Short  string `protobuf:"bytes,1,opt,name=short,proto3" json:"short,omitempty"`
+	Size   int32  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`           // сторона в пикселях, 0 - 256
+	Format string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`        // png или svg, пусто - png
+	Ec     string `protobuf:"bytes,4,opt,name=ec,proto3" json:"ec,omitempty"`                // уровень коррекции l, m, q или h, пусто - m
+	Margin *int32 `protobuf:"varint,5,opt,name=margin,proto3,oneof" json:"margin,omitempty"` // отступ в модулях, не задан - 4
+}
+
+func (x *QRRequest) Reset() {
+	*x = QRRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QRRequest) ProtoMessage() {}
+
+func (x *QRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -409,43 +1911,135 @@ func (x *RequestDeleteBatch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestDeleteBatch.ProtoReflect.Descriptor instead.
-func (*RequestDeleteBatch) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use QRRequest.ProtoReflect.Descriptor instead.
+func (*QRRequest) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *RequestDeleteBatch) GetShorts() []*Short {
+func (x *QRRequest) GetShort() string {
 	if x != nil {
-		return x.Shorts
+		return x.Short
+	}
+	return ""
+}
+
+func (x *QRRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *QRRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *QRRequest) GetEc() string {
+	if x != nil {
+		return x.Ec
+	}
+	return ""
+}
+
+func (x *QRRequest) GetMargin() int32 {
+	if x != nil && x.Margin != nil {
+		return *x.Margin
+	}
+	return 0
+}
+
+type QRImage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Image       []byte `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Etag        string `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+}
+
+func (x *QRImage) Reset() {
+	*x = QRImage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QRImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QRImage) ProtoMessage() {}
+
+func (x *QRImage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QRImage.ProtoReflect.Descriptor instead.
+func (*QRImage) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *QRImage) GetImage() []byte {
+	if x != nil {
+		return x.Image
 	}
 	return nil
 }
 
-type ResponseGetURLsByUser struct {
+func (x *QRImage) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *QRImage) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type URLStatsResponseReferrer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Urls []*URL `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	Referrer string `protobuf:"bytes,1,opt,name=referrer,proto3" json:"referrer,omitempty"`
+	Clicks   int64  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
 }
 
-func (x *ResponseGetURLsByUser) Reset() {
-	*x = ResponseGetURLsByUser{}
+func (x *URLStatsResponseReferrer) Reset() {
+	*x = URLStatsResponseReferrer{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[8]
+		mi := &file_proto_yapshrtnr_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseGetURLsByUser) String() string {
+func (x *URLStatsResponseReferrer) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseGetURLsByUser) ProtoMessage() {}
+func (*URLStatsResponseReferrer) ProtoMessage() {}
 
-func (x *ResponseGetURLsByUser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[8]
+func (x *URLStatsResponseReferrer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -456,31 +2050,167 @@ func (x *ResponseGetURLsByUser) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseGetURLsByUser.ProtoReflect.Descriptor instead.
-func (*ResponseGetURLsByUser) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use URLStatsResponseReferrer.ProtoReflect.Descriptor instead.
+func (*URLStatsResponseReferrer) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{6, 0}
 }
 
-func (x *ResponseGetURLsByUser) GetUrls() []*URL {
+func (x *URLStatsResponseReferrer) GetReferrer() string {
 	if x != nil {
-		return x.Urls
+		return x.Referrer
+	}
+	return ""
+}
+
+func (x *URLStatsResponseReferrer) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+type URLStatsResponsePoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // начало суток UTC
+	Clicks int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+}
+
+func (x *URLStatsResponsePoint) Reset() {
+	*x = URLStatsResponsePoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *URLStatsResponsePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLStatsResponsePoint) ProtoMessage() {}
+
+func (x *URLStatsResponsePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLStatsResponsePoint.ProtoReflect.Descriptor instead.
+func (*URLStatsResponsePoint) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{6, 1}
+}
+
+func (x *URLStatsResponsePoint) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
 	}
 	return nil
 }
 
+func (x *URLStatsResponsePoint) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+type URLStatsResponseVariant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Variant string `protobuf:"bytes,1,opt,name=variant,proto3" json:"variant,omitempty"`
+	Clicks  int64  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	Unique  int64  `protobuf:"varint,3,opt,name=unique,proto3" json:"unique,omitempty"`
+}
+
+func (x *URLStatsResponseVariant) Reset() {
+	*x = URLStatsResponseVariant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_yapshrtnr_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *URLStatsResponseVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLStatsResponseVariant) ProtoMessage() {}
+
+func (x *URLStatsResponseVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_yapshrtnr_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLStatsResponseVariant.ProtoReflect.Descriptor instead.
+func (*URLStatsResponseVariant) Descriptor() ([]byte, []int) {
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{6, 2}
+}
+
+func (x *URLStatsResponseVariant) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *URLStatsResponseVariant) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *URLStatsResponseVariant) GetUnique() int64 {
+	if x != nil {
+		return x.Unique
+	}
+	return 0
+}
+
 type RequestBatchURLsInput struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Long          string `protobuf:"bytes,1,opt,name=long,proto3" json:"long,omitempty"`
-	CorrelationId string `protobuf:"bytes,2,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Long          string                 `protobuf:"bytes,1,opt,name=long,proto3" json:"long,omitempty"`
+	CorrelationId string                 `protobuf:"bytes,2,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Alias         string                 `protobuf:"bytes,3,opt,name=alias,proto3" json:"alias,omitempty"`
+	Ttl           int64                  `protobuf:"varint,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Title         string                 `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Preview       bool                   `protobuf:"varint,8,opt,name=preview,proto3" json:"preview,omitempty"`
+	Password      string                 `protobuf:"bytes,9,opt,name=password,proto3" json:"password,omitempty"`
+	MaxClicks     int32                  `protobuf:"varint,10,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	NotBefore     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	FallbackUrl   string                 `protobuf:"bytes,13,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
 }
 
 func (x *RequestBatchURLsInput) Reset() {
 	*x = RequestBatchURLsInput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[9]
+		mi := &file_proto_yapshrtnr_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -493,7 +2223,7 @@ func (x *RequestBatchURLsInput) String() string {
 func (*RequestBatchURLsInput) ProtoMessage() {}
 
 func (x *RequestBatchURLsInput) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[9]
+	mi := &file_proto_yapshrtnr_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -506,19 +2236,96 @@ func (x *RequestBatchURLsInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestBatchURLsInput.ProtoReflect.Descriptor instead.
 func (*RequestBatchURLsInput) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{5, 0}
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{11, 0}
 }
 
 func (x *RequestBatchURLsInput) GetLong() string {
 	if x != nil {
-		return x.Long
+		return x.Long
+	}
+	return ""
+}
+
+func (x *RequestBatchURLsInput) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *RequestBatchURLsInput) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *RequestBatchURLsInput) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *RequestBatchURLsInput) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *RequestBatchURLsInput) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *RequestBatchURLsInput) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *RequestBatchURLsInput) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *RequestBatchURLsInput) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RequestBatchURLsInput) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *RequestBatchURLsInput) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *RequestBatchURLsInput) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
 	}
-	return ""
+	return nil
 }
 
-func (x *RequestBatchURLsInput) GetCorrelationId() string {
+func (x *RequestBatchURLsInput) GetFallbackUrl() string {
 	if x != nil {
-		return x.CorrelationId
+		return x.FallbackUrl
 	}
 	return ""
 }
@@ -535,7 +2342,7 @@ type ResponseBatchURLsOutput struct {
 func (x *ResponseBatchURLsOutput) Reset() {
 	*x = ResponseBatchURLsOutput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_yapshrtnr_proto_msgTypes[10]
+		mi := &file_proto_yapshrtnr_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -548,7 +2355,7 @@ func (x *ResponseBatchURLsOutput) String() string {
 func (*ResponseBatchURLsOutput) ProtoMessage() {}
 
 func (x *ResponseBatchURLsOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_yapshrtnr_proto_msgTypes[10]
+	mi := &file_proto_yapshrtnr_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -561,7 +2368,7 @@ func (x *ResponseBatchURLsOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResponseBatchURLsOutput.ProtoReflect.Descriptor instead.
 func (*ResponseBatchURLsOutput) Descriptor() ([]byte, []int) {
-	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{6, 0}
+	return file_proto_yapshrtnr_proto_rawDescGZIP(), []int{12, 0}
 }
 
 func (x *ResponseBatchURLsOutput) GetShort() string {
@@ -584,79 +2391,398 @@ var file_proto_yapshrtnr_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e,
 	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74,
 	0x6e, 0x72, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
-	0x2f, 0x0a, 0x03, 0x55, 0x52, 0x4c, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xc4, 0x05, 0x0a, 0x03, 0x55, 0x52, 0x4c, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f,
+	0x6e, 0x67, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x55, 0x73, 0x65, 0x64, 0x12,
+	0x39, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x12, 0x2d, 0x0a,
+	0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x79,
+	0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x08,
+	0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x0a,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0x74, 0x0a, 0x0c, 0x52, 0x65, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66,
+	0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66,
+	0x6f, 0x72, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e,
+	0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x22, 0x49, 0x0a,
+	0x07, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
 	0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67,
-	0x22, 0x1d, 0x0a, 0x05, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f,
-	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x22,
-	0x1a, 0x0a, 0x04, 0x4c, 0x6f, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x22, 0x39, 0x0a, 0x0d, 0x53,
-	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x11, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73,
-	0x12, 0x14, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x11, 0x52,
-	0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0x3b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x64, 0x22, 0x91, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42,
-	0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x12, 0x39, 0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75,
-	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68,
-	0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63,
-	0x68, 0x55, 0x52, 0x4c, 0x73, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x06, 0x69, 0x6e, 0x70,
-	0x75, 0x74, 0x73, 0x1a, 0x42, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04,
-	0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67,
-	0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x99, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x12, 0x3d, 0x0a,
-	0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23,
-	0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x2e, 0x6f, 0x75, 0x74,
-	0x70, 0x75, 0x74, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x1a, 0x45, 0x0a, 0x06,
-	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x25, 0x0a, 0x0e,
-	0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x49, 0x64, 0x22, 0x3e, 0x0a, 0x12, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x28, 0x0a, 0x06, 0x73, 0x68, 0x6f,
-	0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73,
-	0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x52, 0x06, 0x73, 0x68, 0x6f,
-	0x72, 0x74, 0x73, 0x22, 0x3b, 0x0a, 0x15, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x47,
+	0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x1d, 0x0a, 0x05, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x22, 0x93, 0x03, 0x0a, 0x04, 0x4c, 0x6f, 0x6e, 0x67,
+	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6c, 0x6f, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x74,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x12, 0x39, 0x0a, 0x0a,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x63,
+	0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x78,
+	0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72,
+	0x65, 0x12, 0x37, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x22, 0x39, 0x0a,
+	0x0d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x11, 0x52, 0x04, 0x75, 0x72,
+	0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x11, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0x81, 0x04, 0x0a, 0x10, 0x55, 0x52, 0x4c,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69,
+	0x71, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x71, 0x75,
+	0x65, 0x12, 0x49, 0x0a, 0x0d, 0x74, 0x6f, 0x70, 0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65,
+	0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68,
+	0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x52, 0x0c,
+	0x74, 0x6f, 0x70, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x73, 0x12, 0x37, 0x0a, 0x05,
+	0x64, 0x61, 0x69, 0x6c, 0x79, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x79, 0x61,
+	0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x05,
+	0x64, 0x61, 0x69, 0x6c, 0x79, 0x12, 0x3f, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72,
+	0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x08, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x1a, 0x3e, 0x0a, 0x08, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72,
+	0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x1a, 0x4f, 0x0a, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x2e, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x1a, 0x53, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6c,
+	0x69, 0x63, 0x6b, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x22, 0x3c, 0x0a, 0x10,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x22, 0x7b, 0x0a, 0x08, 0x52, 0x65,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x6c, 0x64, 0x5f, 0x6c, 0x6f,
+	0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x6c, 0x64, 0x4c, 0x6f, 0x6e,
+	0x67, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x4c, 0x6f, 0x6e, 0x67, 0x12, 0x39, 0x0a, 0x0a,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x64, 0x41, 0x74, 0x22, 0x49, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x31, 0x0a, 0x09, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52,
+	0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69,
+	0x74, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65,
+	0x12, 0x39, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x74, 0x22, 0x8b, 0x04, 0x0a, 0x10, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x12, 0x39, 0x0a, 0x06, 0x69, 0x6e,
+	0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x79, 0x61, 0x70,
+	0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x06, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x73, 0x1a, 0xbb, 0x03, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c,
+	0x6f, 0x6e, 0x67, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72,
+	0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x12, 0x10, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x74,
+	0x74, 0x6c, 0x12, 0x39, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69,
+	0x74, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x12, 0x39, 0x0a, 0x0a,
+	0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x6e, 0x6f,
+	0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61,
+	0x66, 0x74, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72,
+	0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x55, 0x72, 0x6c, 0x22, 0x99, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x12, 0x3d, 0x0a, 0x07, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x79, 0x61, 0x70,
+	0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52,
+	0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x1a, 0x45, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72,
+	0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22,
+	0x3e, 0x0a, 0x12, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x28, 0x0a, 0x06, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e,
+	0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x52, 0x06, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x73, 0x22,
+	0x17, 0x0a, 0x05, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xf3, 0x01, 0x0a, 0x09, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70,
+	0x70, 0x65, 0x64, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70,
+	0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xe2,
+	0x01, 0x0a, 0x14, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x47, 0x65, 0x74, 0x55, 0x52, 0x4c,
+	0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x22, 0x5c, 0x0a, 0x15, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x47,
 	0x65, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x04,
 	0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x79, 0x61, 0x70,
 	0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73,
-	0x32, 0xd0, 0x03, 0x0a, 0x09, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12, 0x38,
-	0x0a, 0x06, 0x50, 0x69, 0x6e, 0x67, 0x44, 0x42, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x32, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x55,
-	0x52, 0x4c, 0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53,
-	0x68, 0x6f, 0x72, 0x74, 0x1a, 0x16, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72,
-	0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07,
-	0x50, 0x6f, 0x73, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x0f, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72,
-	0x74, 0x6e, 0x72, 0x2e, 0x4c, 0x6f, 0x6e, 0x67, 0x1a, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68,
-	0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x44, 0x0a, 0x10, 0x47, 0x65,
-	0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x18, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74,
-	0x6e, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x4a, 0x0a, 0x0d, 0x50, 0x6f, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c,
-	0x73, 0x12, 0x1b, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x1a, 0x1c,
-	0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x12, 0x4a, 0x0a, 0x11,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x42, 0x79, 0x55, 0x73, 0x65,
-	0x72, 0x12, 0x1d, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x49, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x55,
-	0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x20, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x47, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55,
-	0x73, 0x65, 0x72, 0x42, 0x0e, 0x5a, 0x0c, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
-	0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f,
+	0x72, 0x22, 0x6c, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c,
+	0x69, 0x6e, 0x6b, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22,
+	0x3a, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x12, 0x28, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x22, 0x41, 0x0a, 0x10, 0x52,
+	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3d,
+	0x0a, 0x11, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x43, 0x0a,
+	0x13, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f,
+	0x6c, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x22, 0x46, 0x0a, 0x14, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x50, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x22, 0x49, 0x0a, 0x15, 0x53, 0x65,
+	0x74, 0x55, 0x52, 0x4c, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x59, 0x0a, 0x12, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x12, 0x2d, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x22, 0x5d, 0x0a, 0x15, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12,
+	0x2e, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x56, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x22,
+	0x85, 0x01, 0x0a, 0x09, 0x51, 0x52, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x65, 0x63, 0x12,
+	0x1b, 0x0a, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x00, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07,
+	0x5f, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x22, 0x56, 0x0a, 0x07, 0x51, 0x52, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x65,
+	0x74, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x65, 0x74, 0x61, 0x67, 0x32,
+	0x8f, 0x0c, 0x0a, 0x09, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12, 0x38, 0x0a,
+	0x06, 0x50, 0x69, 0x6e, 0x67, 0x44, 0x42, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x32, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x55, 0x52,
+	0x4c, 0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x1a, 0x16, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x50,
+	0x6f, 0x73, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x0f, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74,
+	0x6e, 0x72, 0x2e, 0x4c, 0x6f, 0x6e, 0x67, 0x1a, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72,
+	0x74, 0x6e, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x44, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x18, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e,
+	0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4a, 0x0a, 0x0d, 0x50, 0x6f, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73,
+	0x12, 0x1b, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x1a, 0x1c, 0x2e,
+	0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x52, 0x4c, 0x73, 0x12, 0x44, 0x0a, 0x11, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0x1d, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x1a,
+	0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x4a, 0x6f, 0x62, 0x49,
+	0x44, 0x12, 0x52, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73,
+	0x65, 0x72, 0x12, 0x1f, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x47, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55,
+	0x73, 0x65, 0x72, 0x1a, 0x20, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x47, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42,
+	0x79, 0x55, 0x73, 0x65, 0x72, 0x12, 0x3c, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72,
+	0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x1a, 0x1b, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74,
+	0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x52, 0x4c,
+	0x12, 0x1b, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x12, 0x44, 0x0a,
+	0x0f, 0x47, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x1a, 0x1f, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x2e, 0x0a, 0x0a, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x55, 0x52,
+	0x4c, 0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x1a, 0x0e, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e,
+	0x55, 0x52, 0x4c, 0x12, 0x36, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x4a, 0x6f, 0x62, 0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e,
+	0x4a, 0x6f, 0x62, 0x49, 0x44, 0x1a, 0x14, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e,
+	0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x3d, 0x0a, 0x08, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x61, 0x67, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x19, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x2f, 0x0a, 0x09, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72,
+	0x74, 0x6e, 0x72, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x1a, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73,
+	0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x3a, 0x0a, 0x09, 0x52,
+	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x67, 0x12, 0x1b, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68,
+	0x72, 0x74, 0x6e, 0x72, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e,
+	0x72, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x35, 0x0a, 0x09, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x54, 0x61, 0x67, 0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72,
+	0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40,
+	0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x19, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e,
+	0x72, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x12, 0x38, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x12, 0x10, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3a, 0x0a, 0x0a, 0x53, 0x65,
+	0x74, 0x55, 0x52, 0x4c, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1c, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68,
+	0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x54, 0x61, 0x67, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74,
+	0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x12, 0x3e, 0x0a, 0x0c, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c,
+	0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74,
+	0x6e, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74,
+	0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x12, 0x31, 0x0a, 0x05, 0x47, 0x65, 0x74, 0x51, 0x52, 0x12,
+	0x14, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x51, 0x52, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e,
+	0x72, 0x2e, 0x51, 0x52, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x40, 0x0a, 0x0d, 0x53, 0x65, 0x74,
+	0x55, 0x52, 0x4c, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x1f, 0x2e, 0x79, 0x61, 0x70,
+	0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x50, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x79, 0x61,
+	0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x12, 0x42, 0x0a, 0x0e, 0x53,
+	0x65, 0x74, 0x55, 0x52, 0x4c, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x20, 0x2e,
+	0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0e, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x12,
+	0x3c, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1d,
+	0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x52,
+	0x4c, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52, 0x4c, 0x12, 0x42, 0x0a,
+	0x0e, 0x53, 0x65, 0x74, 0x55, 0x52, 0x4c, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12,
+	0x20, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x55,
+	0x52, 0x4c, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0e, 0x2e, 0x79, 0x61, 0x70, 0x73, 0x68, 0x72, 0x74, 0x6e, 0x72, 0x2e, 0x55, 0x52,
+	0x4c, 0x42, 0x0e, 0x5a, 0x0c, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -671,45 +2797,131 @@ func file_proto_yapshrtnr_proto_rawDescGZIP() []byte {
 	return file_proto_yapshrtnr_proto_rawDescData
 }
 
-var file_proto_yapshrtnr_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_yapshrtnr_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_proto_yapshrtnr_proto_goTypes = []interface{}{
-	(*URL)(nil),                     // 0: yapshrtnr.URL
-	(*Short)(nil),                   // 1: yapshrtnr.Short
-	(*Long)(nil),                    // 2: yapshrtnr.Long
-	(*StatsResponse)(nil),           // 3: yapshrtnr.StatsResponse
-	(*GetResponse)(nil),             // 4: yapshrtnr.GetResponse
-	(*RequestBatchURLs)(nil),        // 5: yapshrtnr.RequestBatchURLs
-	(*ResponseBatchURLs)(nil),       // 6: yapshrtnr.ResponseBatchURLs
-	(*RequestDeleteBatch)(nil),      // 7: yapshrtnr.RequestDeleteBatch
-	(*ResponseGetURLsByUser)(nil),   // 8: yapshrtnr.ResponseGetURLsByUser
-	(*RequestBatchURLsInput)(nil),   // 9: yapshrtnr.RequestBatchURLs.input
-	(*ResponseBatchURLsOutput)(nil), // 10: yapshrtnr.ResponseBatchURLs.output
-	(*emptypb.Empty)(nil),           // 11: google.protobuf.Empty
+	(*URL)(nil),                      // 0: yapshrtnr.URL
+	(*RedirectRule)(nil),             // 1: yapshrtnr.RedirectRule
+	(*Variant)(nil),                  // 2: yapshrtnr.Variant
+	(*Short)(nil),                    // 3: yapshrtnr.Short
+	(*Long)(nil),                     // 4: yapshrtnr.Long
+	(*StatsResponse)(nil),            // 5: yapshrtnr.StatsResponse
+	(*URLStatsResponse)(nil),         // 6: yapshrtnr.URLStatsResponse
+	(*UpdateURLRequest)(nil),         // 7: yapshrtnr.UpdateURLRequest
+	(*Revision)(nil),                 // 8: yapshrtnr.Revision
+	(*ResponseURLRevisions)(nil),     // 9: yapshrtnr.ResponseURLRevisions
+	(*GetResponse)(nil),              // 10: yapshrtnr.GetResponse
+	(*RequestBatchURLs)(nil),         // 11: yapshrtnr.RequestBatchURLs
+	(*ResponseBatchURLs)(nil),        // 12: yapshrtnr.ResponseBatchURLs
+	(*RequestDeleteBatch)(nil),       // 13: yapshrtnr.RequestDeleteBatch
+	(*JobID)(nil),                    // 14: yapshrtnr.JobID
+	(*DeleteJob)(nil),                // 15: yapshrtnr.DeleteJob
+	(*RequestGetURLsByUser)(nil),     // 16: yapshrtnr.RequestGetURLsByUser
+	(*ResponseGetURLsByUser)(nil),    // 17: yapshrtnr.ResponseGetURLsByUser
+	(*Label)(nil),                    // 18: yapshrtnr.Label
+	(*ResponseLabels)(nil),           // 19: yapshrtnr.ResponseLabels
+	(*RenameTagRequest)(nil),         // 20: yapshrtnr.RenameTagRequest
+	(*SetURLTagsRequest)(nil),        // 21: yapshrtnr.SetURLTagsRequest
+	(*SetURLFolderRequest)(nil),      // 22: yapshrtnr.SetURLFolderRequest
+	(*SetURLPreviewRequest)(nil),     // 23: yapshrtnr.SetURLPreviewRequest
+	(*SetURLPasswordRequest)(nil),    // 24: yapshrtnr.SetURLPasswordRequest
+	(*SetURLRulesRequest)(nil),       // 25: yapshrtnr.SetURLRulesRequest
+	(*SetURLVariantsRequest)(nil),    // 26: yapshrtnr.SetURLVariantsRequest
+	(*QRRequest)(nil),                // 27: yapshrtnr.QRRequest
+	(*QRImage)(nil),                  // 28: yapshrtnr.QRImage
+	(*URLStatsResponseReferrer)(nil), // 29: yapshrtnr.URLStatsResponse.referrer
+	(*URLStatsResponsePoint)(nil),    // 30: yapshrtnr.URLStatsResponse.point
+	(*URLStatsResponseVariant)(nil),  // 31: yapshrtnr.URLStatsResponse.variant
+	(*RequestBatchURLsInput)(nil),    // 32: yapshrtnr.RequestBatchURLs.input
+	(*ResponseBatchURLsOutput)(nil),  // 33: yapshrtnr.ResponseBatchURLs.output
+	(*timestamppb.Timestamp)(nil),    // 34: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),            // 35: google.protobuf.Empty
 }
 var file_proto_yapshrtnr_proto_depIdxs = []int32{
-	9,  // 0: yapshrtnr.RequestBatchURLs.inputs:type_name -> yapshrtnr.RequestBatchURLs.input
-	10, // 1: yapshrtnr.ResponseBatchURLs.outputs:type_name -> yapshrtnr.ResponseBatchURLs.output
-	1,  // 2: yapshrtnr.RequestDeleteBatch.shorts:type_name -> yapshrtnr.Short
-	0,  // 3: yapshrtnr.ResponseGetURLsByUser.urls:type_name -> yapshrtnr.URL
-	11, // 4: yapshrtnr.Shortener.PingDB:input_type -> google.protobuf.Empty
-	1,  // 5: yapshrtnr.Shortener.GetURL:input_type -> yapshrtnr.Short
-	2,  // 6: yapshrtnr.Shortener.PostURL:input_type -> yapshrtnr.Long
-	11, // 7: yapshrtnr.Shortener.GetInternalStats:input_type -> google.protobuf.Empty
-	5,  // 8: yapshrtnr.Shortener.PostBatchURLs:input_type -> yapshrtnr.RequestBatchURLs
-	7,  // 9: yapshrtnr.Shortener.DeleteBatchByUser:input_type -> yapshrtnr.RequestDeleteBatch
-	11, // 10: yapshrtnr.Shortener.GetURLsByUser:input_type -> google.protobuf.Empty
-	11, // 11: yapshrtnr.Shortener.PingDB:output_type -> google.protobuf.Empty
-	4,  // 12: yapshrtnr.Shortener.GetURL:output_type -> yapshrtnr.GetResponse
-	1,  // 13: yapshrtnr.Shortener.PostURL:output_type -> yapshrtnr.Short
-	3,  // 14: yapshrtnr.Shortener.GetInternalStats:output_type -> yapshrtnr.StatsResponse
-	6,  // 15: yapshrtnr.Shortener.PostBatchURLs:output_type -> yapshrtnr.ResponseBatchURLs
-	11, // 16: yapshrtnr.Shortener.DeleteBatchByUser:output_type -> google.protobuf.Empty
-	8,  // 17: yapshrtnr.Shortener.GetURLsByUser:output_type -> yapshrtnr.ResponseGetURLsByUser
-	11, // [11:18] is the sub-list for method output_type
-	4,  // [4:11] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	34, // 0: yapshrtnr.URL.created_at:type_name -> google.protobuf.Timestamp
+	34, // 1: yapshrtnr.URL.updated_at:type_name -> google.protobuf.Timestamp
+	34, // 2: yapshrtnr.URL.deleted_at:type_name -> google.protobuf.Timestamp
+	34, // 3: yapshrtnr.URL.not_before:type_name -> google.protobuf.Timestamp
+	1,  // 4: yapshrtnr.URL.rules:type_name -> yapshrtnr.RedirectRule
+	2,  // 5: yapshrtnr.URL.variants:type_name -> yapshrtnr.Variant
+	34, // 6: yapshrtnr.URL.expires_at:type_name -> google.protobuf.Timestamp
+	34, // 7: yapshrtnr.Long.expires_at:type_name -> google.protobuf.Timestamp
+	34, // 8: yapshrtnr.Long.not_before:type_name -> google.protobuf.Timestamp
+	34, // 9: yapshrtnr.Long.not_after:type_name -> google.protobuf.Timestamp
+	29, // 10: yapshrtnr.URLStatsResponse.top_referrers:type_name -> yapshrtnr.URLStatsResponse.referrer
+	30, // 11: yapshrtnr.URLStatsResponse.daily:type_name -> yapshrtnr.URLStatsResponse.point
+	31, // 12: yapshrtnr.URLStatsResponse.variants:type_name -> yapshrtnr.URLStatsResponse.variant
+	34, // 13: yapshrtnr.Revision.changed_at:type_name -> google.protobuf.Timestamp
+	8,  // 14: yapshrtnr.ResponseURLRevisions.revisions:type_name -> yapshrtnr.Revision
+	34, // 15: yapshrtnr.GetResponse.not_before:type_name -> google.protobuf.Timestamp
+	32, // 16: yapshrtnr.RequestBatchURLs.inputs:type_name -> yapshrtnr.RequestBatchURLs.input
+	33, // 17: yapshrtnr.ResponseBatchURLs.outputs:type_name -> yapshrtnr.ResponseBatchURLs.output
+	3,  // 18: yapshrtnr.RequestDeleteBatch.shorts:type_name -> yapshrtnr.Short
+	34, // 19: yapshrtnr.DeleteJob.created_at:type_name -> google.protobuf.Timestamp
+	34, // 20: yapshrtnr.DeleteJob.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 21: yapshrtnr.ResponseGetURLsByUser.urls:type_name -> yapshrtnr.URL
+	34, // 22: yapshrtnr.Label.created_at:type_name -> google.protobuf.Timestamp
+	18, // 23: yapshrtnr.ResponseLabels.labels:type_name -> yapshrtnr.Label
+	1,  // 24: yapshrtnr.SetURLRulesRequest.rules:type_name -> yapshrtnr.RedirectRule
+	2,  // 25: yapshrtnr.SetURLVariantsRequest.variants:type_name -> yapshrtnr.Variant
+	34, // 26: yapshrtnr.URLStatsResponse.point.date:type_name -> google.protobuf.Timestamp
+	34, // 27: yapshrtnr.RequestBatchURLs.input.expires_at:type_name -> google.protobuf.Timestamp
+	34, // 28: yapshrtnr.RequestBatchURLs.input.not_before:type_name -> google.protobuf.Timestamp
+	34, // 29: yapshrtnr.RequestBatchURLs.input.not_after:type_name -> google.protobuf.Timestamp
+	35, // 30: yapshrtnr.Shortener.PingDB:input_type -> google.protobuf.Empty
+	3,  // 31: yapshrtnr.Shortener.GetURL:input_type -> yapshrtnr.Short
+	4,  // 32: yapshrtnr.Shortener.PostURL:input_type -> yapshrtnr.Long
+	35, // 33: yapshrtnr.Shortener.GetInternalStats:input_type -> google.protobuf.Empty
+	11, // 34: yapshrtnr.Shortener.PostBatchURLs:input_type -> yapshrtnr.RequestBatchURLs
+	13, // 35: yapshrtnr.Shortener.DeleteBatchByUser:input_type -> yapshrtnr.RequestDeleteBatch
+	16, // 36: yapshrtnr.Shortener.GetURLsByUser:input_type -> yapshrtnr.RequestGetURLsByUser
+	3,  // 37: yapshrtnr.Shortener.GetURLStats:input_type -> yapshrtnr.Short
+	7,  // 38: yapshrtnr.Shortener.UpdateURL:input_type -> yapshrtnr.UpdateURLRequest
+	3,  // 39: yapshrtnr.Shortener.GetURLRevisions:input_type -> yapshrtnr.Short
+	3,  // 40: yapshrtnr.Shortener.RestoreURL:input_type -> yapshrtnr.Short
+	14, // 41: yapshrtnr.Shortener.GetDeleteJob:input_type -> yapshrtnr.JobID
+	35, // 42: yapshrtnr.Shortener.ListTags:input_type -> google.protobuf.Empty
+	18, // 43: yapshrtnr.Shortener.CreateTag:input_type -> yapshrtnr.Label
+	20, // 44: yapshrtnr.Shortener.RenameTag:input_type -> yapshrtnr.RenameTagRequest
+	18, // 45: yapshrtnr.Shortener.DeleteTag:input_type -> yapshrtnr.Label
+	35, // 46: yapshrtnr.Shortener.ListFolders:input_type -> google.protobuf.Empty
+	18, // 47: yapshrtnr.Shortener.DeleteFolder:input_type -> yapshrtnr.Label
+	21, // 48: yapshrtnr.Shortener.SetURLTags:input_type -> yapshrtnr.SetURLTagsRequest
+	22, // 49: yapshrtnr.Shortener.SetURLFolder:input_type -> yapshrtnr.SetURLFolderRequest
+	27, // 50: yapshrtnr.Shortener.GetQR:input_type -> yapshrtnr.QRRequest
+	23, // 51: yapshrtnr.Shortener.SetURLPreview:input_type -> yapshrtnr.SetURLPreviewRequest
+	24, // 52: yapshrtnr.Shortener.SetURLPassword:input_type -> yapshrtnr.SetURLPasswordRequest
+	25, // 53: yapshrtnr.Shortener.SetURLRules:input_type -> yapshrtnr.SetURLRulesRequest
+	26, // 54: yapshrtnr.Shortener.SetURLVariants:input_type -> yapshrtnr.SetURLVariantsRequest
+	35, // 55: yapshrtnr.Shortener.PingDB:output_type -> google.protobuf.Empty
+	10, // 56: yapshrtnr.Shortener.GetURL:output_type -> yapshrtnr.GetResponse
+	3,  // 57: yapshrtnr.Shortener.PostURL:output_type -> yapshrtnr.Short
+	5,  // 58: yapshrtnr.Shortener.GetInternalStats:output_type -> yapshrtnr.StatsResponse
+	12, // 59: yapshrtnr.Shortener.PostBatchURLs:output_type -> yapshrtnr.ResponseBatchURLs
+	14, // 60: yapshrtnr.Shortener.DeleteBatchByUser:output_type -> yapshrtnr.JobID
+	17, // 61: yapshrtnr.Shortener.GetURLsByUser:output_type -> yapshrtnr.ResponseGetURLsByUser
+	6,  // 62: yapshrtnr.Shortener.GetURLStats:output_type -> yapshrtnr.URLStatsResponse
+	0,  // 63: yapshrtnr.Shortener.UpdateURL:output_type -> yapshrtnr.URL
+	9,  // 64: yapshrtnr.Shortener.GetURLRevisions:output_type -> yapshrtnr.ResponseURLRevisions
+	0,  // 65: yapshrtnr.Shortener.RestoreURL:output_type -> yapshrtnr.URL
+	15, // 66: yapshrtnr.Shortener.GetDeleteJob:output_type -> yapshrtnr.DeleteJob
+	19, // 67: yapshrtnr.Shortener.ListTags:output_type -> yapshrtnr.ResponseLabels
+	18, // 68: yapshrtnr.Shortener.CreateTag:output_type -> yapshrtnr.Label
+	18, // 69: yapshrtnr.Shortener.RenameTag:output_type -> yapshrtnr.Label
+	35, // 70: yapshrtnr.Shortener.DeleteTag:output_type -> google.protobuf.Empty
+	19, // 71: yapshrtnr.Shortener.ListFolders:output_type -> yapshrtnr.ResponseLabels
+	35, // 72: yapshrtnr.Shortener.DeleteFolder:output_type -> google.protobuf.Empty
+	0,  // 73: yapshrtnr.Shortener.SetURLTags:output_type -> yapshrtnr.URL
+	0,  // 74: yapshrtnr.Shortener.SetURLFolder:output_type -> yapshrtnr.URL
+	28, // 75: yapshrtnr.Shortener.GetQR:output_type -> yapshrtnr.QRImage
+	0,  // 76: yapshrtnr.Shortener.SetURLPreview:output_type -> yapshrtnr.URL
+	0,  // 77: yapshrtnr.Shortener.SetURLPassword:output_type -> yapshrtnr.URL
+	0,  // 78: yapshrtnr.Shortener.SetURLRules:output_type -> yapshrtnr.URL
+	0,  // 79: yapshrtnr.Shortener.SetURLVariants:output_type -> yapshrtnr.URL
+	55, // [55:80] is the sub-list for method output_type
+	30, // [30:55] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_proto_yapshrtnr_proto_init() }
@@ -731,7 +2943,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Short); i {
+			switch v := v.(*RedirectRule); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -743,7 +2955,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Long); i {
+			switch v := v.(*Variant); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -755,7 +2967,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatsResponse); i {
+			switch v := v.(*Short); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -767,7 +2979,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetResponse); i {
+			switch v := v.(*Long); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -779,7 +2991,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestBatchURLs); i {
+			switch v := v.(*StatsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -791,7 +3003,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResponseBatchURLs); i {
+			switch v := v.(*URLStatsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -803,7 +3015,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestDeleteBatch); i {
+			switch v := v.(*UpdateURLRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -815,7 +3027,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResponseGetURLsByUser); i {
+			switch v := v.(*Revision); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -827,7 +3039,7 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestBatchURLsInput); i {
+			switch v := v.(*ResponseURLRevisions); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -839,6 +3051,282 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 		file_proto_yapshrtnr_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestBatchURLs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseBatchURLs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestDeleteBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestGetURLsByUser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseGetURLsByUser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Label); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseLabels); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameTagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetURLTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetURLFolderRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetURLPreviewRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetURLPasswordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetURLRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetURLVariantsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QRRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QRImage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*URLStatsResponseReferrer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*URLStatsResponsePoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*URLStatsResponseVariant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestBatchURLsInput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_yapshrtnr_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseBatchURLsOutput); i {
 			case 0:
 				return &v.state
@@ -851,13 +3339,14 @@ func file_proto_yapshrtnr_proto_init() {
 			}
 		}
 	}
+	file_proto_yapshrtnr_proto_msgTypes[27].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_yapshrtnr_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -27,6 +27,24 @@ const (
 	Shortener_PostBatchURLs_FullMethodName     = "/yapshrtnr.Shortener/PostBatchURLs"
 	Shortener_DeleteBatchByUser_FullMethodName = "/yapshrtnr.Shortener/DeleteBatchByUser"
 	Shortener_GetURLsByUser_FullMethodName     = "/yapshrtnr.Shortener/GetURLsByUser"
+	Shortener_GetURLStats_FullMethodName       = "/yapshrtnr.Shortener/GetURLStats"
+	Shortener_UpdateURL_FullMethodName         = "/yapshrtnr.Shortener/UpdateURL"
+	Shortener_GetURLRevisions_FullMethodName   = "/yapshrtnr.Shortener/GetURLRevisions"
+	Shortener_RestoreURL_FullMethodName        = "/yapshrtnr.Shortener/RestoreURL"
+	Shortener_GetDeleteJob_FullMethodName      = "/yapshrtnr.Shortener/GetDeleteJob"
+	Shortener_ListTags_FullMethodName          = "/yapshrtnr.Shortener/ListTags"
+	Shortener_CreateTag_FullMethodName         = "/yapshrtnr.Shortener/CreateTag"
+	Shortener_RenameTag_FullMethodName         = "/yapshrtnr.Shortener/RenameTag"
+	Shortener_DeleteTag_FullMethodName         = "/yapshrtnr.Shortener/DeleteTag"
+	Shortener_ListFolders_FullMethodName       = "/yapshrtnr.Shortener/ListFolders"
+	Shortener_DeleteFolder_FullMethodName      = "/yapshrtnr.Shortener/DeleteFolder"
+	Shortener_SetURLTags_FullMethodName        = "/yapshrtnr.Shortener/SetURLTags"
+	Shortener_SetURLFolder_FullMethodName      = "/yapshrtnr.Shortener/SetURLFolder"
+	Shortener_GetQR_FullMethodName             = "/yapshrtnr.Shortener/GetQR"
+	Shortener_SetURLPreview_FullMethodName     = "/yapshrtnr.Shortener/SetURLPreview"
+	Shortener_SetURLPassword_FullMethodName    = "/yapshrtnr.Shortener/SetURLPassword"
+	Shortener_SetURLRules_FullMethodName       = "/yapshrtnr.Shortener/SetURLRules"
+	Shortener_SetURLVariants_FullMethodName    = "/yapshrtnr.Shortener/SetURLVariants"
 )
 
 // ShortenerClient is the client API for Shortener service.
@@ -38,8 +56,26 @@ type ShortenerClient interface {
 	PostURL(ctx context.Context, in *Long, opts ...grpc.CallOption) (*Short, error)
 	GetInternalStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatsResponse, error)
 	PostBatchURLs(ctx context.Context, in *RequestBatchURLs, opts ...grpc.CallOption) (*ResponseBatchURLs, error)
-	DeleteBatchByUser(ctx context.Context, in *RequestDeleteBatch, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	GetURLsByUser(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ResponseGetURLsByUser, error)
+	DeleteBatchByUser(ctx context.Context, in *RequestDeleteBatch, opts ...grpc.CallOption) (*JobID, error)
+	GetURLsByUser(ctx context.Context, in *RequestGetURLsByUser, opts ...grpc.CallOption) (*ResponseGetURLsByUser, error)
+	GetURLStats(ctx context.Context, in *Short, opts ...grpc.CallOption) (*URLStatsResponse, error)
+	UpdateURL(ctx context.Context, in *UpdateURLRequest, opts ...grpc.CallOption) (*URL, error)
+	GetURLRevisions(ctx context.Context, in *Short, opts ...grpc.CallOption) (*ResponseURLRevisions, error)
+	RestoreURL(ctx context.Context, in *Short, opts ...grpc.CallOption) (*URL, error)
+	GetDeleteJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*DeleteJob, error)
+	ListTags(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ResponseLabels, error)
+	CreateTag(ctx context.Context, in *Label, opts ...grpc.CallOption) (*Label, error)
+	RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*Label, error)
+	DeleteTag(ctx context.Context, in *Label, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListFolders(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ResponseLabels, error)
+	DeleteFolder(ctx context.Context, in *Label, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SetURLTags(ctx context.Context, in *SetURLTagsRequest, opts ...grpc.CallOption) (*URL, error)
+	SetURLFolder(ctx context.Context, in *SetURLFolderRequest, opts ...grpc.CallOption) (*URL, error)
+	GetQR(ctx context.Context, in *QRRequest, opts ...grpc.CallOption) (*QRImage, error)
+	SetURLPreview(ctx context.Context, in *SetURLPreviewRequest, opts ...grpc.CallOption) (*URL, error)
+	SetURLPassword(ctx context.Context, in *SetURLPasswordRequest, opts ...grpc.CallOption) (*URL, error)
+	SetURLRules(ctx context.Context, in *SetURLRulesRequest, opts ...grpc.CallOption) (*URL, error)
+	SetURLVariants(ctx context.Context, in *SetURLVariantsRequest, opts ...grpc.CallOption) (*URL, error)
 }
 
 type shortenerClient struct {
@@ -95,8 +131,8 @@ func (c *shortenerClient) PostBatchURLs(ctx context.Context, in *RequestBatchURL
 	return out, nil
 }
 
-func (c *shortenerClient) DeleteBatchByUser(ctx context.Context, in *RequestDeleteBatch, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
+func (c *shortenerClient) DeleteBatchByUser(ctx context.Context, in *RequestDeleteBatch, opts ...grpc.CallOption) (*JobID, error) {
+	out := new(JobID)
 	err := c.cc.Invoke(ctx, Shortener_DeleteBatchByUser_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -104,7 +140,7 @@ func (c *shortenerClient) DeleteBatchByUser(ctx context.Context, in *RequestDele
 	return out, nil
 }
 
-func (c *shortenerClient) GetURLsByUser(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ResponseGetURLsByUser, error) {
+func (c *shortenerClient) GetURLsByUser(ctx context.Context, in *RequestGetURLsByUser, opts ...grpc.CallOption) (*ResponseGetURLsByUser, error) {
 	out := new(ResponseGetURLsByUser)
 	err := c.cc.Invoke(ctx, Shortener_GetURLsByUser_FullMethodName, in, out, opts...)
 	if err != nil {
@@ -113,6 +149,168 @@ func (c *shortenerClient) GetURLsByUser(ctx context.Context, in *emptypb.Empty,
 	return out, nil
 }
 
+func (c *shortenerClient) GetURLStats(ctx context.Context, in *Short, opts ...grpc.CallOption) (*URLStatsResponse, error) {
+	out := new(URLStatsResponse)
+	err := c.cc.Invoke(ctx, Shortener_GetURLStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) UpdateURL(ctx context.Context, in *UpdateURLRequest, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_UpdateURL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) GetURLRevisions(ctx context.Context, in *Short, opts ...grpc.CallOption) (*ResponseURLRevisions, error) {
+	out := new(ResponseURLRevisions)
+	err := c.cc.Invoke(ctx, Shortener_GetURLRevisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) RestoreURL(ctx context.Context, in *Short, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_RestoreURL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) GetDeleteJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*DeleteJob, error) {
+	out := new(DeleteJob)
+	err := c.cc.Invoke(ctx, Shortener_GetDeleteJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) ListTags(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ResponseLabels, error) {
+	out := new(ResponseLabels)
+	err := c.cc.Invoke(ctx, Shortener_ListTags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) CreateTag(ctx context.Context, in *Label, opts ...grpc.CallOption) (*Label, error) {
+	out := new(Label)
+	err := c.cc.Invoke(ctx, Shortener_CreateTag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*Label, error) {
+	out := new(Label)
+	err := c.cc.Invoke(ctx, Shortener_RenameTag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) DeleteTag(ctx context.Context, in *Label, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Shortener_DeleteTag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) ListFolders(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ResponseLabels, error) {
+	out := new(ResponseLabels)
+	err := c.cc.Invoke(ctx, Shortener_ListFolders_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) DeleteFolder(ctx context.Context, in *Label, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Shortener_DeleteFolder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) SetURLTags(ctx context.Context, in *SetURLTagsRequest, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_SetURLTags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) SetURLFolder(ctx context.Context, in *SetURLFolderRequest, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_SetURLFolder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) GetQR(ctx context.Context, in *QRRequest, opts ...grpc.CallOption) (*QRImage, error) {
+	out := new(QRImage)
+	err := c.cc.Invoke(ctx, Shortener_GetQR_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) SetURLPreview(ctx context.Context, in *SetURLPreviewRequest, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_SetURLPreview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) SetURLPassword(ctx context.Context, in *SetURLPasswordRequest, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_SetURLPassword_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) SetURLRules(ctx context.Context, in *SetURLRulesRequest, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_SetURLRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) SetURLVariants(ctx context.Context, in *SetURLVariantsRequest, opts ...grpc.CallOption) (*URL, error) {
+	out := new(URL)
+	err := c.cc.Invoke(ctx, Shortener_SetURLVariants_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortenerServer is the server API for Shortener service.
 // All implementations must embed UnimplementedShortenerServer
 // for forward compatibility
@@ -122,8 +320,26 @@ type ShortenerServer interface {
 	PostURL(context.Context, *Long) (*Short, error)
 	GetInternalStats(context.Context, *emptypb.Empty) (*StatsResponse, error)
 	PostBatchURLs(context.Context, *RequestBatchURLs) (*ResponseBatchURLs, error)
-	DeleteBatchByUser(context.Context, *RequestDeleteBatch) (*emptypb.Empty, error)
-	GetURLsByUser(context.Context, *emptypb.Empty) (*ResponseGetURLsByUser, error)
+	DeleteBatchByUser(context.Context, *RequestDeleteBatch) (*JobID, error)
+	GetURLsByUser(context.Context, *RequestGetURLsByUser) (*ResponseGetURLsByUser, error)
+	GetURLStats(context.Context, *Short) (*URLStatsResponse, error)
+	UpdateURL(context.Context, *UpdateURLRequest) (*URL, error)
+	GetURLRevisions(context.Context, *Short) (*ResponseURLRevisions, error)
+	RestoreURL(context.Context, *Short) (*URL, error)
+	GetDeleteJob(context.Context, *JobID) (*DeleteJob, error)
+	ListTags(context.Context, *emptypb.Empty) (*ResponseLabels, error)
+	CreateTag(context.Context, *Label) (*Label, error)
+	RenameTag(context.Context, *RenameTagRequest) (*Label, error)
+	DeleteTag(context.Context, *Label) (*emptypb.Empty, error)
+	ListFolders(context.Context, *emptypb.Empty) (*ResponseLabels, error)
+	DeleteFolder(context.Context, *Label) (*emptypb.Empty, error)
+	SetURLTags(context.Context, *SetURLTagsRequest) (*URL, error)
+	SetURLFolder(context.Context, *SetURLFolderRequest) (*URL, error)
+	GetQR(context.Context, *QRRequest) (*QRImage, error)
+	SetURLPreview(context.Context, *SetURLPreviewRequest) (*URL, error)
+	SetURLPassword(context.Context, *SetURLPasswordRequest) (*URL, error)
+	SetURLRules(context.Context, *SetURLRulesRequest) (*URL, error)
+	SetURLVariants(context.Context, *SetURLVariantsRequest) (*URL, error)
 	mustEmbedUnimplementedShortenerServer()
 }
 
@@ -146,12 +362,66 @@ func (UnimplementedShortenerServer) GetInternalStats(context.Context, *emptypb.E
 func (UnimplementedShortenerServer) PostBatchURLs(context.Context, *RequestBatchURLs) (*ResponseBatchURLs, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PostBatchURLs not implemented")
 }
-func (UnimplementedShortenerServer) DeleteBatchByUser(context.Context, *RequestDeleteBatch) (*emptypb.Empty, error) {
+func (UnimplementedShortenerServer) DeleteBatchByUser(context.Context, *RequestDeleteBatch) (*JobID, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteBatchByUser not implemented")
 }
-func (UnimplementedShortenerServer) GetURLsByUser(context.Context, *emptypb.Empty) (*ResponseGetURLsByUser, error) {
+func (UnimplementedShortenerServer) GetURLsByUser(context.Context, *RequestGetURLsByUser) (*ResponseGetURLsByUser, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetURLsByUser not implemented")
 }
+func (UnimplementedShortenerServer) GetURLStats(context.Context, *Short) (*URLStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURLStats not implemented")
+}
+func (UnimplementedShortenerServer) UpdateURL(context.Context, *UpdateURLRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateURL not implemented")
+}
+func (UnimplementedShortenerServer) GetURLRevisions(context.Context, *Short) (*ResponseURLRevisions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURLRevisions not implemented")
+}
+func (UnimplementedShortenerServer) RestoreURL(context.Context, *Short) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreURL not implemented")
+}
+func (UnimplementedShortenerServer) GetDeleteJob(context.Context, *JobID) (*DeleteJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeleteJob not implemented")
+}
+func (UnimplementedShortenerServer) ListTags(context.Context, *emptypb.Empty) (*ResponseLabels, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedShortenerServer) CreateTag(context.Context, *Label) (*Label, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedShortenerServer) RenameTag(context.Context, *RenameTagRequest) (*Label, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameTag not implemented")
+}
+func (UnimplementedShortenerServer) DeleteTag(context.Context, *Label) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTag not implemented")
+}
+func (UnimplementedShortenerServer) ListFolders(context.Context, *emptypb.Empty) (*ResponseLabels, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFolders not implemented")
+}
+func (UnimplementedShortenerServer) DeleteFolder(context.Context, *Label) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+}
+func (UnimplementedShortenerServer) SetURLTags(context.Context, *SetURLTagsRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLTags not implemented")
+}
+func (UnimplementedShortenerServer) SetURLFolder(context.Context, *SetURLFolderRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLFolder not implemented")
+}
+func (UnimplementedShortenerServer) GetQR(context.Context, *QRRequest) (*QRImage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQR not implemented")
+}
+func (UnimplementedShortenerServer) SetURLPreview(context.Context, *SetURLPreviewRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLPreview not implemented")
+}
+func (UnimplementedShortenerServer) SetURLPassword(context.Context, *SetURLPasswordRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLPassword not implemented")
+}
+func (UnimplementedShortenerServer) SetURLRules(context.Context, *SetURLRulesRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLRules not implemented")
+}
+func (UnimplementedShortenerServer) SetURLVariants(context.Context, *SetURLVariantsRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLVariants not implemented")
+}
 func (UnimplementedShortenerServer) mustEmbedUnimplementedShortenerServer() {}
 
 // UnsafeShortenerServer may be embedded to opt out of forward compatibility for this service.
@@ -274,7 +544,7 @@ func _Shortener_DeleteBatchByUser_Handler(srv interface{}, ctx context.Context,
 }
 
 func _Shortener_GetURLsByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(RequestGetURLsByUser)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -286,7 +556,331 @@ func _Shortener_GetURLsByUser_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: Shortener_GetURLsByUser_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShortenerServer).GetURLsByUser(ctx, req.(*emptypb.Empty))
+		return srv.(ShortenerServer).GetURLsByUser(ctx, req.(*RequestGetURLsByUser))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_GetURLStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Short)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).GetURLStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_GetURLStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).GetURLStats(ctx, req.(*Short))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_UpdateURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).UpdateURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_UpdateURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).UpdateURL(ctx, req.(*UpdateURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_GetURLRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Short)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).GetURLRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_GetURLRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).GetURLRevisions(ctx, req.(*Short))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_RestoreURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Short)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).RestoreURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_RestoreURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).RestoreURL(ctx, req.(*Short))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_GetDeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).GetDeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_GetDeleteJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).GetDeleteJob(ctx, req.(*JobID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_ListTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).ListTags(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Label)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).CreateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_CreateTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).CreateTag(ctx, req.(*Label))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_RenameTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).RenameTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_RenameTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).RenameTag(ctx, req.(*RenameTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_DeleteTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Label)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).DeleteTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_DeleteTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).DeleteTag(ctx, req.(*Label))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_ListFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).ListFolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_ListFolders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).ListFolders(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Label)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).DeleteFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_DeleteFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).DeleteFolder(ctx, req.(*Label))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_SetURLTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetURLTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetURLTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetURLTags(ctx, req.(*SetURLTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_SetURLFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetURLFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetURLFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetURLFolder(ctx, req.(*SetURLFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_GetQR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QRRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).GetQR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_GetQR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).GetQR(ctx, req.(*QRRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_SetURLPreview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLPreviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetURLPreview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetURLPreview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetURLPreview(ctx, req.(*SetURLPreviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_SetURLPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetURLPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetURLPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetURLPassword(ctx, req.(*SetURLPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_SetURLRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetURLRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetURLRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetURLRules(ctx, req.(*SetURLRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_SetURLVariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLVariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetURLVariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetURLVariants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetURLVariants(ctx, req.(*SetURLVariantsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -326,6 +920,78 @@ var Shortener_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetURLsByUser",
 			Handler:    _Shortener_GetURLsByUser_Handler,
 		},
+		{
+			MethodName: "GetURLStats",
+			Handler:    _Shortener_GetURLStats_Handler,
+		},
+		{
+			MethodName: "UpdateURL",
+			Handler:    _Shortener_UpdateURL_Handler,
+		},
+		{
+			MethodName: "GetURLRevisions",
+			Handler:    _Shortener_GetURLRevisions_Handler,
+		},
+		{
+			MethodName: "RestoreURL",
+			Handler:    _Shortener_RestoreURL_Handler,
+		},
+		{
+			MethodName: "GetDeleteJob",
+			Handler:    _Shortener_GetDeleteJob_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _Shortener_ListTags_Handler,
+		},
+		{
+			MethodName: "CreateTag",
+			Handler:    _Shortener_CreateTag_Handler,
+		},
+		{
+			MethodName: "RenameTag",
+			Handler:    _Shortener_RenameTag_Handler,
+		},
+		{
+			MethodName: "DeleteTag",
+			Handler:    _Shortener_DeleteTag_Handler,
+		},
+		{
+			MethodName: "ListFolders",
+			Handler:    _Shortener_ListFolders_Handler,
+		},
+		{
+			MethodName: "DeleteFolder",
+			Handler:    _Shortener_DeleteFolder_Handler,
+		},
+		{
+			MethodName: "SetURLTags",
+			Handler:    _Shortener_SetURLTags_Handler,
+		},
+		{
+			MethodName: "SetURLFolder",
+			Handler:    _Shortener_SetURLFolder_Handler,
+		},
+		{
+			MethodName: "GetQR",
+			Handler:    _Shortener_GetQR_Handler,
+		},
+		{
+			MethodName: "SetURLPreview",
+			Handler:    _Shortener_SetURLPreview_Handler,
+		},
+		{
+			MethodName: "SetURLPassword",
+			Handler:    _Shortener_SetURLPassword_Handler,
+		},
+		{
+			MethodName: "SetURLRules",
+			Handler:    _Shortener_SetURLRules_Handler,
+		},
+		{
+			MethodName: "SetURLVariants",
+			Handler:    _Shortener_SetURLVariants_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/yapshrtnr.proto",
@@ -0,0 +1,188 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// HELLO WORLD 1-M из руководства thonky.com
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	ecc := rsRemainder(data, rsDivisor(10))
+	require.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ecc)
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	require.Equal(t, 0b111011111000100, formatBits(L, 0))
+	require.Equal(t, 0b101010000010010, formatBits(M, 0))
+	require.Equal(t, 0b011010101011111, formatBits(Q, 0))
+	require.Equal(t, 0b001011010001001, formatBits(H, 0))
+	require.Equal(t, 0b100000011001110, formatBits(M, 5))
+	require.Equal(t, 0b000111110010010100, versionBits(7))
+	require.Equal(t, []int{6, 30, 54}, alignmentPositions(11))
+	require.Equal(t, []int{6, 26, 46, 66}, alignmentPositions(14))
+	require.Equal(t, []int{6, 30, 58, 86, 114, 142, 170}, alignmentPositions(40))
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		level   Level
+		version int
+	}{
+		{name: "short url", data: "http://localhost:8080/abc", level: M, version: 2},
+		{name: "high", data: "http://localhost:8080/abc", level: H, version: 4},
+		{name: "version info", data: strings.Repeat("a", 200), level: Q, version: 12},
+		{name: "max", data: strings.Repeat("z", 2953), level: L, version: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Encode([]byte(tt.data), tt.level)
+			require.NoError(t, err)
+			require.Equal(t, tt.version, c.Version)
+			require.Equal(t, tt.version*4+17, c.Size)
+			require.Equal(t, tt.data, string(decode(t, c)))
+		})
+	}
+
+	_, err := Encode([]byte(strings.Repeat("z", 2954)), L)
+	require.ErrorIs(t, err, ErrTooLong)
+	_, err = Encode([]byte("a"), Level(4))
+	require.ErrorIs(t, err, ErrWrongLevel)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("Q")
+	require.NoError(t, err)
+	require.Equal(t, Q, level)
+	_, err = ParseLevel("x")
+	require.ErrorIs(t, err, ErrWrongLevel)
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode([]byte("http://localhost:8080/abc"), M)
+	require.NoError(t, err)
+
+	b, err := c.PNG(300, 4)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	// 25 модулей и 8 модулей отступа по 9 пикселей
+	require.Equal(t, 297, img.Bounds().Dx())
+	r, _, _, _ := img.At(0, 0).RGBA()
+	require.Equal(t, uint32(0xffff), r)
+	r, _, _, _ = img.At(4*9, 4*9).RGBA()
+	require.Equal(t, uint32(0), r)
+
+	b, err = c.PNG(10, 0)
+	require.NoError(t, err)
+	img, err = png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	require.Equal(t, 25, img.Bounds().Dx())
+
+	svg := string(c.SVG(300, 2))
+	require.Contains(t, svg, `width="300" height="300" viewBox="0 0 29 29"`)
+	// верхняя строка левого поискового узора
+	require.Contains(t, svg, "M2,2h7v1h-7z")
+}
+
+// decode читает данные из кода в обратном порядке: строка формата, снятие маски,
+// чтение змейкой, разбор блоков с проверкой кодов коррекции и байтового сегмента.
+func decode(t *testing.T, c *Code) []byte {
+	t.Helper()
+	var format int
+	for i := 14; i >= 9; i-- {
+		format = format<<1 | b2i(c.Black(14-i, 8))
+	}
+	format = format<<1 | b2i(c.Black(7, 8))
+	format = format<<1 | b2i(c.Black(8, 8))
+	format = format<<1 | b2i(c.Black(8, 7))
+	for i := 5; i >= 0; i-- {
+		format = format<<1 | b2i(c.Black(8, i))
+	}
+	require.Equal(t, formatBits(c.Level, c.Mask), format)
+
+	c.applyMask(c.Mask)
+	defer c.applyMask(c.Mask)
+
+	var stream []byte
+	var cur byte
+	n := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if c.isFunction[y][right-j] {
+					continue
+				}
+				cur = cur<<1 | byte(b2i(c.modules[y][right-j]))
+				n++
+				if n%8 == 0 {
+					stream = append(stream, cur)
+					cur = 0
+				}
+			}
+		}
+	}
+	rawCodewords := numRawDataModules(c.Version) / 8
+	require.Len(t, stream, rawCodewords)
+
+	numBlocks := eccBlocks[c.Level][c.Version]
+	eccLen := eccPerBlock[c.Level][c.Version]
+	numShort := numBlocks - rawCodewords%numBlocks
+	shortLen := rawCodewords / numBlocks
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortLen; i++ {
+		for j := range blocks {
+			if i == shortLen-eccLen && j < numShort {
+				continue
+			}
+			blocks[j] = append(blocks[j], stream[k])
+			k++
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		dataLen := len(block) - eccLen
+		require.Equal(t, block[dataLen:], rsRemainder(block[:dataLen], rsDivisor(eccLen)))
+		data = append(data, block[:dataLen]...)
+	}
+
+	require.Equal(t, byte(0b0100), data[0]>>4)
+	var bits bitBuffer
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	read := func(pos, n int) int {
+		v := 0
+		for _, b := range bits[pos : pos+n] {
+			v = v<<1 | b2i(b)
+		}
+		return v
+	}
+	length := read(4, countBits(c.Version))
+	res := make([]byte, length)
+	for i := range res {
+		res[i] = byte(read(4+countBits(c.Version)+i*8, 8))
+	}
+	return res
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// scale возвращает размер модуля в пикселях: наибольший целый, при котором код с отступом margin
+// помещается в size пикселей, но не меньше одного.
+func (c *Code) scale(size, margin int) int {
+	scale := size / (c.Size + 2*margin)
+	if scale < 1 {
+		return 1
+	}
+	return scale
+}
+
+// PNG отрисовывает код черно-белым PNG со стороной не больше size пикселей и отступом margin модулей.
+// Модули целого размера, поэтому сторона кратна размеру кода с отступом.
+func (c *Code) PNG(size, margin int) ([]byte, error) {
+	scale := c.scale(size, margin)
+	side := (c.Size + 2*margin) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) {
+				continue
+			}
+			x0, y0 := (x+margin)*scale, (y+margin)*scale
+			for dy := 0; dy < scale; dy++ {
+				row := img.Pix[img.PixOffset(x0, y0+dy):]
+				for dx := 0; dx < scale; dx++ {
+					row[dx] = 1
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG отрисовывает код векторным SVG со стороной size пикселей и отступом margin модулей.
+func (c *Code) SVG(size, margin int) []byte {
+	side := c.Size + 2*margin
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		size, size, side, side)
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%" fill="#FFFFFF"/>`+"\n")
+	sb.WriteString(`<path fill="#000000" d="`)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) {
+				continue
+			}
+			// соседние темные модули строки объединяются в один прямоугольник
+			run := 1
+			for c.Black(x+run, y) {
+				run++
+			}
+			fmt.Fprintf(&sb, "M%d,%dh%dv1h-%dz", x+margin, y+margin, run, run)
+			x += run - 1
+		}
+	}
+	sb.WriteString("\"/>\n</svg>\n")
+	return []byte(sb.String())
+}
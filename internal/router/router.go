@@ -19,15 +19,37 @@ func New(h *handler.Handler) http.Handler {
 	r.Use(handler.DecompressGZRequest)
 	r.Mount("/debug", middleware.Profiler())
 	r.Get("/{id}", h.GetURL)
+	r.Post("/{id}", h.PostUnlock)
+	r.Get("/{id}+", h.GetPreview)
+	r.Get("/{id}/qr", h.GetQR)
 	r.Get("/ping", h.PingDB)
 	r.Post("/", h.PostURL)
+	r.Post("/api/shorten/import", h.PostImport)
+	r.Get("/api/user/urls/export", h.ExportURLs)
 	r.Get("/api/internal/stats", h.GetInternalStats)
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.SetHeader("Content-Type", "application/json"))
 		r.Post("/api/shorten", h.PostJSON)
 		r.Get("/api/user/urls", h.GetURLsByUser)
+		r.Get("/api/user/urls/{id}/stats", h.GetURLStats)
+		r.Patch("/api/user/urls/{id}", h.PatchURL)
+		r.Get("/api/user/urls/{id}/revisions", h.GetURLRevisions)
+		r.Post("/api/user/urls/{id}/restore", h.RestoreURL)
 		r.Delete("/api/user/urls", h.DeleteBatchByUser)
+		r.Get("/api/user/jobs/{id}", h.GetDeleteJob)
+		r.Put("/api/user/urls/{id}/tags", h.PutURLTags)
+		r.Put("/api/user/urls/{id}/folder", h.PutURLFolder)
+		r.Put("/api/user/urls/{id}/preview", h.PutURLPreview)
+		r.Put("/api/user/urls/{id}/password", h.PutURLPassword)
+		r.Put("/api/user/urls/{id}/rules", h.PutURLRules)
+		r.Put("/api/user/urls/{id}/variants", h.PutURLVariants)
+		r.Get("/api/user/tags", h.GetTags)
+		r.Post("/api/user/tags", h.PostTag)
+		r.Patch("/api/user/tags/{name}", h.PatchTag)
+		r.Delete("/api/user/tags/{name}", h.DeleteTag)
+		r.Get("/api/user/folders", h.GetFolders)
+		r.Delete("/api/user/folders/{name}", h.DeleteFolder)
 		r.Post("/api/shorten/batch", h.PostBatch)
 	})
 
@@ -6,7 +6,9 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/Spear5030/yapshrtnr/internal/config"
+	"github.com/Spear5030/yapshrtnr/internal/domain"
 	"github.com/Spear5030/yapshrtnr/internal/handler"
+	"github.com/Spear5030/yapshrtnr/internal/module"
 	testStorage "github.com/Spear5030/yapshrtnr/internal/storage"
 	"github.com/Spear5030/yapshrtnr/pkg/logger"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +19,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func testRequest(t *testing.T, ts *httptest.Server, method, path, body string) (int, string) {
@@ -38,11 +41,11 @@ func TestRouter(t *testing.T) {
 	cfg, err := config.New()
 	require.NoError(t, err)
 	lg, _ := logger.New(true)
-	h := handler.New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	h := handler.New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
 	r := New(h)
 	ts := httptest.NewServer(r)
 	defer ts.Close()
-	h.Storage.SetURL(context.Background(), "user1", "tt123456", "http://ya.ru")
+	h.Storage.SetURL(context.Background(), domain.URL{Short: "tt123456", Long: "http://ya.ru", User: "user1"})
 
 	statusCode, body := testRequest(t, ts, "POST", "/", "http://longlonglong.lg")
 	assert.Equal(t, http.StatusCreated, statusCode)
@@ -63,7 +66,7 @@ func TestGZRequest(t *testing.T) {
 	cfg, err := config.New()
 	require.NoError(t, err)
 	lg, _ := logger.New(true)
-	h := handler.New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	h := handler.New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
 	r := New(h)
 	ts := httptest.NewServer(r)
 	defer ts.Close()
@@ -81,7 +84,7 @@ func TestJSON(t *testing.T) {
 	cfg, err := config.New()
 	require.NoError(t, err)
 	lg, _ := logger.New(true)
-	h := handler.New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet))
+	h := handler.New(lg, testStorage.NewMemoryStorage(), cfg.BaseURL, cfg.Key, net.IPNet(cfg.TrustedSubnet), module.RandomGenerator{}, time.Hour, nil)
 	r := New(h)
 	ts := httptest.NewServer(r)
 	defer ts.Close()
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+const (
+	clickBatchSize     = 100                // размер пакета переходов, при котором запись не ждет таймера
+	clickFlushInterval = time.Second        // период записи накопленных переходов
+	clickQueueSize     = 4 * clickBatchSize // переходы сверх очереди отбрасываются, редирект не ждет хранилище
+	topReferrers       = 10                 // количество источников в статистике ссылки
+)
+
+// clickBatcher копит переходы из канала и пакетно записывает их через flush по размеру пакета или по таймеру.
+// По аналогии с удалением в pgStorage запись в хранилище не задерживает обработку запроса
+type clickBatcher struct {
+	clicks    chan domain.Click
+	flush     func(clicks []domain.Click) error
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func newClickBatcher(flush func(clicks []domain.Click) error) *clickBatcher {
+	b := &clickBatcher{
+		clicks:  make(chan domain.Click, clickQueueSize),
+		flush:   flush,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go b.work()
+	return b
+}
+
+// add ставит переход в очередь. Если очередь переполнена - переход отбрасывается с записью в лог
+func (b *clickBatcher) add(click domain.Click) {
+	select {
+	case b.clicks <- click:
+	default:
+		log.Println("click queue is full, click dropped:", click.Short)
+	}
+}
+
+func (b *clickBatcher) work() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(clickFlushInterval)
+	defer ticker.Stop()
+	batch := make([]domain.Click, 0, clickBatchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.flush(batch); err != nil {
+			log.Println("clicks flush:", err)
+		}
+		batch = make([]domain.Click, 0, clickBatchSize)
+	}
+	for {
+		select {
+		case click := <-b.clicks:
+			batch = append(batch, click)
+			if len(batch) >= clickBatchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case <-b.done:
+			for {
+				select {
+				case click := <-b.clicks:
+					batch = append(batch, click)
+				default:
+					write()
+					return
+				}
+			}
+		}
+	}
+}
+
+// close записывает оставшиеся в очереди переходы и останавливает воркер
+func (b *clickBatcher) close() {
+	b.closeOnce.Do(func() { close(b.done) })
+	<-b.stopped
+}
+
+// clickStats считает статистику по списку переходов одной ссылки
+func clickStats(clicks []domain.Click) domain.ClickStats {
+	stats := domain.ClickStats{Total: len(clicks)}
+	byDay := make(map[time.Time]int)
+	visitors := make(map[[2]string]struct{})
+	byReferrer := make(map[string]int)
+	byVariant := make(map[string]int)
+	variantVisitors := make(map[[3]string]struct{})
+	for _, click := range clicks {
+		if click.Variant != "" {
+			byVariant[click.Variant]++
+			variantVisitors[[3]string{click.Variant, click.IP, click.UserAgent}] = struct{}{}
+		}
+		byDay[click.Time.UTC().Truncate(24*time.Hour)]++
+		visitors[[2]string{click.IP, click.UserAgent}] = struct{}{}
+		if click.Referrer != "" {
+			byReferrer[click.Referrer]++
+		}
+	}
+	stats.Unique = len(visitors)
+	for referrer, count := range byReferrer {
+		stats.TopReferrers = append(stats.TopReferrers, domain.ReferrerClicks{Referrer: referrer, Clicks: count})
+	}
+	sort.Slice(stats.TopReferrers, func(i, j int) bool {
+		if stats.TopReferrers[i].Clicks != stats.TopReferrers[j].Clicks {
+			return stats.TopReferrers[i].Clicks > stats.TopReferrers[j].Clicks
+		}
+		return stats.TopReferrers[i].Referrer < stats.TopReferrers[j].Referrer
+	})
+	if len(stats.TopReferrers) > topReferrers {
+		stats.TopReferrers = stats.TopReferrers[:topReferrers]
+	}
+	for day, count := range byDay {
+		stats.Daily = append(stats.Daily, domain.DailyClicks{Date: day, Clicks: count})
+	}
+	sort.Slice(stats.Daily, func(i, j int) bool {
+		return stats.Daily[i].Date.Before(stats.Daily[j].Date)
+	})
+	variantUnique := make(map[string]int)
+	for visitor := range variantVisitors {
+		variantUnique[visitor[0]]++
+	}
+	for variant, count := range byVariant {
+		stats.Variants = append(stats.Variants, domain.VariantClicks{Variant: variant, Clicks: count, Unique: variantUnique[variant]})
+	}
+	sort.Slice(stats.Variants, func(i, j int) bool {
+		return stats.Variants[i].Variant < stats.Variants[j].Variant
+	})
+	return stats
+}
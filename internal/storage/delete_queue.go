@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	deleteQueueSize     = 1000                   // задачи сверх очереди остаются в pending_deletes и подгружаются позже
+	deleteBatchSize     = 100                    // размер пакета задач, при котором запись не ждет таймера
+	deleteFlushInterval = 500 * time.Millisecond // период записи накопленных задач
+	deleteRetryMin      = 100 * time.Millisecond // начальная задержка повтора при ошибке записи
+	deleteRetryMax      = 10 * time.Second       // максимальная задержка повтора
+	deleteDrainTimeout  = 10 * time.Second       // сколько Shutdown ждет записи очереди
+	deleteMaxAttempts   = 5                      // после стольких неудачных попыток задачи пакета помечаются failed
+)
+
+// pendingDelete задача на удаление ссылок пользователя. id - строка в pending_deletes
+type pendingDelete struct {
+	id     int64
+	user   string
+	shorts []string
+}
+
+// deleteQueue ограниченная очередь удалений. Задачи уже сохранены в pending_deletes, очередь только
+// ускоряет их выполнение: пакет пишется по размеру или по таймеру, при ошибке - повтор с экспоненциальной задержкой.
+// Если очередь переполнена, задача остается в таблице и подгружается через load, когда очередь опустеет.
+// Если пакет не записан за deleteMaxAttempts попыток, его задачи помечаются неуспешными через fail
+type deleteQueue struct {
+	items     chan pendingDelete
+	flush     func(ctx context.Context, batch []pendingDelete) error
+	fail      func(ctx context.Context, batch []pendingDelete, cause error) error
+	load      func(ctx context.Context, limit int) ([]pendingDelete, error)
+	stale     atomic.Bool // в таблице есть задачи, не попавшие в очередь
+	done      chan struct{}
+	abort     chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	abortOnce sync.Once
+}
+
+// newDeleteQueue запускает воркер очереди. Задачи, оставшиеся в таблице с прошлого запуска, подгружаются сразу
+func newDeleteQueue(flush func(ctx context.Context, batch []pendingDelete) error,
+	fail func(ctx context.Context, batch []pendingDelete, cause error) error,
+	load func(ctx context.Context, limit int) ([]pendingDelete, error)) *deleteQueue {
+	q := &deleteQueue{
+		items:   make(chan pendingDelete, deleteQueueSize),
+		flush:   flush,
+		fail:    fail,
+		load:    load,
+		done:    make(chan struct{}),
+		abort:   make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	q.stale.Store(true)
+	go q.work()
+	return q
+}
+
+// add ставит задачу в очередь без ожидания. При переполнении задача будет подгружена из таблицы позже
+func (q *deleteQueue) add(item pendingDelete) {
+	select {
+	case <-q.done:
+		return
+	default:
+	}
+	select {
+	case q.items <- item:
+	default:
+		q.stale.Store(true)
+	}
+}
+
+func (q *deleteQueue) work() {
+	defer close(q.stopped)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-q.abort:
+			cancel()
+		case <-q.stopped:
+		}
+	}()
+
+	ticker := time.NewTicker(deleteFlushInterval)
+	defer ticker.Stop()
+	batch := make([]pendingDelete, 0, deleteBatchSize)
+	write := func() {
+		if len(batch) > 0 {
+			q.write(ctx, batch)
+			batch = make([]pendingDelete, 0, deleteBatchSize)
+		}
+	}
+	for {
+		select {
+		case item := <-q.items:
+			batch = append(batch, item)
+			if len(batch) >= deleteBatchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+			if len(q.items) == 0 && q.stale.Swap(false) {
+				q.reload(ctx)
+			}
+		case <-q.done:
+			for {
+				select {
+				case item := <-q.items:
+					batch = append(batch, item)
+					if len(batch) >= deleteBatchSize {
+						write()
+					}
+				default:
+					write()
+					return
+				}
+			}
+		}
+	}
+}
+
+// write записывает пакет, повторяя с экспоненциальной задержкой до успеха, отмены ctx или исчерпания попыток.
+// Невыполненные задачи остаются в pending_deletes до следующего запуска
+func (q *deleteQueue) write(ctx context.Context, batch []pendingDelete) {
+	delay := deleteRetryMin
+	for attempt := 1; ; attempt++ {
+		err := q.flush(ctx, batch)
+		if err == nil {
+			return
+		}
+		if attempt >= deleteMaxAttempts {
+			log.Printf("delete queue: flush %d jobs failed after %d attempts: %v", len(batch), attempt, err)
+			if err = q.fail(ctx, batch, err); err != nil {
+				log.Println("delete queue: mark jobs failed:", err)
+				q.stale.Store(true) // задачи остались в таблице, попробуем позже
+			}
+			return
+		}
+		log.Printf("delete queue: flush %d jobs: %v, retry in %v", len(batch), err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > deleteRetryMax {
+			delay = deleteRetryMax
+		}
+	}
+}
+
+// reload подгружает задачи из таблицы в очередь
+func (q *deleteQueue) reload(ctx context.Context) {
+	items, err := q.load(ctx, deleteQueueSize)
+	if err != nil {
+		log.Println("delete queue: load pending deletes:", err)
+		q.stale.Store(true)
+		return
+	}
+	for _, item := range items {
+		q.add(item)
+	}
+}
+
+// close перестает принимать задачи и записывает очередь. Если ctx истекает раньше, запись прерывается,
+// оставшиеся задачи выполнятся после перезапуска
+func (q *deleteQueue) close(ctx context.Context) error {
+	q.closeOnce.Do(func() { close(q.done) })
+	select {
+	case <-q.stopped:
+		return nil
+	case <-ctx.Done():
+		q.abortOnce.Do(func() { close(q.abort) })
+		<-q.stopped
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakePendingDeletes имитирует таблицу pending_deletes
+type fakePendingDeletes struct {
+	mu       sync.Mutex
+	pending  map[int64]pendingDelete
+	failures int // сколько следующих flush завершится ошибкой
+	flushes  int
+	failed   map[int64]string
+}
+
+func (f *fakePendingDeletes) flush(ctx context.Context, batch []pendingDelete) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flushes++
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("connection refused")
+	}
+	for _, item := range batch {
+		delete(f.pending, item.id)
+	}
+	return nil
+}
+
+func (f *fakePendingDeletes) fail(ctx context.Context, batch []pendingDelete, cause error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failed == nil {
+		f.failed = make(map[int64]string)
+	}
+	for _, item := range batch {
+		delete(f.pending, item.id)
+		f.failed[item.id] = cause.Error()
+	}
+	return nil
+}
+
+func (f *fakePendingDeletes) load(ctx context.Context, limit int) ([]pendingDelete, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var items []pendingDelete
+	for _, item := range f.pending {
+		if len(items) == limit {
+			break
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (f *fakePendingDeletes) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.pending)
+}
+
+func TestDeleteQueue_RetryAndDrain(t *testing.T) {
+	table := &fakePendingDeletes{pending: make(map[int64]pendingDelete), failures: 2}
+	q := newDeleteQueue(table.flush, table.fail, table.load)
+	for i := int64(1); i <= 3; i++ {
+		item := pendingDelete{id: i, user: "user1", shorts: []string{"short"}}
+		table.mu.Lock()
+		table.pending[i] = item
+		table.mu.Unlock()
+		q.add(item)
+	}
+	require.NoError(t, q.close(context.Background()))
+	require.Equal(t, 0, table.len())
+	require.GreaterOrEqual(t, table.flushes, 3)
+}
+
+func TestDeleteQueue_ResumePending(t *testing.T) {
+	// задачи, оставшиеся в таблице после перезапуска, подгружаются без add
+	table := &fakePendingDeletes{pending: map[int64]pendingDelete{
+		1: {id: 1, user: "user1", shorts: []string{"short1"}},
+		2: {id: 2, user: "user2", shorts: []string{"short2"}},
+	}}
+	q := newDeleteQueue(table.flush, table.fail, table.load)
+	require.Eventually(t, func() bool { return table.len() == 0 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, q.close(context.Background()))
+}
+
+func TestDeleteQueue_DrainDeadline(t *testing.T) {
+	table := &fakePendingDeletes{pending: make(map[int64]pendingDelete), failures: 1000}
+	q := newDeleteQueue(table.flush, table.fail, table.load)
+	table.mu.Lock()
+	table.pending[1] = pendingDelete{id: 1, user: "user1"}
+	table.mu.Unlock()
+	q.add(pendingDelete{id: 1, user: "user1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, q.close(ctx), context.DeadlineExceeded)
+	require.Equal(t, 1, table.len(), "невыполненная задача остается в таблице")
+}
+
+func TestDeleteQueue_FailAfterAttempts(t *testing.T) {
+	table := &fakePendingDeletes{pending: make(map[int64]pendingDelete), failures: deleteMaxAttempts}
+	q := newDeleteQueue(table.flush, table.fail, table.load)
+	item := pendingDelete{id: 1, user: "user1", shorts: []string{"short"}}
+	table.mu.Lock()
+	table.pending[1] = item
+	table.mu.Unlock()
+	q.add(item)
+	require.NoError(t, q.close(context.Background()))
+	require.Equal(t, 0, table.len())
+	require.Equal(t, deleteMaxAttempts, table.flushes)
+	require.Equal(t, "connection refused", table.failed[1])
+}
@@ -0,0 +1,509 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// fileStorage хранилище в памяти с журналом изменений в файле. Каждое изменение сначала
+// записывается в журнал и только потом применяется к map. mu упорядочивает запись в журнал,
+// чтение идет напрямую из шардов хранилища в памяти. Журнал периодически сжимается
+// в снимок, при старте загружается снимок и проигрывается только хвост журнала
+type fileStorage struct {
+	mu          sync.Mutex
+	filename    string
+	file        *os.File
+	size        int64  // смещение конца последней целой записи журнала
+	generation  uint64 // поколение текущего журнала и последнего снимка
+	compactSize int64  // размер журнала, при котором он сжимается в снимок. 0 - без ограничения
+	done        chan struct{}
+	closeOnce   sync.Once
+	clicks      *clickBatcher
+	storage
+}
+
+// NewFileStorage возвращает файловое хранилище. Состояние восстанавливается из последнего снимка
+// и хвоста журнала. Файл старого формата без журнала сначала переводится в журнал, см. migrateLegacy.
+// Снимок делается при достижении журналом compactSize байт и раз в snapshotInterval
+func NewFileStorage(filename string, compactSize int64, snapshotInterval time.Duration) (*fileStorage, error) {
+	if err := migrateLegacy(filename); err != nil {
+		return nil, err
+	}
+	fStorage := &fileStorage{
+		filename:    filename,
+		compactSize: compactSize,
+		done:        make(chan struct{}),
+		storage:     *NewMemoryStorage(),
+	}
+	generation, data, err := readSnapshot(filename)
+	if err == nil {
+		fStorage.restore(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	fStorage.generation = generation
+
+	fStorage.file, err = os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	fStorage.size, err = replayWAL(fStorage.file, generation, fStorage.apply)
+	if err != nil {
+		fStorage.file.Close()
+		return nil, err
+	}
+	fStorage.clicks = newClickBatcher(fStorage.flushClicks)
+	if snapshotInterval > 0 {
+		go fStorage.snapshotLoop(snapshotInterval)
+	}
+	return fStorage, nil
+}
+
+// compact записывает снимок следующего поколения и начинает журнал заново. Вызывается под mu.
+// Если сбой произойдет между записью снимка и заменой журнала, при старте старый журнал
+// будет распознан по поколению и отброшен
+func (fStorage *fileStorage) compact() error {
+	if fStorage.size <= int64(walHeaderSize) {
+		return nil
+	}
+	generation := fStorage.generation + 1
+	err := writeSnapshot(fStorage.filename, generation, fStorage.export())
+	if err != nil {
+		return err
+	}
+	file, err := createFileAtomic(fStorage.filename, walHeader(generation))
+	if err != nil {
+		return err
+	}
+	fStorage.file.Close()
+	fStorage.file = file
+	fStorage.size = int64(walHeaderSize)
+	fStorage.generation = generation
+	return nil
+}
+
+// snapshotLoop сжимает журнал раз в interval до вызова Shutdown
+func (fStorage *fileStorage) snapshotLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-fStorage.done:
+			return
+		case <-ticker.C:
+			fStorage.mu.Lock()
+			if err := fStorage.compact(); err != nil {
+				log.Println("file storage snapshot:", err)
+			}
+			fStorage.mu.Unlock()
+		}
+	}
+}
+
+// apply применяет запись журнала к map в памяти
+func (fStorage *fileStorage) apply(rec walRecord) {
+	switch rec.Op {
+	case opSetURLs:
+		for _, u := range rec.URLs {
+			_ = fStorage.setURL(u)
+		}
+	case opDeleteURLs:
+		if rec.JobID == "" {
+			fStorage.deleteURLs(rec.User, rec.Shorts, rec.Time)
+			break
+		}
+		fStorage.deleteJob(rec.JobID, rec.User, rec.Shorts, rec.Time)
+	case opRemoveURLs:
+		for _, u := range rec.URLs {
+			fStorage.removeURL(u.User, u.Short)
+		}
+	case opRestoreURLs:
+		for _, short := range rec.Shorts {
+			us := fStorage.urlShard(short)
+			us.mu.Lock()
+			us.restoreURL(short, rec.Time)
+			us.mu.Unlock()
+		}
+	case opAddClicks:
+		fStorage.addClicks(rec.Clicks)
+	case opUpdateURLs:
+		for _, rev := range rec.Revisions {
+			us := fStorage.urlShard(rev.Short)
+			us.mu.Lock()
+			us.applyRevision(rev)
+			us.mu.Unlock()
+		}
+	case opCreateTag:
+		_ = fStorage.labels.createTag(rec.User, rec.Name, rec.Time)
+	case opRenameTag:
+		_ = fStorage.renameTag(rec.User, rec.Name, rec.NewName)
+	case opDeleteTag:
+		_ = fStorage.deleteTag(rec.User, rec.Name)
+	case opSetURLTags:
+		for _, short := range rec.Shorts {
+			fStorage.setURLTags(rec.User, short, rec.Tags, rec.Time)
+		}
+	case opSetURLFolder:
+		for _, short := range rec.Shorts {
+			fStorage.setURLFolder(rec.User, short, rec.Name, rec.Time)
+		}
+	case opDeleteFolder:
+		_ = fStorage.deleteFolder(rec.User, rec.Name)
+	case opSetURLPreview:
+		for _, short := range rec.Shorts {
+			fStorage.setURLPreview(short, rec.Preview, rec.Time)
+		}
+	case opSetURLPassword:
+		for _, short := range rec.Shorts {
+			fStorage.setURLPassword(short, rec.Password, rec.Time)
+		}
+	case opSetURLRules:
+		for _, short := range rec.Shorts {
+			fStorage.setURLRules(short, rec.Rules, rec.Time)
+		}
+	case opSetURLVariants:
+		for _, short := range rec.Shorts {
+			fStorage.setURLVariants(short, rec.Variants, rec.Time)
+		}
+	case opUseURLs:
+		for _, short := range rec.Shorts {
+			_, _ = fStorage.storage.UseURL(context.Background(), short)
+		}
+	}
+}
+
+// appendRecord дописывает запись в журнал. При ошибке журнал обрезается до последней целой записи
+func (fStorage *fileStorage) appendRecord(rec walRecord) error {
+	b, err := encodeWALRecord(rec)
+	if err != nil {
+		return err
+	}
+	_, err = fStorage.file.Write(b)
+	if err == nil {
+		err = fStorage.file.Sync()
+	}
+	if err != nil {
+		if errTrunc := fStorage.file.Truncate(fStorage.size); errTrunc == nil {
+			_, _ = fStorage.file.Seek(fStorage.size, io.SeekStart)
+		}
+		return err
+	}
+	fStorage.size += int64(len(b))
+	return nil
+}
+
+// commit дописывает запись в журнал и применяет ее к map. При превышении compactSize делает снимок
+func (fStorage *fileStorage) commit(rec walRecord) error {
+	if err := fStorage.appendRecord(rec); err != nil {
+		return err
+	}
+	fStorage.apply(rec)
+	if fStorage.compactSize > 0 && fStorage.size >= fStorage.compactSize {
+		// запись уже в журнале, ошибка снимка не должна ее отменять
+		if err := fStorage.compact(); err != nil {
+			log.Println("file storage snapshot:", err)
+		}
+	}
+	return nil
+}
+
+// SetURL записывает ссылку в журнал, затем в map памяти
+func (fStorage *fileStorage) SetURL(ctx context.Context, url domain.URL) error {
+	return fStorage.SetBatchURLs(ctx, []domain.URL{url})
+}
+
+// SetBatchURLs пакетное сохранение ссылок. Пакет пишется в журнал одной записью.
+// Если хотя бы один short занят - ничего не пишется и возвращается domain.ErrShortExists
+func (fStorage *fileStorage) SetBatchURLs(ctx context.Context, urls []domain.URL) error {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	// все изменения идут под mu, поэтому проверка и запись атомарны
+	seen := make(map[string]struct{}, len(urls))
+	for _, u := range urls {
+		if _, ok := seen[u.Short]; ok || fStorage.exists(u.Short) {
+			return domain.NewShortExistsError(u.Short)
+		}
+		seen[u.Short] = struct{}{}
+	}
+	// время создания пишется в журнал, чтобы не меняться при проигрывании
+	now := time.Now().UTC()
+	stamped := make([]domain.URL, len(urls))
+	for i, u := range urls {
+		if u.CreatedAt.IsZero() {
+			u.CreatedAt = now
+		}
+		if u.UpdatedAt.IsZero() {
+			u.UpdatedAt = u.CreatedAt
+		}
+		stamped[i] = u
+	}
+	return fStorage.commit(walRecord{Op: opSetURLs, URLs: stamped})
+}
+
+// DeleteURLs пакетное удаление ссылок. Удаление пишется в журнал, затем применяется в памяти.
+// Результаты задачи восстанавливаются при чтении журнала
+func (fStorage *fileStorage) DeleteURLs(ctx context.Context, user string, shorts []string) (string, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	rec := walRecord{Op: opDeleteURLs, User: user, Shorts: shorts, Time: time.Now().UTC(), JobID: newJobID()}
+	if err := fStorage.commit(rec); err != nil {
+		return "", err
+	}
+	return rec.JobID, nil
+}
+
+// UpdateURL меняет полный URL ссылки пользователя. Изменение пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) UpdateURL(ctx context.Context, user, short, long string) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	// все изменения идут под mu, поэтому проверка и запись атомарны
+	us := fStorage.urlShard(short)
+	us.mu.RLock()
+	rev, err := us.revision(user, short, long)
+	us.mu.RUnlock()
+	if err != nil {
+		return domain.URL{}, err
+	}
+	if rev.OldLong == rev.NewLong {
+		return fStorage.storage.GetURL(ctx, short)
+	}
+	if err = fStorage.commit(walRecord{Op: opUpdateURLs, Revisions: []domain.Revision{rev}}); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// RestoreURL восстанавливает удаленную ссылку пользователя, если она удалена не раньше deletedAfter.
+// Восстановление пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) RestoreURL(ctx context.Context, user, short string, deletedAfter time.Time) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	us := fStorage.urlShard(short)
+	us.mu.RLock()
+	url, err := us.restorableURL(user, short, deletedAfter)
+	us.mu.RUnlock()
+	if err != nil || !url.Deleted {
+		return url, err
+	}
+	if err = fStorage.commit(walRecord{Op: opRestoreURLs, User: user, Shorts: []string{short}, Time: time.Now().UTC()}); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// PurgeDeleted окончательно удаляет ссылки, удаленные раньше deletedBefore. Удаление пишется в журнал одной записью
+func (fStorage *fileStorage) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	// задачи пересчитываются из журнала, поэтому их очистка в журнал не пишется
+	fStorage.jobs.purge(deletedBefore)
+	purgeable := fStorage.purgeableURLs(deletedBefore)
+	if len(purgeable) == 0 {
+		return 0, nil
+	}
+	if err := fStorage.commit(walRecord{Op: opRemoveURLs, URLs: purgeable}); err != nil {
+		return 0, err
+	}
+	return len(purgeable), nil
+}
+
+// DeleteExpired удаляет ссылки с истекшим сроком действия. Удаление пишется в журнал одной записью
+func (fStorage *fileStorage) DeleteExpired(ctx context.Context) (int, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	expired := fStorage.expiredURLs(time.Now())
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	if err := fStorage.commit(walRecord{Op: opRemoveURLs, URLs: expired}); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+// CreateTag создает метку пользователя. Создание пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) CreateTag(ctx context.Context, user, name string) (domain.Tag, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if fStorage.labels.hasTag(user, name) {
+		return domain.Tag{}, domain.ErrTagExists
+	}
+	rec := walRecord{Op: opCreateTag, User: user, Name: name, Time: time.Now().UTC()}
+	if err := fStorage.commit(rec); err != nil {
+		return domain.Tag{}, err
+	}
+	return domain.Tag{Name: name, CreatedAt: rec.Time}, nil
+}
+
+// RenameTag переименовывает метку пользователя вместе с ее ссылками. Изменение пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) RenameTag(ctx context.Context, user, name, newName string) (domain.Tag, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	switch {
+	case !fStorage.labels.hasTag(user, name):
+		return domain.Tag{}, domain.ErrNotFound
+	case name == newName:
+		return fStorage.tag(user, name), nil
+	case fStorage.labels.hasTag(user, newName):
+		return domain.Tag{}, domain.ErrTagExists
+	}
+	if err := fStorage.commit(walRecord{Op: opRenameTag, User: user, Name: name, NewName: newName}); err != nil {
+		return domain.Tag{}, err
+	}
+	return fStorage.tag(user, newName), nil
+}
+
+// DeleteTag удаляет метку пользователя и снимает ее со ссылок. Удаление пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) DeleteTag(ctx context.Context, user, name string) error {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if !fStorage.labels.hasTag(user, name) {
+		return domain.ErrNotFound
+	}
+	return fStorage.commit(walRecord{Op: opDeleteTag, User: user, Name: name})
+}
+
+// DeleteFolder удаляет папку пользователя, ссылки из нее остаются вне папок. Удаление пишется в журнал,
+// затем применяется в памяти
+func (fStorage *fileStorage) DeleteFolder(ctx context.Context, user, name string) error {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if !fStorage.labels.hasFolder(user, name) {
+		return domain.ErrNotFound
+	}
+	return fStorage.commit(walRecord{Op: opDeleteFolder, User: user, Name: name})
+}
+
+// SetURLTags заменяет метки ссылки пользователя. Изменение пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) SetURLTags(ctx context.Context, user, short string, tags []string) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if _, err := fStorage.ownedURL(user, short); err != nil {
+		return domain.URL{}, err
+	}
+	rec := walRecord{Op: opSetURLTags, User: user, Shorts: []string{short}, Tags: tags, Time: time.Now().UTC()}
+	if err := fStorage.commit(rec); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// SetURLFolder переносит ссылку пользователя в папку. Изменение пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) SetURLFolder(ctx context.Context, user, short, folder string) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if _, err := fStorage.ownedURL(user, short); err != nil {
+		return domain.URL{}, err
+	}
+	rec := walRecord{Op: opSetURLFolder, User: user, Shorts: []string{short}, Name: folder, Time: time.Now().UTC()}
+	if err := fStorage.commit(rec); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// SetURLPreview включает или выключает предпросмотр ссылки пользователя. Изменение пишется в журнал,
+// затем применяется в памяти
+func (fStorage *fileStorage) SetURLPreview(ctx context.Context, user, short string, preview bool) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if _, err := fStorage.ownedURL(user, short); err != nil {
+		return domain.URL{}, err
+	}
+	rec := walRecord{Op: opSetURLPreview, User: user, Shorts: []string{short}, Preview: preview, Time: time.Now().UTC()}
+	if err := fStorage.commit(rec); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// SetURLPassword задает или снимает пароль ссылки пользователя. Изменение пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) SetURLPassword(ctx context.Context, user, short, hash string) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if _, err := fStorage.ownedURL(user, short); err != nil {
+		return domain.URL{}, err
+	}
+	rec := walRecord{Op: opSetURLPassword, User: user, Shorts: []string{short}, Password: hash, Time: time.Now().UTC()}
+	if err := fStorage.commit(rec); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// SetURLRules заменяет правила перенаправления ссылки пользователя. Изменение пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) SetURLRules(ctx context.Context, user, short string, rules []domain.RedirectRule) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if _, err := fStorage.ownedURL(user, short); err != nil {
+		return domain.URL{}, err
+	}
+	rec := walRecord{Op: opSetURLRules, User: user, Shorts: []string{short}, Rules: rules, Time: time.Now().UTC()}
+	if err := fStorage.commit(rec); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// SetURLVariants заменяет варианты A/B-разделения ссылки пользователя. Изменение пишется в журнал, затем применяется в памяти
+func (fStorage *fileStorage) SetURLVariants(ctx context.Context, user, short string, variants []domain.Variant) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if _, err := fStorage.ownedURL(user, short); err != nil {
+		return domain.URL{}, err
+	}
+	rec := walRecord{Op: opSetURLVariants, User: user, Shorts: []string{short}, Variants: variants, Time: time.Now().UTC()}
+	if err := fStorage.commit(rec); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// UseURL учитывает переход по ссылке с ограничением переходов. Переход пишется в журнал до ответа,
+// чтобы израсходованные переходы не вернулись после перезапуска. Ссылки без ограничения журнал не трогают
+func (fStorage *fileStorage) UseURL(ctx context.Context, short string) (domain.URL, error) {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	url, err := fStorage.storage.GetURL(ctx, short)
+	if err != nil || url.MaxClicks == 0 {
+		return url, err
+	}
+	if url.Exhausted() {
+		return url, domain.ErrClicksExhausted
+	}
+	if err = fStorage.commit(walRecord{Op: opUseURLs, Shorts: []string{short}}); err != nil {
+		return domain.URL{}, err
+	}
+	return fStorage.storage.GetURL(ctx, short)
+}
+
+// RecordClick ставит переход в очередь. Переходы пишутся в журнал пакетами, чтобы не делать fsync на каждый редирект
+func (fStorage *fileStorage) RecordClick(ctx context.Context, click domain.Click) {
+	fStorage.clicks.add(click)
+}
+
+// flushClicks пишет пакет переходов в журнал одной записью
+func (fStorage *fileStorage) flushClicks(clicks []domain.Click) error {
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	return fStorage.commit(walRecord{Op: opAddClicks, Clicks: clicks})
+}
+
+// Shutdown записывает накопленные переходы, останавливает периодические снимки, сбрасывает журнал на диск и закрывает файл
+func (fStorage *fileStorage) Shutdown() error {
+	fStorage.clicks.close()
+	fStorage.closeOnce.Do(func() { close(fStorage.done) })
+	fStorage.mu.Lock()
+	defer fStorage.mu.Unlock()
+	if err := fStorage.file.Sync(); err != nil {
+		log.Println("file storage sync:", err)
+	}
+	return fStorage.file.Close()
+}
@@ -0,0 +1,596 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+func TestFileStorage_Replay(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetBatchURLs(ctx, []domain.URL{
+		{Short: "short002", Long: "http://b.ru", User: "user1"},
+		{Short: "short003", Long: "http://c.ru", User: "user2"},
+	}))
+	s.DeleteURLs(ctx, "user1", []string{"short002", "short003"})
+	created, _ := s.GetURL(ctx, "short001")
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, err := s.GetURL(ctx, "short001")
+	require.NoError(t, err)
+	require.Equal(t, "http://a.ru", url.Long)
+	require.False(t, url.Deleted)
+	require.False(t, url.CreatedAt.IsZero())
+	require.True(t, created.CreatedAt.Equal(url.CreatedAt), "время создания берется из журнала")
+	url, _ = s.GetURL(ctx, "short002")
+	require.True(t, url.Deleted)
+	url, _ = s.GetURL(ctx, "short003")
+	require.Equal(t, "http://c.ru", url.Long)
+	require.False(t, url.Deleted)
+	require.Equal(t, map[string]string{"short001": "http://a.ru"}, s.GetURLsByUser(ctx, "user1"))
+	users, _ := s.GetUsersCount(ctx)
+	require.Equal(t, 2, users)
+}
+
+func TestFileStorage_TornTail(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	require.NoError(t, s.Shutdown())
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(filename, info.Size()-3))
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	url, _ := s.GetURL(ctx, "short001")
+	require.Equal(t, "http://a.ru", url.Long)
+	_, err = s.GetURL(ctx, "short002")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// после отбрасывания хвоста журнал пригоден для дозаписи
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short003", Long: "http://c.ru", User: "user1"}))
+	require.NoError(t, s.Shutdown())
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ = s.GetURL(ctx, "short003")
+	require.Equal(t, "http://c.ru", url.Long)
+}
+
+func TestFileStorage_CorruptRecord(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	firstEnd := info.Size()
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	require.NoError(t, s.Shutdown())
+
+	flip := func(offset int64) {
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		data[offset] ^= 0xFF
+		require.NoError(t, os.WriteFile(filename, data, 0o644))
+	}
+
+	// поврежденная запись перед целой - ошибка, журнал не меняется
+	flip(firstEnd - 1)
+	info, err = os.Stat(filename)
+	require.NoError(t, err)
+	_, err = NewFileStorage(filename, 0, 0)
+	require.ErrorIs(t, err, errWALCorrupt)
+	after, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Equal(t, info.Size(), after.Size())
+
+	// поврежденная последняя запись отбрасывается как недописанная
+	flip(firstEnd - 1)
+	flip(info.Size() - 1)
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ := s.GetURL(ctx, "short001")
+	require.Equal(t, "http://a.ru", url.Long)
+	_, err = s.GetURL(ctx, "short002")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestFileStorage_WrongFormat(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	require.NoError(t, os.WriteFile(filename, []byte("not a log"), 0644))
+	_, err := NewFileStorage(filename, 0, 0)
+	require.ErrorIs(t, err, errWALHeader)
+}
+
+func TestFileStorage_Legacy(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	// запись как в файловом хранилище до появления журнала. Длина http://b.ru/x совпадает с разделителем
+	var legacy []byte
+	for _, l := range []legacyLink{
+		{User: "user1", Short: "short001", Long: "http://a.ru"},
+		{User: "user1", Short: "short002", Long: "http://b.ru/x"},
+		{User: "user2", Short: "short003", Long: "http://c.ru"},
+	} {
+		var buffer bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buffer).Encode(l))
+		legacy = append(legacy, append(buffer.Bytes(), 13)...)
+	}
+	require.NoError(t, os.WriteFile(filename, legacy, 0777))
+
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"short001": "http://a.ru", "short002": "http://b.ru/x"}, s.GetURLsByUser(ctx, "user1"))
+	url, err := s.GetURL(ctx, "short003")
+	require.NoError(t, err)
+	require.Equal(t, "http://c.ru", url.Long)
+	require.Equal(t, "user2", url.User)
+	kept, err := os.ReadFile(filename + legacySuffix)
+	require.NoError(t, err)
+	require.Equal(t, legacy, kept)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short004", Long: "http://d.ru", User: "user1"}))
+	require.NoError(t, s.Shutdown())
+
+	// после перевода файл - обычный журнал
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	require.Len(t, s.GetURLsByUser(ctx, "user1"), 3)
+	url, _ = s.GetURL(ctx, "short002")
+	require.Equal(t, "http://b.ru/x", url.Long)
+}
+
+func TestFileStorage_Compact(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 512, 0)
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		require.NoError(t, s.SetURL(ctx, domain.URL{Short: fmt.Sprintf("short%03d", i), Long: fmt.Sprintf("http://%d.ru", i), User: "user1"}))
+	}
+	s.DeleteURLs(ctx, "user1", []string{"short000"})
+	require.NoError(t, s.Shutdown())
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Less(t, info.Size(), int64(512))
+	_, err = os.Stat(snapshotName(filename))
+	require.NoError(t, err)
+
+	s, err = NewFileStorage(filename, 512, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	urls, _ := s.GetUrlsCount(ctx)
+	require.Equal(t, 20, urls)
+	url, _ := s.GetURL(ctx, "short000")
+	require.True(t, url.Deleted)
+	url, _ = s.GetURL(ctx, "short019")
+	require.Equal(t, "http://19.ru", url.Long)
+	// индекс ссылок пользователя восстанавливается из снимка
+	page, err := s.ListURLs(ctx, "user1", domain.ListQuery{Limit: 5, Sort: domain.SortCreated, Desc: true})
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+	page, err = s.ListURLs(ctx, "user1", domain.ListQuery{After: domain.ListCursor{Short: "short014"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+	require.Equal(t, "short015", page[0].Short)
+}
+
+func TestFileStorage_StaleLogAfterSnapshot(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.Shutdown())
+
+	// сбой между записью снимка и заменой журнала: журнал прошлого поколения уже в снимке
+	logData, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	s.mu.Lock()
+	require.NoError(t, s.compact())
+	s.mu.Unlock()
+	require.NoError(t, s.Shutdown())
+	require.NoError(t, os.WriteFile(filename, logData, 0644))
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	require.Equal(t, map[string]string{"short001": "http://a.ru"}, s.GetURLsByUser(ctx, "user1"))
+	require.Len(t, s.export().Users["user1"], 1)
+}
+
+func TestFileStorage_SnapshotInterval(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(snapshotName(filename))
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ := s.GetURL(ctx, "short001")
+	require.Equal(t, "http://a.ru", url.Long)
+}
+
+func TestFileStorage_DeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1",
+		ExpiresAt: time.Now().Add(-time.Second)}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1",
+		ExpiresAt: time.Now().Add(time.Hour)}))
+	count, err := s.DeleteExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	_, err = s.GetURL(ctx, "short001")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	url, err := s.GetURL(ctx, "short002")
+	require.NoError(t, err)
+	require.False(t, url.ExpiresAt.IsZero())
+	require.Equal(t, map[string]string{"short002": "http://b.ru"}, s.GetURLsByUser(ctx, "user1"))
+}
+
+func TestFileStorage_Clicks(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	for i := 0; i < 5; i++ {
+		s.RecordClick(ctx, domain.Click{Short: "short001", Time: time.Now(), Referrer: "http://ref.ru"})
+	}
+	// Shutdown дописывает очередь переходов в журнал
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	stats, err := s.GetClickStats(ctx, "short001")
+	require.NoError(t, err)
+	require.Equal(t, 5, stats.Total)
+}
+
+func TestFileStorage_UpdateURL(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	_, err = s.UpdateURL(ctx, "user2", "short001", "http://b.ru")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	url, err := s.UpdateURL(ctx, "user1", "short001", "http://b.ru")
+	require.NoError(t, err)
+	require.Equal(t, "http://b.ru", url.Long)
+	_, err = s.UpdateURL(ctx, "user1", "short001", "http://c.ru")
+	require.NoError(t, err)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ = s.GetURL(ctx, "short001")
+	require.Equal(t, "http://c.ru", url.Long)
+	revisions, err := s.GetURLRevisions(ctx, "short001")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, "http://a.ru", revisions[0].OldLong)
+	require.Equal(t, "http://c.ru", revisions[1].NewLong)
+	require.Equal(t, "user1", revisions[1].User)
+}
+
+func TestFileStorage_RestoreAndPurge(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	s.DeleteURLs(ctx, "user1", []string{"short001", "short002"})
+	deleted, _ := s.GetURL(ctx, "short002")
+	_, err = s.RestoreURL(ctx, "user1", "short001", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, s.Shutdown())
+
+	// время удаления восстанавливается из журнала, а не из момента проигрывания
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	url, _ := s.GetURL(ctx, "short001")
+	require.False(t, url.Deleted)
+	url, _ = s.GetURL(ctx, "short002")
+	require.True(t, url.Deleted)
+	require.True(t, deleted.DeletedAt.Equal(url.DeletedAt))
+	count, err := s.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	_, err = s.GetURL(ctx, "short002")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestFileStorage_Meta(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1",
+		Title: "Главная", Tags: []string{"news", "ru"}}))
+	s.DeleteURLs(ctx, "user1", []string{"short001"})
+	restored, err := s.RestoreURL(ctx, "user1", "short001", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.True(t, restored.UpdatedAt.After(restored.CreatedAt) || restored.UpdatedAt.Equal(restored.CreatedAt))
+	require.NoError(t, s.Shutdown())
+
+	// время изменения восстанавливается из журнала
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, err := s.GetURL(ctx, "short001")
+	require.NoError(t, err)
+	require.Equal(t, "Главная", url.Title)
+	require.Equal(t, []string{"news", "ru"}, url.Tags)
+	require.True(t, restored.CreatedAt.Equal(url.CreatedAt))
+	require.True(t, restored.UpdatedAt.Equal(url.UpdatedAt))
+}
+
+func TestFileStorage_DeleteJob(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user2"}))
+	id, err := s.DeleteURLs(ctx, "user1", []string{"short001", "short002"})
+	require.NoError(t, err)
+	job, err := s.GetDeleteJob(ctx, id)
+	require.NoError(t, err)
+	require.NoError(t, s.Shutdown())
+
+	// результаты задачи восстанавливаются при чтении журнала
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	replayed, err := s.GetDeleteJob(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, job, replayed)
+	require.Equal(t, []string{"short001"}, replayed.Deleted)
+	require.Equal(t, []string{"short002"}, replayed.Skipped)
+}
+
+func TestFileStorage_Labels(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1", Tags: []string{"go"}}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	_, err = s.CreateTag(ctx, "user1", "empty")
+	require.NoError(t, err)
+	_, err = s.RenameTag(ctx, "user1", "go", "golang")
+	require.NoError(t, err)
+	_, err = s.SetURLTags(ctx, "user1", "short002", []string{"golang", "news"})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteTag(ctx, "user1", "news"))
+	_, err = s.SetURLFolder(ctx, "user1", "short001", "work")
+	require.NoError(t, err)
+	_, err = s.SetURLFolder(ctx, "user1", "short002", "old")
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteFolder(ctx, "user1", "old"))
+	tags, _ := s.ListTags(ctx, "user1")
+	folders, _ := s.ListFolders(ctx, "user1")
+	require.NoError(t, s.Shutdown())
+
+	// метки и папки восстанавливаются из журнала вместе с временем создания
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	restoredTags, _ := s.ListTags(ctx, "user1")
+	require.Equal(t, tags, restoredTags)
+	restoredFolders, _ := s.ListFolders(ctx, "user1")
+	require.Equal(t, folders, restoredFolders)
+	url, _ := s.GetURL(ctx, "short002")
+	require.Equal(t, []string{"golang"}, url.Tags)
+	require.Empty(t, url.Folder)
+	url, _ = s.GetURL(ctx, "short001")
+	require.Equal(t, "work", url.Folder)
+}
+
+func TestFileStorage_Preview(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1", Preview: true}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	url, err := s.SetURLPreview(ctx, "user1", "short002", true)
+	require.NoError(t, err)
+	require.True(t, url.Preview)
+	_, err = s.SetURLPreview(ctx, "user1", "short001", false)
+	require.NoError(t, err)
+	_, err = s.SetURLPreview(ctx, "user2", "short001", true)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ = s.GetURL(ctx, "short001")
+	require.False(t, url.Preview)
+	url, _ = s.GetURL(ctx, "short002")
+	require.True(t, url.Preview)
+}
+
+func TestFileStorage_Password(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1", Password: "hash1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	url, err := s.SetURLPassword(ctx, "user1", "short002", "hash2")
+	require.NoError(t, err)
+	require.True(t, url.Protected())
+	_, err = s.SetURLPassword(ctx, "user1", "short001", "")
+	require.NoError(t, err)
+	_, err = s.SetURLPassword(ctx, "user2", "short002", "")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ = s.GetURL(ctx, "short001")
+	require.False(t, url.Protected())
+	url, _ = s.GetURL(ctx, "short002")
+	require.Equal(t, "hash2", url.Password)
+}
+
+func TestFileStorage_UseURL(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1", MaxClicks: 3}))
+
+	const workers = 16
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	used := 0
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.UseURL(ctx, "short001"); err == nil {
+				mu.Lock()
+				used++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 3, used)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ := s.GetURL(ctx, "short001")
+	require.Equal(t, 3, url.Uses)
+	_, err = s.UseURL(ctx, "short001")
+	require.ErrorIs(t, err, domain.ErrClicksExhausted)
+}
+
+func TestFileStorage_Schedule(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	launch := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1",
+		NotBefore: launch, Fallback: "http://a.ru/soon"}))
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, err := s.GetURL(ctx, "short001")
+	require.NoError(t, err)
+	require.True(t, launch.Equal(url.NotBefore))
+	require.Equal(t, "http://a.ru/soon", url.Fallback)
+	require.True(t, url.Pending(launch.Add(-time.Second)))
+	require.False(t, url.Pending(launch))
+}
+
+func TestFileStorage_Rules(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	rules := []domain.RedirectRule{{Platform: "ios", Long: "http://a.ru/ios"}, {Country: "DE", Language: "de", Long: "http://a.de"}}
+	url, err := s.SetURLRules(ctx, "user1", "short001", rules)
+	require.NoError(t, err)
+	require.Equal(t, rules, url.Rules)
+	_, err = s.SetURLRules(ctx, "user2", "short001", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ = s.GetURL(ctx, "short001")
+	require.Equal(t, rules, url.Rules)
+}
+
+func TestFileStorage_Variants(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "storage.log")
+	s, err := NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	variants := []domain.Variant{{Name: "a", Long: "http://a.ru/a", Weight: 1}, {Name: "b", Long: "http://a.ru/b", Weight: 2}}
+	url, err := s.SetURLVariants(ctx, "user1", "short001", variants)
+	require.NoError(t, err)
+	require.Equal(t, variants, url.Variants)
+	_, err = s.SetURLVariants(ctx, "user2", "short001", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	s.RecordClick(ctx, domain.Click{Short: "short001", IP: "1.1.1.1", Variant: "a"})
+	s.RecordClick(ctx, domain.Click{Short: "short001", IP: "1.1.1.1", Variant: "a"})
+	s.RecordClick(ctx, domain.Click{Short: "short001", IP: "2.2.2.2", Variant: "b"})
+	s.RecordClick(ctx, domain.Click{Short: "short001", IP: "3.3.3.3"})
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStorage(filename, 0, 0)
+	require.NoError(t, err)
+	defer s.Shutdown()
+	url, _ = s.GetURL(ctx, "short001")
+	require.Equal(t, variants, url.Variants)
+	stats, err := s.GetClickStats(ctx, "short001")
+	require.NoError(t, err)
+	require.Equal(t, 4, stats.Total)
+	require.Equal(t, []domain.VariantClicks{{Variant: "a", Clicks: 2, Unique: 1}, {Variant: "b", Clicks: 1, Unique: 1}}, stats.Variants)
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// jobStore задачи удаления хранилища в памяти
+type jobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]domain.DeleteJob
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{jobs: make(map[string]domain.DeleteJob)}
+}
+
+func (js *jobStore) put(job domain.DeleteJob) {
+	js.mu.Lock()
+	js.jobs[job.ID] = job
+	js.mu.Unlock()
+}
+
+// get возвращает задачу по идентификатору. Если задачи нет - domain.ErrNotFound
+func (js *jobStore) get(id string) (domain.DeleteJob, error) {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+	job, ok := js.jobs[id]
+	if !ok {
+		return domain.DeleteJob{}, domain.ErrNotFound
+	}
+	return job, nil
+}
+
+// purge удаляет задачи, завершенные раньше before
+func (js *jobStore) purge(before time.Time) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	for id, job := range js.jobs {
+		if (job.Status == domain.JobDone || job.Status == domain.JobFailed) && job.UpdatedAt.Before(before) {
+			delete(js.jobs, id)
+		}
+	}
+}
+
+// export возвращает копию задач для снимка
+func (js *jobStore) export() map[string]domain.DeleteJob {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+	jobs := make(map[string]domain.DeleteJob, len(js.jobs))
+	for id, job := range js.jobs {
+		jobs[id] = job
+	}
+	return jobs
+}
+
+// newJobID возвращает случайный идентификатор задачи
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// GetDeleteJob возвращает задачу удаления. Если задачи нет - domain.ErrNotFound
+func (mStorage *storage) GetDeleteJob(ctx context.Context, id string) (domain.DeleteJob, error) {
+	return mStorage.jobs.get(id)
+}
@@ -0,0 +1,372 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// labelStore метки и папки пользователей хранилища в памяти. Привязка к ссылкам хранится в самих ссылках,
+// здесь - только имена со временем создания, чтобы метки без ссылок не пропадали
+type labelStore struct {
+	mu      sync.RWMutex
+	tags    map[string]map[string]time.Time // user -> метка -> время создания
+	folders map[string]map[string]time.Time // user -> папка -> время создания
+}
+
+func newLabelStore() *labelStore {
+	return &labelStore{
+		tags:    make(map[string]map[string]time.Time),
+		folders: make(map[string]map[string]time.Time),
+	}
+}
+
+// addName добавляет имя пользователю, если его еще нет. Возвращает false, если имя уже было. Вызывается под mu
+func addName(names map[string]map[string]time.Time, user, name string, at time.Time) bool {
+	if _, ok := names[user][name]; ok {
+		return false
+	}
+	if names[user] == nil {
+		names[user] = make(map[string]time.Time)
+	}
+	names[user][name] = at
+	return true
+}
+
+// removeName убирает имя пользователя. Возвращает false, если имени не было. Вызывается под mu
+func removeName(names map[string]map[string]time.Time, user, name string) bool {
+	if _, ok := names[user][name]; !ok {
+		return false
+	}
+	delete(names[user], name)
+	if len(names[user]) == 0 {
+		delete(names, user)
+	}
+	return true
+}
+
+// register добавляет метки и папку ссылки, которых у пользователя еще нет
+func (ls *labelStore) register(user string, tags []string, folder string, at time.Time) {
+	if len(tags) == 0 && folder == "" {
+		return
+	}
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	for _, tag := range tags {
+		addName(ls.tags, user, tag, at)
+	}
+	if folder != "" {
+		addName(ls.folders, user, folder, at)
+	}
+}
+
+func (ls *labelStore) hasTag(user, name string) bool {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	_, ok := ls.tags[user][name]
+	return ok
+}
+
+func (ls *labelStore) hasFolder(user, name string) bool {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	_, ok := ls.folders[user][name]
+	return ok
+}
+
+// createTag добавляет метку. Если метка уже есть - domain.ErrTagExists
+func (ls *labelStore) createTag(user, name string, at time.Time) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if !addName(ls.tags, user, name, at) {
+		return domain.ErrTagExists
+	}
+	return nil
+}
+
+// renameTag переименовывает метку с сохранением времени создания. Если метки нет - domain.ErrNotFound,
+// если новое имя занято - domain.ErrTagExists
+func (ls *labelStore) renameTag(user, name, newName string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	created, ok := ls.tags[user][name]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if !addName(ls.tags, user, newName, created) {
+		return domain.ErrTagExists
+	}
+	removeName(ls.tags, user, name)
+	return nil
+}
+
+// deleteTag убирает метку. Если метки нет - domain.ErrNotFound
+func (ls *labelStore) deleteTag(user, name string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if !removeName(ls.tags, user, name) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// deleteFolder убирает папку. Если папки нет - domain.ErrNotFound
+func (ls *labelStore) deleteFolder(user, name string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if !removeName(ls.folders, user, name) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// names возвращает копию имен пользователя со временем создания
+func (ls *labelStore) names(names map[string]map[string]time.Time, user string) map[string]time.Time {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	res := make(map[string]time.Time, len(names[user]))
+	for name, at := range names[user] {
+		res[name] = at
+	}
+	return res
+}
+
+// export возвращает копию меток и папок для снимка
+func (ls *labelStore) export() (tags, folders map[string]map[string]time.Time) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	clone := func(names map[string]map[string]time.Time) map[string]map[string]time.Time {
+		res := make(map[string]map[string]time.Time, len(names))
+		for user, userNames := range names {
+			res[user] = make(map[string]time.Time, len(userNames))
+			for name, at := range userNames {
+				res[user][name] = at
+			}
+		}
+		return res
+	}
+	return clone(ls.tags), clone(ls.folders)
+}
+
+// restore добавляет метки и папки из снимка
+func (ls *labelStore) restore(tags, folders map[string]map[string]time.Time) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	for user, names := range tags {
+		for name, at := range names {
+			addName(ls.tags, user, name, at)
+		}
+	}
+	for user, names := range folders {
+		for name, at := range names {
+			addName(ls.folders, user, name, at)
+		}
+	}
+}
+
+// userLinks применяет change к каждой ссылке пользователя под блокировкой шарда.
+// Если change вернул true, ссылка сохраняется
+func (mStorage *storage) userLinks(user string, change func(url *domain.URL) bool) {
+	for _, short := range mStorage.userShorts(user) {
+		us := mStorage.urlShard(short)
+		us.mu.Lock()
+		if url, ok := us.links[short]; ok && change(&url) {
+			us.links[short] = url
+		}
+		us.mu.Unlock()
+	}
+}
+
+// linkCounts возвращает количество неудаленных ссылок пользователя по меткам и папкам
+func (mStorage *storage) linkCounts(user string) (tags, folders map[string]int) {
+	tags, folders = make(map[string]int), make(map[string]int)
+	for _, short := range mStorage.userShorts(user) {
+		us := mStorage.urlShard(short)
+		us.mu.RLock()
+		url, ok := us.links[short]
+		us.mu.RUnlock()
+		if !ok || url.Deleted {
+			continue
+		}
+		for _, tag := range url.Tags {
+			tags[tag]++
+		}
+		if url.Folder != "" {
+			folders[url.Folder]++
+		}
+	}
+	return tags, folders
+}
+
+// ListTags возвращает метки пользователя по имени с количеством ссылок
+func (mStorage *storage) ListTags(ctx context.Context, user string) ([]domain.Tag, error) {
+	counts, _ := mStorage.linkCounts(user)
+	names := mStorage.labels.names(mStorage.labels.tags, user)
+	tags := make([]domain.Tag, 0, len(names))
+	for name, at := range names {
+		tags = append(tags, domain.Tag{Name: name, Links: counts[name], CreatedAt: at})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// ListFolders возвращает папки пользователя по имени с количеством ссылок
+func (mStorage *storage) ListFolders(ctx context.Context, user string) ([]domain.Folder, error) {
+	_, counts := mStorage.linkCounts(user)
+	names := mStorage.labels.names(mStorage.labels.folders, user)
+	folders := make([]domain.Folder, 0, len(names))
+	for name, at := range names {
+		folders = append(folders, domain.Folder{Name: name, Links: counts[name], CreatedAt: at})
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].Name < folders[j].Name })
+	return folders, nil
+}
+
+// tag возвращает метку пользователя с количеством ссылок
+func (mStorage *storage) tag(user, name string) domain.Tag {
+	counts, _ := mStorage.linkCounts(user)
+	return domain.Tag{Name: name, Links: counts[name], CreatedAt: mStorage.labels.names(mStorage.labels.tags, user)[name]}
+}
+
+// CreateTag создает метку пользователя. Если метка уже есть - domain.ErrTagExists
+func (mStorage *storage) CreateTag(ctx context.Context, user, name string) (domain.Tag, error) {
+	at := time.Now().UTC()
+	if err := mStorage.labels.createTag(user, name, at); err != nil {
+		return domain.Tag{}, err
+	}
+	return domain.Tag{Name: name, CreatedAt: at}, nil
+}
+
+// RenameTag переименовывает метку пользователя вместе с ее ссылками. Если метки нет - domain.ErrNotFound,
+// если новое имя занято - domain.ErrTagExists
+func (mStorage *storage) RenameTag(ctx context.Context, user, name, newName string) (domain.Tag, error) {
+	if name != newName {
+		if err := mStorage.renameTag(user, name, newName); err != nil {
+			return domain.Tag{}, err
+		}
+	} else if !mStorage.labels.hasTag(user, name) {
+		return domain.Tag{}, domain.ErrNotFound
+	}
+	return mStorage.tag(user, newName), nil
+}
+
+func (mStorage *storage) renameTag(user, name, newName string) error {
+	if err := mStorage.labels.renameTag(user, name, newName); err != nil {
+		return err
+	}
+	mStorage.userLinks(user, func(url *domain.URL) bool {
+		i := indexOf(url.Tags, name)
+		if i < 0 {
+			return false
+		}
+		// срез меток мог быть отдан наружу, поэтому меняется копия
+		url.Tags = append([]string(nil), url.Tags...)
+		url.Tags[i] = newName
+		return true
+	})
+	return nil
+}
+
+// DeleteTag удаляет метку пользователя и снимает ее со ссылок. Если метки нет - domain.ErrNotFound
+func (mStorage *storage) DeleteTag(ctx context.Context, user, name string) error {
+	return mStorage.deleteTag(user, name)
+}
+
+func (mStorage *storage) deleteTag(user, name string) error {
+	if err := mStorage.labels.deleteTag(user, name); err != nil {
+		return err
+	}
+	mStorage.userLinks(user, func(url *domain.URL) bool {
+		i := indexOf(url.Tags, name)
+		if i < 0 {
+			return false
+		}
+		tags := make([]string, 0, len(url.Tags)-1)
+		url.Tags = append(append(tags, url.Tags[:i]...), url.Tags[i+1:]...)
+		if len(url.Tags) == 0 {
+			url.Tags = nil
+		}
+		return true
+	})
+	return nil
+}
+
+// DeleteFolder удаляет папку пользователя, ссылки из нее остаются вне папок. Если папки нет - domain.ErrNotFound
+func (mStorage *storage) DeleteFolder(ctx context.Context, user, name string) error {
+	return mStorage.deleteFolder(user, name)
+}
+
+func (mStorage *storage) deleteFolder(user, name string) error {
+	if err := mStorage.labels.deleteFolder(user, name); err != nil {
+		return err
+	}
+	mStorage.userLinks(user, func(url *domain.URL) bool {
+		if url.Folder != name {
+			return false
+		}
+		url.Folder = ""
+		return true
+	})
+	return nil
+}
+
+// ownedURL возвращает неудаленную ссылку пользователя. Для несуществующих, удаленных и чужих - domain.ErrNotFound
+func (mStorage *storage) ownedURL(user, short string) (domain.URL, error) {
+	url, err := mStorage.GetURL(context.Background(), short)
+	if err != nil || url.Deleted || url.User != user {
+		return domain.URL{}, domain.ErrNotFound
+	}
+	return url, nil
+}
+
+// SetURLTags заменяет метки ссылки пользователя. Новые метки создаются.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) SetURLTags(ctx context.Context, user, short string, tags []string) (domain.URL, error) {
+	// метки регистрируются под блокировкой шарда: только для прошедшей проверку ссылки и до того, как она их получит
+	return mStorage.updateOwnedURL(user, short, func(url *domain.URL) {
+		mStorage.labels.register(user, tags, "", time.Now().UTC())
+		url.Tags = urlTags(tags)
+	})
+}
+
+// setURLTags заменяет метки ссылки на момент at и регистрирует новые метки пользователя
+func (mStorage *storage) setURLTags(user, short string, tags []string, at time.Time) {
+	mStorage.labels.register(user, tags, "", at)
+	mStorage.updateURLAt(short, at, func(url *domain.URL) { url.Tags = urlTags(tags) })
+}
+
+// urlTags копирует метки ссылки, пустой список - nil
+func urlTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	return append([]string(nil), tags...)
+}
+
+// SetURLFolder переносит ссылку пользователя в папку, пустое имя - вне папок. Новая папка создается.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) SetURLFolder(ctx context.Context, user, short, folder string) (domain.URL, error) {
+	return mStorage.updateOwnedURL(user, short, func(url *domain.URL) {
+		mStorage.labels.register(user, nil, folder, time.Now().UTC())
+		url.Folder = folder
+	})
+}
+
+// setURLFolder переносит ссылку в папку на момент at и регистрирует новую папку пользователя
+func (mStorage *storage) setURLFolder(user, short, folder string, at time.Time) {
+	mStorage.labels.register(user, nil, folder, at)
+	mStorage.updateURLAt(short, at, func(url *domain.URL) { url.Folder = folder })
+}
+
+// indexOf возвращает позицию s в list или -1
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// Формат файлового хранилища до появления журнала: каждая ссылка - отдельный поток gob со структурой
+// {User, Short, Long} и байт legacyDelim после него. Заголовка у файла нет
+const (
+	legacyDelim  = 13
+	legacySuffix = ".legacy"
+)
+
+type legacyLink struct {
+	User  string
+	Short string
+	Long  string
+}
+
+// countingReader считает прочитанные байты. Реализует io.ByteReader, поэтому gob.Decoder
+// не читает вперед и смещение конца каждой записи известно точно
+type countingReader struct {
+	rd *bufio.Reader
+	n  int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.rd.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	b, err := cr.rd.ReadByte()
+	if err == nil {
+		cr.n++
+	}
+	return b, err
+}
+
+// readLegacyLinks читает ссылки старого формата и возвращает смещение конца последней целой записи.
+// Ошибка - запись после этого смещения не читается
+func readLegacyLinks(r io.Reader) ([]legacyLink, int64, error) {
+	cr := &countingReader{rd: bufio.NewReader(r)}
+	var links []legacyLink
+	for {
+		offset := cr.n
+		if _, err := cr.rd.Peek(1); err == io.EOF {
+			return links, offset, nil
+		}
+		var link legacyLink
+		if err := gob.NewDecoder(cr).Decode(&link); err != nil {
+			return links, offset, err
+		}
+		if b, err := cr.ReadByte(); err != nil || b != legacyDelim {
+			return links, offset, errors.New("storage: no record delimiter")
+		}
+		links = append(links, link)
+	}
+}
+
+// migrateLegacy переводит файл хранилища старого формата в журнал поколения 0 с одной записью всех ссылок.
+// Исходный файл сохраняется рядом с суффиксом legacySuffix. Журнал, пустой и отсутствующий файл не меняются.
+// Файл без заголовка журнала, из которого не читается ни одной ссылки, - ошибка errWALHeader
+func migrateLegacy(filename string) error {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || bytes.HasPrefix(data, []byte(walMagic)) {
+		return nil
+	}
+	links, offset, err := readLegacyLinks(bytes.NewReader(data))
+	if len(links) == 0 {
+		return fmt.Errorf("%w: %v", errWALHeader, err)
+	}
+	if err != nil {
+		log.Printf("file storage %s: skip unreadable legacy record at offset %d, %d bytes dropped: %v",
+			filename, offset, int64(len(data))-offset, err)
+	}
+
+	now := time.Now().UTC()
+	urls := make([]domain.URL, 0, len(links))
+	seen := make(map[string]struct{}, len(links))
+	for _, link := range links {
+		if _, ok := seen[link.Short]; ok {
+			continue
+		}
+		seen[link.Short] = struct{}{}
+		urls = append(urls, domain.URL{Short: link.Short, Long: link.Long, User: link.User, CreatedAt: now, UpdatedAt: now})
+	}
+	rec, err := encodeWALRecord(walRecord{Op: opSetURLs, URLs: urls})
+	if err != nil {
+		return err
+	}
+	// копия пишется до замены файла: сбой между шагами оставит файл старого формата, и перевод повторится
+	legacy, err := createFileAtomic(filename+legacySuffix, data)
+	if err != nil {
+		return err
+	}
+	legacy.Close()
+	file, err := createFileAtomic(filename, walHeader(0), rec)
+	if err != nil {
+		return err
+	}
+	log.Printf("file storage %s: imported %d links from legacy format, original kept in %s",
+		filename, len(urls), filename+legacySuffix)
+	return file.Close()
+}
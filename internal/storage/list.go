@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// matchLink проверяет, что ссылка проходит фильтры выборки на момент now
+func matchLink(link domain.URL, query domain.ListQuery, now time.Time) bool {
+	switch query.Status {
+	case domain.StatusActive:
+		if link.Deleted || link.Expired(now) || link.Exhausted() {
+			return false
+		}
+	case domain.StatusDeleted:
+		if !link.Deleted {
+			return false
+		}
+	}
+	if query.Domain != "" {
+		host := longHost(link.Long)
+		d := strings.ToLower(query.Domain)
+		if host != d && !strings.HasSuffix(host, "."+d) {
+			return false
+		}
+	}
+	for _, tag := range query.Tags {
+		if indexOf(link.Tags, tag) < 0 {
+			return false
+		}
+	}
+	if query.Folder != "" && link.Folder != query.Folder {
+		return false
+	}
+	if query.Search != "" {
+		search := strings.ToLower(query.Search)
+		if !strings.Contains(strings.ToLower(link.Long), search) && !strings.Contains(strings.ToLower(link.Short), search) {
+			return false
+		}
+	}
+	return true
+}
+
+// longHost возвращает хост полного URL в нижнем регистре
+func longHost(long string) string {
+	u, err := url.Parse(long)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// lessLink сравнивает позиции ссылок по полю сортировки, при равенстве - по короткому идентификатору
+func lessLink(a, b domain.ListCursor, sort domain.ListSort) bool {
+	switch sort {
+	case domain.SortCreated:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	case domain.SortClicks:
+		if a.Clicks != b.Clicks {
+			return a.Clicks < b.Clicks
+		}
+	}
+	return a.Short < b.Short
+}
+
+// userIndex ключи ссылок пользователя, упорядоченные по неизменяемым полям сортировки: короткому
+// идентификатору и времени создания. Страница ListURLs находится двоичным поиском по курсору без перебора
+// всех ссылок. Новые ключи дописываются в конец, порядок восстанавливается при следующей выборке
+type userIndex struct {
+	byShort   []domain.ListCursor
+	byCreated []domain.ListCursor
+	sorted    bool
+}
+
+// add добавляет ключ ссылки
+func (idx *userIndex) add(key domain.ListCursor) {
+	idx.byShort = append(idx.byShort, key)
+	idx.byCreated = append(idx.byCreated, key)
+	idx.sorted = len(idx.byShort) == 1
+}
+
+// remove убирает ключ ссылки, сохраняя порядок остальных
+func (idx *userIndex) remove(short string) {
+	idx.byShort = removeKey(idx.byShort, short)
+	idx.byCreated = removeKey(idx.byCreated, short)
+}
+
+// sort восстанавливает порядок ключей после добавлений
+func (idx *userIndex) sort() {
+	sort.Slice(idx.byShort, func(i, j int) bool {
+		return lessLink(idx.byShort[i], idx.byShort[j], domain.SortShort)
+	})
+	sort.Slice(idx.byCreated, func(i, j int) bool {
+		return lessLink(idx.byCreated[i], idx.byCreated[j], domain.SortCreated)
+	})
+	idx.sorted = true
+}
+
+// keys возвращает упорядоченные ключи для поля сортировки. Сортировка по переходам индексом не поддерживается
+func (idx *userIndex) keys(sort domain.ListSort) []domain.ListCursor {
+	if sort == domain.SortCreated {
+		return idx.byCreated
+	}
+	return idx.byShort
+}
+
+func removeKey(keys []domain.ListCursor, short string) []domain.ListCursor {
+	for i := range keys {
+		if keys[i].Short == short {
+			return append(keys[:i], keys[i+1:]...)
+		}
+	}
+	return keys
+}
+
+// pageStart возвращает позицию первого ключа после курсора в порядке выдачи: по возрастанию - индекс
+// от начала, по убыванию - индекс, с которого keys просматриваются к началу
+func pageStart(keys []domain.ListCursor, query domain.ListQuery) int {
+	if query.Desc {
+		if query.After.Short == "" {
+			return len(keys) - 1
+		}
+		return sort.Search(len(keys), func(i int) bool {
+			return !lessLink(keys[i], query.After, query.Sort)
+		}) - 1
+	}
+	if query.After.Short == "" {
+		return 0
+	}
+	return sort.Search(len(keys), func(i int) bool {
+		return lessLink(query.After, keys[i], query.Sort)
+	})
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// Формат снимка файлового хранилища:
+// snapshotMagic, версия (uint16), поколение (uint64), crc32 payload (uint32), payload в gob.
+// Поколение снимка совпадает с поколением журнала, записанного сразу после него.
+const (
+	snapshotMagic      = "YPSS"
+	snapshotVersion    = 1
+	snapshotHeaderSize = len(snapshotMagic) + 2 + 8 + 4
+)
+
+var errSnapshot = errors.New("storage: broken snapshot")
+
+// snapshotData содержимое map хранилища на момент снимка.
+// URLs и Deleted - формат снимков до появления Links, читаются для совместимости
+type snapshotData struct {
+	URLs      map[string]string
+	Users     map[string][]string
+	Deleted   map[string]string
+	Links     map[string]domain.URL
+	Clicks    map[string][]domain.Click
+	Revisions map[string][]domain.Revision
+	Jobs      map[string]domain.DeleteJob
+	Tags      map[string]map[string]time.Time // user -> метка -> время создания
+	Folders   map[string]map[string]time.Time
+}
+
+// links возвращает ссылки снимка. Для снимков старого формата собирает их из URLs, Deleted и Users
+func (data snapshotData) links() map[string]domain.URL {
+	if data.Links != nil {
+		return data.Links
+	}
+	owners := make(map[string]string)
+	for user, shorts := range data.Users {
+		for _, short := range shorts {
+			owners[short] = user
+		}
+	}
+	links := make(map[string]domain.URL, len(data.URLs)+len(data.Deleted))
+	for short, long := range data.URLs {
+		links[short] = domain.URL{Short: short, Long: long, User: owners[short]}
+	}
+	for short, long := range data.Deleted {
+		links[short] = domain.URL{Short: short, Long: long, User: owners[short], Deleted: true}
+	}
+	return links
+}
+
+func snapshotName(filename string) string {
+	return filename + ".snapshot"
+}
+
+// writeSnapshot атомарно записывает снимок: во временный файл, fsync, затем rename
+func writeSnapshot(filename string, generation uint64, data snapshotData) error {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(data); err != nil {
+		return err
+	}
+	header := make([]byte, snapshotHeaderSize)
+	copy(header, snapshotMagic)
+	binary.BigEndian.PutUint16(header[4:6], snapshotVersion)
+	binary.BigEndian.PutUint64(header[6:14], generation)
+	binary.BigEndian.PutUint32(header[14:18], crc32.Checksum(payload.Bytes(), crcTable))
+
+	file, err := createFileAtomic(snapshotName(filename), header, payload.Bytes())
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// readSnapshot читает последний снимок. Если снимка нет - возвращает нулевое поколение и os.ErrNotExist
+func readSnapshot(filename string) (uint64, snapshotData, error) {
+	var data snapshotData
+	file, err := os.Open(snapshotName(filename))
+	if err != nil {
+		return 0, data, err
+	}
+	defer file.Close()
+	rd := bufio.NewReader(file)
+	header := make([]byte, snapshotHeaderSize)
+	if _, err = io.ReadFull(rd, header); err != nil {
+		return 0, data, errSnapshot
+	}
+	if string(header[:4]) != snapshotMagic || binary.BigEndian.Uint16(header[4:6]) != snapshotVersion {
+		return 0, data, errSnapshot
+	}
+	payload, err := io.ReadAll(rd)
+	if err != nil {
+		return 0, data, err
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[14:18]) {
+		return 0, data, errSnapshot
+	}
+	if err = gob.NewDecoder(bytes.NewReader(payload)).Decode(&data); err != nil {
+		return 0, data, errSnapshot
+	}
+	return binary.BigEndian.Uint64(header[6:14]), data, nil
+}
+
+// createFileAtomic записывает части во временный файл рядом с name и переименовывает его в name.
+// Возвращает открытый на чтение и запись файл, позиция - в конце
+func createFileAtomic(name string, parts ...[]byte) (*os.File, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	err = tmp.Chmod(0644)
+	for _, part := range parts {
+		if err != nil {
+			break
+		}
+		_, err = tmp.Write(part)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	if dir, err := os.Open(filepath.Dir(name)); err == nil {
+		_ = dir.Sync()
+		dir.Close()
+	}
+	return tmp, nil
+}
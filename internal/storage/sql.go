@@ -3,28 +3,31 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/Spear5030/yapshrtnr/internal/domain"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
-type pgStorage struct {
-	db         *sql.DB
-	chanForDel chan urlsForDelete
-	deleteWork chan bool
-}
+// shortConstraint имя ограничения первичного ключа таблицы urls
+const shortConstraint = "urls_pkey"
 
-type urlsForDelete struct {
-	user   string
-	shorts []string
+type pgStorage struct {
+	db      *sql.DB
+	deletes *deleteQueue
+	clicks  *clickBatcher
 }
 
 // URL структура с CorrelationID для связи списков сокращенных и полных URL при пакетной обработке
@@ -76,11 +79,11 @@ func NewPGXStorage(dsn string) (*pgStorage, error) {
 		return nil, err
 	}
 	pgS := pgStorage{
-		db:         db,
-		chanForDel: make(chan urlsForDelete), //канал, в который отправляются задачи(пользователь, слайс URL)
-		deleteWork: make(chan bool),          //канал по которому стартуем саму операцию удаления()
+		db: db,
 	}
-	go pgS.WorkWithDeleteBatch(context.Background()) // функция с циклом for-select - ожидает значения в каналах chanForDel и deleteWork
+	// удаления сохраняются в pending_deletes и выполняются пакетами
+	pgS.deletes = newDeleteQueue(pgS.deleteBatch, pgS.failDeletes, pgS.loadPendingDeletes)
+	pgS.clicks = newClickBatcher(pgS.InsertClicks) // переходы копятся и пишутся пакетами
 	return &pgS, nil
 }
 
@@ -93,67 +96,191 @@ func (pgStorage *pgStorage) Ping() error {
 	return err
 }
 
-// Shutdown форсит пакетное удаление
+// Shutdown записывает очередь удалений с ограничением по времени и накопленные переходы
 func (pgStorage *pgStorage) Shutdown() error {
 	log.Println("Shutdown Postgre storage")
-	pgStorage.deleteWork <- true
+	ctx, cancel := context.WithTimeout(context.Background(), deleteDrainTimeout)
+	defer cancel()
+	if err := pgStorage.deletes.close(ctx); err != nil {
+		log.Println("delete queue not drained, pending deletes will resume after restart:", err)
+	}
+	pgStorage.clicks.close()
 	return pgStorage.db.Close()
 }
 
-// DeleteURLs отправляет в канал список удаляемых URL. Через 500мс через канал deleteWork выполняет отложенный запуск
-func (pgStorage *pgStorage) DeleteURLs(ctx context.Context, user string, shorts []string) {
-	time.AfterFunc(500*time.Millisecond, func() {
-		pgStorage.deleteWork <- true
-	})
-	chunk := urlsForDelete{user, shorts}
-	pgStorage.chanForDel <- chunk
-}
-
-// WorkWithDeleteBatch Сбор URL из канала chanForDel. По каналу deleteWork удаление в хранилище
-func (pgStorage *pgStorage) WorkWithDeleteBatch(ctx context.Context) {
-	urlsByUser := make(map[string][]string)
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Context done")
-		case x := <-pgStorage.chanForDel:
-			urlsByUser[x.user] = append(urlsByUser[x.user], x.shorts...)
-		case <-pgStorage.deleteWork:
-			for user, shorts := range urlsByUser {
-				err := pgStorage.DeleteBatchURLs(user, shorts)
-				if err != nil {
-					log.Println(err)
-				}
+// DeleteURLs сохраняет задачу удаления в pending_deletes и ставит ее в очередь. Возвращает идентификатор задачи.
+// После успешного сохранения задача будет выполнена, даже если сервис перезапустится
+func (pgStorage *pgStorage) DeleteURLs(ctx context.Context, user string, shorts []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	item := pendingDelete{user: user, shorts: shorts}
+	query := `INSERT INTO pending_deletes(userID, shorts) VALUES($1, $2) RETURNING id;`
+	if err := pgStorage.db.QueryRowContext(ctx, query, user, shorts).Scan(&item.id); err != nil {
+		return "", err
+	}
+	pgStorage.deletes.add(item)
+	return strconv.FormatInt(item.id, 10), nil
+}
+
+// deleteBatch выполняет пакет задач удаления и сохраняет их результаты одной транзакцией.
+// Ссылки пользователя попадают в deleted, в том числе удаленные ранее, остальные - в skipped
+func (pgStorage *pgStorage) deleteBatch(ctx context.Context, batch []pendingDelete) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ids := make([]int64, 0, len(batch))
+	for _, item := range batch {
+		ids = append(ids, item.id)
+	}
+	query := `UPDATE pending_deletes SET status = 'running', updated_at = now() WHERE id = any ($1) AND status = 'queued';`
+	if _, err := pgStorage.db.ExecContext(ctx, query, ids); err != nil {
+		return err
+	}
+
+	tx, err := pgStorage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `UPDATE urls SET deleted = true, deleted_at = COALESCE(deleted_at, now()),
+                        updated_at = CASE WHEN deleted THEN updated_at ELSE now() END 
+                                   WHERE userID = $1 AND short = any ($2) RETURNING short;`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	done, err := tx.PrepareContext(ctx, `UPDATE pending_deletes SET status = 'done', deleted = $2, skipped = $3, 
+                           updated_at = now() WHERE id = $1;`)
+	if err != nil {
+		return err
+	}
+	defer done.Close()
+	for _, item := range batch {
+		deleted, err := deletedShorts(ctx, stmt, item)
+		if err != nil {
+			return err
+		}
+		skipped := make([]string, 0)
+		for _, short := range item.shorts {
+			if _, ok := deleted[short]; !ok {
+				skipped = append(skipped, short)
 			}
-			urlsByUser = make(map[string][]string)
+		}
+		if _, err = done.ExecContext(ctx, item.id, sortedKeys(deleted), skipped); err != nil {
+			return err
 		}
 	}
+	return tx.Commit()
 }
 
-// DeleteBatchURLs Пакетное удаление массива URL
-func (pgStorage *pgStorage) DeleteBatchURLs(user string, shorts []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// deletedShorts помечает ссылки задачи удаленными и возвращает те, что принадлежат пользователю
+func deletedShorts(ctx context.Context, stmt *sql.Stmt, item pendingDelete) (map[string]struct{}, error) {
+	rows, err := stmt.QueryContext(ctx, item.user, item.shorts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deleted := make(map[string]struct{})
+	for rows.Next() {
+		var short string
+		if err = rows.Scan(&short); err != nil {
+			return nil, err
+		}
+		deleted[short] = struct{}{}
+	}
+	return deleted, rows.Err()
+}
+
+// sortedKeys возвращает ключи множества по возрастанию
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// failDeletes помечает задачи пакета неуспешными с текстом ошибки
+func (pgStorage *pgStorage) failDeletes(ctx context.Context, batch []pendingDelete, cause error) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ids := make([]int64, 0, len(batch))
+	for _, item := range batch {
+		ids = append(ids, item.id)
+	}
+	query := `UPDATE pending_deletes SET status = 'failed', error = $2, updated_at = now() WHERE id = any ($1);`
+	_, err := pgStorage.db.ExecContext(ctx, query, ids, cause.Error())
+	return err
+}
+
+// loadPendingDeletes возвращает невыполненные задачи удаления в порядке создания
+func (pgStorage *pgStorage) loadPendingDeletes(ctx context.Context, limit int) ([]pendingDelete, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	query := `UPDATE urls SET deleted = true WHERE 
-                                   userID = $1 AND short = any ($2);`
-	_, err := pgStorage.db.ExecContext(ctx, query, user, shorts)
+
+	query := `SELECT id, userID, shorts FROM pending_deletes WHERE status IN ('queued', 'running') ORDER BY id LIMIT $1;`
+	rows, err := pgStorage.db.QueryContext(ctx, query, limit)
 	if err != nil {
-		log.Println(err)
-		return err
+		return nil, err
 	}
-	return nil
+	defer rows.Close()
+	var items []pendingDelete
+	types := pgtype.NewMap() // database/sql не умеет сканировать массивы
+	for rows.Next() {
+		var item pendingDelete
+		if err = rows.Scan(&item.id, &item.user, types.SQLScanner(&item.shorts)); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
 }
 
-// SetURL запись URL в PostgeSQL
-func (pgStorage *pgStorage) SetURL(ctx context.Context, user, short, long string) error {
+// GetDeleteJob возвращает задачу удаления. Если задачи нет - domain.ErrNotFound
+func (pgStorage *pgStorage) GetDeleteJob(ctx context.Context, id string) (domain.DeleteJob, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO urls(short, long, userID) 
-          			VALUES($1, $2, $3);`
-	_, err := pgStorage.db.ExecContext(ctx, query, short, long, user)
+	job := domain.DeleteJob{ID: id}
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return job, domain.ErrNotFound
+	}
+	var status string
+	types := pgtype.NewMap()
+	query := `SELECT userID, shorts, status, deleted, skipped, error, created_at, updated_at FROM pending_deletes WHERE id = $1;`
+	err = pgStorage.db.QueryRowContext(ctx, query, jobID).Scan(&job.User, types.SQLScanner(&job.Shorts), &status,
+		types.SQLScanner(&job.Deleted), types.SQLScanner(&job.Skipped), &job.Error, &job.CreatedAt, &job.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, domain.ErrNotFound
+	}
+	job.Status = domain.JobStatus(status)
+	return job, err
+}
+
+// SetURL запись URL в PostgeSQL вместе с папкой и метками
+func (pgStorage *pgStorage) SetURL(ctx context.Context, url domain.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	short, long := url.Short, url.Long
+	tx, err := pgStorage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = insertURL(ctx, tx, url)
+	if err == nil {
+		err = tx.Commit()
+	}
 	var pgErr *pgconn.PgError
 	if err != nil {
+		if isShortViolation(err) {
+			return domain.NewShortExistsError(short)
+		}
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
@@ -169,26 +296,184 @@ func (pgStorage *pgStorage) SetURL(ctx context.Context, user, short, long string
 	return nil
 }
 
-// GetURL Получение оригинального URL по короткой записи. Возвращает вторым аргументом bool - удален ли URL
-func (pgStorage *pgStorage) GetURL(ctx context.Context, short string) (string, bool) {
+// insertURL добавляет ссылку в транзакции. Папка и метки, которых у пользователя еще нет, создаются
+func insertURL(ctx context.Context, tx *sql.Tx, url domain.URL) error {
+	if url.Folder != "" {
+		query := `INSERT INTO folders(userID, name) VALUES($1, $2) ON CONFLICT DO NOTHING;`
+		if _, err := tx.ExecContext(ctx, query, url.User, url.Folder); err != nil {
+			return err
+		}
+	}
+	rules, err := marshalList(url.Rules)
+	if err != nil {
+		return err
+	}
+	variants, err := marshalList(url.Variants)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO urls(short, long, userID, expires_at, title, folder, preview, password_hash, max_clicks,
+                                   not_before, fallback_url, rules, variants) 
+                                   VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::jsonb, $13::jsonb);`
+	_, err = tx.ExecContext(ctx, query, url.Short, url.Long, url.User, nullTime(url.ExpiresAt), url.Title,
+		nullString(url.Folder), url.Preview, url.Password, url.MaxClicks, nullTime(url.NotBefore), url.Fallback, rules,
+		variants)
+	if err != nil || len(url.Tags) == 0 {
+		return err
+	}
+	return insertURLTags(ctx, tx, url.User, url.Short, url.Tags)
+}
+
+// insertURLTags привязывает метки к ссылке в транзакции с сохранением порядка. Недостающие метки пользователя создаются
+func insertURLTags(ctx context.Context, tx *sql.Tx, user, short string, tags []string) error {
+	query := `INSERT INTO tags(userID, name) SELECT $1, unnest($2::varchar[]) ON CONFLICT DO NOTHING;`
+	if _, err := tx.ExecContext(ctx, query, user, tags); err != nil {
+		return err
+	}
+	query = `INSERT INTO url_tags(short, userID, tag, position) 
+                        SELECT $1, $2, t.tag, t.position FROM unnest($3::varchar[]) WITH ORDINALITY AS t(tag, position);`
+	_, err := tx.ExecContext(ctx, query, short, user, tags)
+	return err
+}
+
+// GetURL Получение ссылки по короткой записи. Если ссылки нет - domain.ErrNotFound
+func (pgStorage *pgStorage) GetURL(ctx context.Context, short string) (domain.URL, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	sql := `SELECT long, deleted FROM urls WHERE short=$1;`
-	row := pgStorage.db.QueryRowContext(ctx, sql, short)
-	var long string
-	var deleted bool
-
-	err := row.Scan(&long, &deleted)
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE short=$1;`
+	var url domain.URL
+	err := scanURL(pgtype.NewMap(), pgStorage.db.QueryRowContext(ctx, query, short), &url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.URL{Short: short}, domain.ErrNotFound
+	}
 	if err != nil {
 		log.Println(err)
+		return url, err
 	}
-	return long, deleted
+	return url, nil
 }
 
-// GetURLsByUser не имплементировано на БД
+// ListURLs возвращает страницу ссылок пользователя с количеством переходов, отфильтрованных и упорядоченных по query.
+// Ссылки без времени создания считаются созданными в нулевой момент
+func (pgStorage *pgStorage) ListURLs(ctx context.Context, user string, query domain.ListQuery) ([]domain.LinkInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	args := []any{user}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	where := []string{"u.userID = $1"}
+	switch query.Status {
+	case domain.StatusActive:
+		where = append(where, "u.deleted IS NOT TRUE AND (u.expires_at IS NULL OR u.expires_at > now()) AND "+
+			"(u.max_clicks = 0 OR u.uses < u.max_clicks)")
+	case domain.StatusDeleted:
+		where = append(where, "u.deleted")
+	}
+	if query.Domain != "" {
+		host := `lower(substring(u.long from '^[^:]+://(?:[^@/]*@)?([^/:?#]+)'))`
+		d := strings.ToLower(query.Domain)
+		where = append(where, fmt.Sprintf("(%s = %s OR %s LIKE %s)", host, arg(d), host, arg("%."+likeEscape(d))))
+	}
+	if query.Search != "" {
+		search := arg("%" + likeEscape(query.Search) + "%")
+		where = append(where, fmt.Sprintf("(u.long ILIKE %s OR u.short ILIKE %s)", search, search))
+	}
+	for _, tag := range query.Tags {
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM url_tags t WHERE t.short = u.short AND t.tag = %s)", arg(tag)))
+	}
+	if query.Folder != "" {
+		where = append(where, "u.folder = "+arg(query.Folder))
+	}
+
+	key := ""
+	switch query.Sort {
+	case domain.SortCreated:
+		key = "l.created_at"
+	case domain.SortClicks:
+		key = "l.clicks"
+	}
+	dir, cmp := "ASC", ">"
+	if query.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	var after, order string
+	switch {
+	case key == "":
+		order = "l.short " + dir
+		if query.After.Short != "" {
+			after = fmt.Sprintf("WHERE l.short %s %s", cmp, arg(query.After.Short))
+		}
+	default:
+		order = fmt.Sprintf("%s %s, l.short %s", key, dir, dir)
+		if query.After.Short != "" {
+			var value any = query.After.CreatedAt
+			if query.Sort == domain.SortClicks {
+				value = query.After.Clicks
+			}
+			after = fmt.Sprintf("WHERE (%s, l.short) %s (%s, %s)", key, cmp, arg(value), arg(query.After.Short))
+		}
+	}
+	sqlQuery := fmt.Sprintf(`SELECT `+urlFields+`, l.clicks FROM (
+       SELECT u.short, u.long, u.userID, u.deleted, u.deleted_at, u.expires_at, 
+              COALESCE(u.created_at, '0001-01-01 00:00:00+00') AS created_at, u.updated_at, u.title, u.folder, u.preview, u.password_hash,
+              u.max_clicks, u.uses, u.not_before, u.fallback_url, u.rules, u.variants,
+              ARRAY(SELECT t.tag FROM url_tags t WHERE t.short = u.short ORDER BY t.position) AS tags,
+              (SELECT count(*) FROM clicks c WHERE c.short = u.short) AS clicks 
+       FROM urls u WHERE %s) l %s ORDER BY %s LIMIT %s;`,
+		strings.Join(where, " AND "), after, order, arg(query.Limit))
+
+	rows, err := pgStorage.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	links := make([]domain.LinkInfo, 0, query.Limit)
+	types := pgtype.NewMap()
+	for rows.Next() {
+		var link domain.LinkInfo
+		if err = scanURL(types, rows, &link.URL, &link.Clicks); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// likeEscape экранирует спецсимволы шаблона LIKE
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetURLsByUser возвращает список URL созданных пользователем. Удаленные и истекшие URL не возвращаются
 func (pgStorage *pgStorage) GetURLsByUser(ctx context.Context, user string) (urls map[string]string) {
-	return nil
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	urls = make(map[string]string)
+	query := `SELECT short, long FROM urls WHERE userID = $1 AND deleted IS NOT TRUE 
+                                    AND (expires_at IS NULL OR expires_at > now()) ORDER BY short;`
+	rows, err := pgStorage.db.QueryContext(ctx, query, user)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var short, long string
+		if err = rows.Scan(&short, &long); err != nil {
+			log.Println(err)
+			return
+		}
+		urls[short] = long
+	}
+	if err = rows.Err(); err != nil {
+		log.Println(err)
+	}
+	return
 }
 
 // SetBatchURLs Пакетная запись URL в PostgreSQL
@@ -201,19 +486,649 @@ func (pgStorage *pgStorage) SetBatchURLs(ctx context.Context, urls []domain.URL)
 		return err
 	}
 	defer tx.Rollback()
-	stmt, err := tx.PrepareContext(ctx, "INSERT INTO urls(short, long, userID) VALUES($1,$2,$3);")
+	for _, url := range urls {
+		if err = insertURL(ctx, tx, url); err != nil {
+			if isShortViolation(err) {
+				return domain.NewShortExistsError(url.Short)
+			}
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteExpired удаляет ссылки с истекшим сроком действия. Возвращает количество удаленных
+func (pgStorage *pgStorage) DeleteExpired(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM urls WHERE expires_at <= now();`
+	res, err := pgStorage.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	count, err := res.RowsAffected()
+	return int(count), err
+}
+
+// UpdateURL меняет полный URL ссылки пользователя и пишет изменение в url_revisions одной транзакцией.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound. Если новый URL уже сокращен другой ссылкой -
+// DuplicationError с ее идентификатором
+func (pgStorage *pgStorage) UpdateURL(ctx context.Context, user, short, long string) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	tx, err := pgStorage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return url, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE short = $1 AND userID = $2 AND deleted IS NOT TRUE FOR UPDATE;`
+	err = scanURL(pgtype.NewMap(), tx.QueryRowContext(ctx, query, short, user), &url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return url, domain.ErrNotFound
+	}
+	if err != nil {
+		return url, err
+	}
+	oldLong := url.Long
+	if oldLong == long {
+		return url, nil
+	}
+	url.Long = long
+	url.UpdatedAt = time.Now().UTC()
+	// уникальный индекс long_idx1 проверяется на UPDATE, конфликт с другой ссылкой откатывает транзакцию
+	query = `UPDATE urls SET long = $1, updated_at = $2 WHERE short = $3;`
+	if _, err = tx.ExecContext(ctx, query, long, url.UpdatedAt, short); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			var duplication string
+			_ = pgStorage.db.QueryRowContext(ctx, `SELECT short FROM urls WHERE long = $1;`, long).Scan(&duplication)
+			return url, NewDuplicationError(duplication, err)
+		}
+		return url, err
+	}
+	query = `INSERT INTO url_revisions(short, old_long, new_long, userID, changed_at) VALUES($1, $2, $3, $4, $5);`
+	if _, err = tx.ExecContext(ctx, query, short, oldLong, long, user, url.UpdatedAt); err != nil {
+		return url, err
+	}
+	return url, tx.Commit()
+}
+
+// GetURLRevisions возвращает историю изменений ссылки от старых к новым. Если ссылки нет - domain.ErrNotFound
+func (pgStorage *pgStorage) GetURLRevisions(ctx context.Context, short string) ([]domain.Revision, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var exists bool
+	err := pgStorage.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM urls WHERE short = $1);`, short).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	query := `SELECT old_long, new_long, userID, changed_at FROM url_revisions WHERE short = $1 ORDER BY id;`
+	rows, err := pgStorage.db.QueryContext(ctx, query, short)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var revisions []domain.Revision
+	for rows.Next() {
+		rev := domain.Revision{Short: short}
+		if err = rows.Scan(&rev.OldLong, &rev.NewLong, &rev.User, &rev.ChangedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// RecordClick отправляет переход в очередь на пакетную запись
+func (pgStorage *pgStorage) RecordClick(ctx context.Context, click domain.Click) {
+	pgStorage.clicks.add(click)
+}
+
+// InsertClicks пакетная запись переходов. Переходы по уже удаленным из таблицы ссылкам пропускаются
+func (pgStorage *pgStorage) InsertClicks(clicks []domain.Click) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := pgStorage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO clicks(short, clicked_at, referrer, user_agent, ip, variant) 
+		SELECT $1, $2, $3, $4, $5, $6 WHERE EXISTS (SELECT 1 FROM urls WHERE short = $1);`)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
-	for _, url := range urls {
-		if _, err = stmt.ExecContext(ctx, url.Short, url.Long, url.User); err != nil {
+	for _, click := range clicks {
+		if _, err = stmt.ExecContext(ctx, click.Short, click.Time, click.Referrer, click.UserAgent, click.IP,
+			click.Variant); err != nil {
 			return err
 		}
 	}
 	return tx.Commit()
 }
 
+// GetClickStats возвращает статистику переходов по ссылке. Если ссылки нет - domain.ErrNotFound
+func (pgStorage *pgStorage) GetClickStats(ctx context.Context, short string) (domain.ClickStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var stats domain.ClickStats
+	var exists bool
+	err := pgStorage.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM urls WHERE short = $1);`, short).Scan(&exists)
+	if err != nil {
+		return stats, err
+	}
+	if !exists {
+		return stats, domain.ErrNotFound
+	}
+	query := `SELECT COUNT(*), COUNT(DISTINCT (ip, user_agent)) FROM clicks WHERE short = $1;`
+	if err = pgStorage.db.QueryRowContext(ctx, query, short).Scan(&stats.Total, &stats.Unique); err != nil {
+		return stats, err
+	}
+	if stats.TopReferrers, err = pgStorage.topReferrers(ctx, short); err != nil {
+		return stats, err
+	}
+	if stats.Variants, err = pgStorage.variantClicks(ctx, short); err != nil {
+		return stats, err
+	}
+	query = `SELECT date_trunc('day', clicked_at AT TIME ZONE 'UTC') AS day, COUNT(*) FROM clicks 
+                                   WHERE short = $1 GROUP BY day ORDER BY day;`
+	rows, err := pgStorage.db.QueryContext(ctx, query, short)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var day domain.DailyClicks
+		if err = rows.Scan(&day.Date, &day.Clicks); err != nil {
+			return stats, err
+		}
+		day.Date = time.Date(day.Date.Year(), day.Date.Month(), day.Date.Day(), 0, 0, 0, 0, time.UTC)
+		stats.Daily = append(stats.Daily, day)
+	}
+	return stats, rows.Err()
+}
+
+// topReferrers возвращает самые частые непустые источники переходов по ссылке
+func (pgStorage *pgStorage) topReferrers(ctx context.Context, short string) ([]domain.ReferrerClicks, error) {
+	query := `SELECT referrer, COUNT(*) AS cnt FROM clicks WHERE short = $1 AND referrer <> '' 
+                                   GROUP BY referrer ORDER BY cnt DESC, referrer LIMIT $2;`
+	rows, err := pgStorage.db.QueryContext(ctx, query, short, topReferrers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var referrers []domain.ReferrerClicks
+	for rows.Next() {
+		var referrer domain.ReferrerClicks
+		if err = rows.Scan(&referrer.Referrer, &referrer.Clicks); err != nil {
+			return nil, err
+		}
+		referrers = append(referrers, referrer)
+	}
+	return referrers, rows.Err()
+}
+
+// variantClicks возвращает переходы и уникальных посетителей по вариантам A/B-разделения ссылки
+func (pgStorage *pgStorage) variantClicks(ctx context.Context, short string) ([]domain.VariantClicks, error) {
+	query := `SELECT variant, COUNT(*), COUNT(DISTINCT (ip, user_agent)) FROM clicks WHERE short = $1 AND variant <> '' 
+                                   GROUP BY variant ORDER BY variant;`
+	rows, err := pgStorage.db.QueryContext(ctx, query, short)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var variants []domain.VariantClicks
+	for rows.Next() {
+		var variant domain.VariantClicks
+		if err = rows.Scan(&variant.Variant, &variant.Clicks, &variant.Unique); err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+// RestoreURL восстанавливает удаленную ссылку пользователя, если она удалена не раньше deletedAfter.
+// Для несуществующих, чужих и удаленных раньше ссылок - domain.ErrNotFound
+func (pgStorage *pgStorage) RestoreURL(ctx context.Context, user, short string, deletedAfter time.Time) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	query := `UPDATE urls SET deleted = false, deleted_at = NULL, 
+                        updated_at = CASE WHEN deleted THEN now() ELSE updated_at END
+                                   WHERE short = $1 AND userID = $2 AND (deleted IS NOT TRUE OR deleted_at >= $3) 
+                                   RETURNING ` + urlColumns + `;`
+	err := scanURL(pgtype.NewMap(), pgStorage.db.QueryRowContext(ctx, query, short, user, deletedAfter), &url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return url, domain.ErrNotFound
+	}
+	return url, err
+}
+
+// PurgeDeleted окончательно удаляет ссылки, удаленные раньше deletedBefore. Переходы и история удаляются каскадно.
+// Вместе с ними удаляются задачи удаления, завершенные раньше deletedBefore
+func (pgStorage *pgStorage) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := pgStorage.db.ExecContext(ctx, `DELETE FROM urls WHERE deleted AND deleted_at < $1;`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	// завершенные задачи удаления хранятся столько же, сколько удаленные ссылки
+	query := `DELETE FROM pending_deletes WHERE status IN ('done', 'failed') AND updated_at < $1;`
+	if _, err = pgStorage.db.ExecContext(ctx, query, deletedBefore); err != nil {
+		return 0, err
+	}
+	count, err := res.RowsAffected()
+	return int(count), err
+}
+
+// ListTags возвращает метки пользователя по имени с количеством неудаленных ссылок
+func (pgStorage *pgStorage) ListTags(ctx context.Context, user string) ([]domain.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := pgStorage.db.QueryContext(ctx, tagsQuery+` GROUP BY t.name, t.created_at ORDER BY t.name;`, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tags := make([]domain.Tag, 0)
+	for rows.Next() {
+		var tag domain.Tag
+		if err = rows.Scan(&tag.Name, &tag.CreatedAt, &tag.Links); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// tagsQuery выборка меток пользователя с количеством неудаленных ссылок, без группировки
+const tagsQuery = `SELECT t.name, t.created_at, count(u.short) FROM tags t 
+                                   LEFT JOIN url_tags ut ON ut.userID = t.userID AND ut.tag = t.name 
+                                   LEFT JOIN urls u ON u.short = ut.short AND u.deleted IS NOT TRUE 
+                                   WHERE t.userID = $1`
+
+// getTag возвращает метку пользователя. Если метки нет - domain.ErrNotFound
+func (pgStorage *pgStorage) getTag(ctx context.Context, user, name string) (domain.Tag, error) {
+	var tag domain.Tag
+	err := pgStorage.db.QueryRowContext(ctx, tagsQuery+` AND t.name = $2 GROUP BY t.name, t.created_at;`, user, name).
+		Scan(&tag.Name, &tag.CreatedAt, &tag.Links)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tag, domain.ErrNotFound
+	}
+	return tag, err
+}
+
+// CreateTag создает метку пользователя. Если метка уже есть - domain.ErrTagExists
+func (pgStorage *pgStorage) CreateTag(ctx context.Context, user, name string) (domain.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag := domain.Tag{Name: name}
+	query := `INSERT INTO tags(userID, name) VALUES($1, $2) RETURNING created_at;`
+	err := pgStorage.db.QueryRowContext(ctx, query, user, name).Scan(&tag.CreatedAt)
+	if isUniqueViolation(err) {
+		return tag, domain.ErrTagExists
+	}
+	return tag, err
+}
+
+// RenameTag переименовывает метку пользователя, привязки к ссылкам меняются каскадно.
+// Если метки нет - domain.ErrNotFound, если новое имя занято - domain.ErrTagExists
+func (pgStorage *pgStorage) RenameTag(ctx context.Context, user, name, newName string) (domain.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if name != newName {
+		query := `UPDATE tags SET name = $3 WHERE userID = $1 AND name = $2;`
+		res, err := pgStorage.db.ExecContext(ctx, query, user, name, newName)
+		if isUniqueViolation(err) {
+			return domain.Tag{}, domain.ErrTagExists
+		}
+		if err != nil {
+			return domain.Tag{}, err
+		}
+		if count, err := res.RowsAffected(); err != nil || count == 0 {
+			return domain.Tag{}, notFound(err)
+		}
+	}
+	return pgStorage.getTag(ctx, user, newName)
+}
+
+// DeleteTag удаляет метку пользователя, привязки к ссылкам удаляются каскадно. Если метки нет - domain.ErrNotFound
+func (pgStorage *pgStorage) DeleteTag(ctx context.Context, user, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := pgStorage.db.ExecContext(ctx, `DELETE FROM tags WHERE userID = $1 AND name = $2;`, user, name)
+	if err != nil {
+		return err
+	}
+	if count, err := res.RowsAffected(); err != nil || count == 0 {
+		return notFound(err)
+	}
+	return nil
+}
+
+// ListFolders возвращает папки пользователя по имени с количеством неудаленных ссылок
+func (pgStorage *pgStorage) ListFolders(ctx context.Context, user string) ([]domain.Folder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `SELECT f.name, f.created_at, count(u.short) FROM folders f 
+                                   LEFT JOIN urls u ON u.userID = f.userID AND u.folder = f.name AND u.deleted IS NOT TRUE 
+                                   WHERE f.userID = $1 GROUP BY f.name, f.created_at ORDER BY f.name;`
+	rows, err := pgStorage.db.QueryContext(ctx, query, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	folders := make([]domain.Folder, 0)
+	for rows.Next() {
+		var folder domain.Folder
+		if err = rows.Scan(&folder.Name, &folder.CreatedAt, &folder.Links); err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+	return folders, rows.Err()
+}
+
+// DeleteFolder удаляет папку пользователя, ссылки из нее остаются вне папок. Если папки нет - domain.ErrNotFound
+func (pgStorage *pgStorage) DeleteFolder(ctx context.Context, user, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := pgStorage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err = tx.ExecContext(ctx, `UPDATE urls SET folder = NULL WHERE userID = $1 AND folder = $2;`, user, name); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM folders WHERE userID = $1 AND name = $2;`, user, name)
+	if err != nil {
+		return err
+	}
+	if count, err := res.RowsAffected(); err != nil || count == 0 {
+		return notFound(err)
+	}
+	return tx.Commit()
+}
+
+// SetURLTags заменяет метки ссылки пользователя одной транзакцией. Недостающие метки создаются.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (pgStorage *pgStorage) SetURLTags(ctx context.Context, user, short string, tags []string) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	tx, err := pgStorage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return url, err
+	}
+	defer tx.Rollback()
+	query := `UPDATE urls SET updated_at = now() WHERE short = $1 AND userID = $2 AND deleted IS NOT TRUE RETURNING short;`
+	if err = tx.QueryRowContext(ctx, query, short, user).Scan(&short); err != nil {
+		return url, notFound(err)
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM url_tags WHERE short = $1;`, short); err != nil {
+		return url, err
+	}
+	if len(tags) > 0 {
+		if err = insertURLTags(ctx, tx, user, short, tags); err != nil {
+			return url, err
+		}
+	}
+	query = `SELECT ` + urlColumns + ` FROM urls WHERE short = $1;`
+	if err = scanURL(pgtype.NewMap(), tx.QueryRowContext(ctx, query, short), &url); err != nil {
+		return url, err
+	}
+	return url, tx.Commit()
+}
+
+// SetURLFolder переносит ссылку пользователя в папку, пустое имя - вне папок. Новая папка создается.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (pgStorage *pgStorage) SetURLFolder(ctx context.Context, user, short, folder string) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	tx, err := pgStorage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return url, err
+	}
+	defer tx.Rollback()
+	if folder != "" {
+		query := `INSERT INTO folders(userID, name) VALUES($1, $2) ON CONFLICT DO NOTHING;`
+		if _, err = tx.ExecContext(ctx, query, user, folder); err != nil {
+			return url, err
+		}
+	}
+	query := `UPDATE urls SET folder = $3, updated_at = now() WHERE short = $1 AND userID = $2 AND deleted IS NOT TRUE 
+                                   RETURNING ` + urlColumns + `;`
+	err = scanURL(pgtype.NewMap(), tx.QueryRowContext(ctx, query, short, user, nullString(folder)), &url)
+	if err != nil {
+		return url, notFound(err)
+	}
+	return url, tx.Commit()
+}
+
+// SetURLPreview включает или выключает предпросмотр ссылки пользователя.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (pgStorage *pgStorage) SetURLPreview(ctx context.Context, user, short string, preview bool) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	query := `UPDATE urls SET preview = $3, updated_at = now() WHERE short = $1 AND userID = $2 AND deleted IS NOT TRUE 
+                                   RETURNING ` + urlColumns + `;`
+	err := scanURL(pgtype.NewMap(), pgStorage.db.QueryRowContext(ctx, query, short, user, preview), &url)
+	if err != nil {
+		return url, notFound(err)
+	}
+	return url, nil
+}
+
+// SetURLPassword задает bcrypt-хэш пароля ссылки пользователя, пустой хэш снимает пароль.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (pgStorage *pgStorage) SetURLPassword(ctx context.Context, user, short, hash string) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	query := `UPDATE urls SET password_hash = $3, updated_at = now() WHERE short = $1 AND userID = $2 AND deleted IS NOT TRUE 
+                                   RETURNING ` + urlColumns + `;`
+	err := scanURL(pgtype.NewMap(), pgStorage.db.QueryRowContext(ctx, query, short, user, hash), &url)
+	if err != nil {
+		return url, notFound(err)
+	}
+	return url, nil
+}
+
+// SetURLRules заменяет правила перенаправления ссылки пользователя, пустой список убирает правила.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (pgStorage *pgStorage) SetURLRules(ctx context.Context, user, short string, rules []domain.RedirectRule) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	rulesJSON, err := marshalList(rules)
+	if err != nil {
+		return url, err
+	}
+	query := `UPDATE urls SET rules = $3::jsonb, updated_at = now() WHERE short = $1 AND userID = $2 AND deleted IS NOT TRUE 
+                                   RETURNING ` + urlColumns + `;`
+	err = scanURL(pgtype.NewMap(), pgStorage.db.QueryRowContext(ctx, query, short, user, rulesJSON), &url)
+	if err != nil {
+		return url, notFound(err)
+	}
+	return url, nil
+}
+
+// SetURLVariants заменяет варианты A/B-разделения ссылки пользователя, пустой список убирает разделение.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (pgStorage *pgStorage) SetURLVariants(ctx context.Context, user, short string, variants []domain.Variant) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url := domain.URL{Short: short, User: user}
+	variantsJSON, err := marshalList(variants)
+	if err != nil {
+		return url, err
+	}
+	query := `UPDATE urls SET variants = $3::jsonb, updated_at = now() WHERE short = $1 AND userID = $2 AND deleted IS NOT TRUE 
+                                   RETURNING ` + urlColumns + `;`
+	err = scanURL(pgtype.NewMap(), pgStorage.db.QueryRowContext(ctx, query, short, user, variantsJSON), &url)
+	if err != nil {
+		return url, notFound(err)
+	}
+	return url, nil
+}
+
+// marshalList переводит список в JSON для колонок rules и variants, пустой список - в []
+func marshalList[T any](list []T) (string, error) {
+	if len(list) == 0 {
+		return "[]", nil
+	}
+	b, err := json.Marshal(list)
+	return string(b), err
+}
+
+// unmarshalList читает список из JSON колонок rules и variants, пустой список - nil
+func unmarshalList[T any](b []byte) ([]T, error) {
+	var list []T
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(b, &list); err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return list, nil
+}
+
+// UseURL учитывает переход по ссылке с ограничением переходов условным UPDATE: из конкурирующих запросов
+// за последний переход строку меняет только один. Если переходы закончились - domain.ErrClicksExhausted,
+// если ссылки нет - domain.ErrNotFound. Ссылки без ограничения не меняются
+func (pgStorage *pgStorage) UseURL(ctx context.Context, short string) (domain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var url domain.URL
+	types := pgtype.NewMap()
+	query := `UPDATE urls SET uses = uses + 1 WHERE short = $1 AND max_clicks > 0 AND uses < max_clicks 
+                                   RETURNING ` + urlColumns + `;`
+	err := scanURL(types, pgStorage.db.QueryRowContext(ctx, query, short), &url)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return url, err
+	}
+	query = `SELECT ` + urlColumns + ` FROM urls WHERE short = $1;`
+	if err = scanURL(types, pgStorage.db.QueryRowContext(ctx, query, short), &url); err != nil {
+		return url, notFound(err)
+	}
+	if url.Exhausted() {
+		return url, domain.ErrClicksExhausted
+	}
+	return url, nil
+}
+
+// notFound переводит отсутствие строк в domain.ErrNotFound. Нулевая ошибка - тоже domain.ErrNotFound:
+// так вызывается при нуле измененных строк
+func notFound(err error) error {
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+// isUniqueViolation проверяет, что ошибка - нарушение уникальности
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation
+}
+
+const (
+	// urlFields поля ссылки в порядке, который читает scanURL
+	urlFields = `short, long, userID, deleted, deleted_at, expires_at, created_at, updated_at, title, folder, preview,
+       password_hash, max_clicks, uses, not_before, fallback_url, rules, variants, tags`
+	// urlColumns те же поля при чтении из urls, метки собираются из url_tags
+	urlColumns = `short, long, userID, deleted, deleted_at, expires_at, created_at, updated_at, title, folder, preview,
+       password_hash, max_clicks, uses, not_before, fallback_url, rules, variants, ARRAY(SELECT tag FROM url_tags WHERE url_tags.short = urls.short ORDER BY position) AS tags`
+)
+
+// rowScanner общий интерфейс sql.Row и sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanURL читает ссылку из полей urlFields, за которыми идут колонки extra.
+// types нужен для чтения массивов, database/sql их не поддерживает
+func scanURL(types *pgtype.Map, row rowScanner, url *domain.URL, extra ...any) error {
+	var deleted sql.NullBool
+	var deletedAt, expiresAt, createdAt, updatedAt, notBefore sql.NullTime
+	var folder sql.NullString
+	var rules, variants []byte
+	dest := []any{&url.Short, &url.Long, &url.User, &deleted, &deletedAt, &expiresAt, &createdAt, &updatedAt,
+		&url.Title, &folder, &url.Preview, &url.Password, &url.MaxClicks, &url.Uses, &notBefore, &url.Fallback, &rules,
+		&variants, types.SQLScanner(&url.Tags)}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	url.Deleted = deleted.Bool
+	url.DeletedAt = deletedAt.Time
+	url.ExpiresAt = expiresAt.Time
+	url.CreatedAt = createdAt.Time
+	url.UpdatedAt = updatedAt.Time
+	url.NotBefore = notBefore.Time
+	var err error
+	if url.Rules, err = unmarshalList[domain.RedirectRule](rules); err != nil {
+		return err
+	}
+	if url.Variants, err = unmarshalList[domain.Variant](variants); err != nil {
+		return err
+	}
+	url.Folder = folder.String
+	if url.CreatedAt.IsZero() {
+		url.CreatedAt = time.Time{} // COALESCE в ListURLs дает нулевой момент с часовым поясом
+	}
+	if len(url.Tags) == 0 {
+		url.Tags = nil
+	}
+	return nil
+}
+
+// nullString переводит пустую строку в NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTime переводит нулевое время в NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// isShortViolation проверяет, что ошибка - нарушение первичного ключа short, а не уникальности long
+func isShortViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation && pgErr.ConstraintName == shortConstraint
+}
+
 // GetUsersCount возвращает количество пользователей
 func (pgStorage *pgStorage) GetUsersCount(ctx context.Context) (int, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -2,149 +2,238 @@
 package storage
 
 import (
-	"bufio"
-	"bytes"
 	"context"
-	"encoding/gob"
-	"fmt"
-	"io"
-	"os"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/Spear5030/yapshrtnr/internal/domain"
 )
 
-type link struct {
-	User  string
-	Short string
-	Long  string
+// defaultShards количество шардов хранилища в памяти по умолчанию
+const defaultShards = 32
+
+// urlShard часть ссылок хранилища в памяти. Шард выбирается по короткому идентификатору
+type urlShard struct {
+	mu        sync.RWMutex
+	links     map[string]domain.URL        // short -> ссылка
+	clicks    map[string][]domain.Click    // short -> переходы
+	revisions map[string][]domain.Revision // short -> история изменений
 }
 
-type storage struct {
-	URLs    map[string]string
-	Users   map[string][]string
-	Deleted map[string]string
+// userShard часть списков ссылок пользователей. Шард выбирается по пользователю
+type userShard struct {
+	mu    sync.RWMutex
+	users map[string][]string   // user -> shorts
+	index map[string]*userIndex // user -> ключи ссылок для ListURLs
 }
 
-type fileStorage struct {
-	filename string
-	storage
+// storage хранилище в памяти, разбитое на шарды с собственными RWMutex.
+// Запросы к разным шардам не блокируют друг друга
+type storage struct {
+	urlShards  []*urlShard
+	userShards []*userShard
+	jobs       *jobStore
+	labels     *labelStore
 }
 
 // NewMemoryStorage возвращает хранилище в памяти
 func NewMemoryStorage() *storage {
-	return &storage{
-		make(map[string]string),
-		make(map[string][]string),
-		make(map[string]string),
-	}
+	return NewShardedStorage(defaultShards)
 }
 
-// NewFileStorage возвращает файловое хранилище
-func NewFileStorage(filename string) (*fileStorage, error) {
-	file, err := os.OpenFile(filename, os.O_RDONLY|os.O_APPEND|os.O_CREATE, 0777)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-	rd := bufio.NewReader(file)
-	var buffer bytes.Buffer
-	var url link
-	urls := make(map[string]string)
-	users := make(map[string]string)
-	for {
-		b, err := rd.ReadBytes(13) // "\n"
-		if err != nil {
-			if err == io.EOF {
-				break
-			} else {
-				fmt.Println(err)
-				break
-			}
+// NewShardedStorage возвращает хранилище в памяти с заданным количеством шардов
+func NewShardedStorage(shards int) *storage {
+	if shards < 1 {
+		shards = 1
+	}
+	mStorage := &storage{
+		urlShards:  make([]*urlShard, shards),
+		userShards: make([]*userShard, shards),
+		jobs:       newJobStore(),
+		labels:     newLabelStore(),
+	}
+	for i := 0; i < shards; i++ {
+		mStorage.urlShards[i] = &urlShard{
+			links:     make(map[string]domain.URL),
+			clicks:    make(map[string][]domain.Click),
+			revisions: make(map[string][]domain.Revision),
 		}
-		buffer.Write(b)
-		gob.NewDecoder(&buffer).Decode(&url)
-		urls[url.Short] = url.Long
-		users[url.User] = url.User
+		mStorage.userShards[i] = &userShard{
+			users: make(map[string][]string),
+			index: make(map[string]*userIndex),
+		}
+	}
+	return mStorage
+}
+
+func shardIndex(key string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(shards))
+}
+
+func (mStorage *storage) urlShard(short string) *urlShard {
+	return mStorage.urlShards[shardIndex(short, len(mStorage.urlShards))]
+}
 
-		buffer.Reset()
+func (mStorage *storage) userShard(user string) *userShard {
+	return mStorage.userShards[shardIndex(user, len(mStorage.userShards))]
+}
+
+// SetURL записывает ссылку в map памяти. Если short занят - возвращает domain.ErrShortExists
+func (mStorage *storage) SetURL(ctx context.Context, url domain.URL) error {
+	if url.CreatedAt.IsZero() {
+		url.CreatedAt = time.Now().UTC()
 	}
-	storage := NewMemoryStorage()
-	return &fileStorage{
-		filename: filename,
-		storage:  *storage,
-	}, nil
+	if url.UpdatedAt.IsZero() {
+		url.UpdatedAt = url.CreatedAt
+	}
+	return mStorage.setURL(url)
 }
 
-// SetURL записывает связь short и long в map памяти.
-func (mStorage *storage) SetURL(ctx context.Context, user, short, long string) error {
-	mStorage.URLs[short] = long
-	mStorage.Users[user] = append(mStorage.Users[user], short)
+// setURL записывает ссылку как есть, без отметки времени создания. Метки и папка ссылки регистрируются у пользователя
+func (mStorage *storage) setURL(url domain.URL) error {
+	us := mStorage.urlShard(url.Short)
+	us.mu.Lock()
+	if _, ok := us.links[url.Short]; ok {
+		us.mu.Unlock()
+		return domain.NewShortExistsError(url.Short)
+	}
+	us.links[url.Short] = url
+	us.mu.Unlock()
+	mStorage.labels.register(url.User, url.Tags, url.Folder, url.CreatedAt)
+
+	uss := mStorage.userShard(url.User)
+	uss.mu.Lock()
+	uss.users[url.User] = append(uss.users[url.User], url.Short)
+	uss.indexOf(url.User).add(domain.ListCursor{Short: url.Short, CreatedAt: url.CreatedAt})
+	uss.mu.Unlock()
 	return nil
 }
 
-// GetURL возвращает полный URL из хранилища памяти.
-func (mStorage *storage) GetURL(ctx context.Context, short string) (string, bool) {
-	if _, ok := mStorage.Deleted[short]; ok {
-		return "", true
+// indexOf возвращает индекс ссылок пользователя, создавая пустой. Вызывается под блокировкой шарда на запись
+func (uss *userShard) indexOf(user string) *userIndex {
+	idx, ok := uss.index[user]
+	if !ok {
+		idx = &userIndex{}
+		uss.index[user] = idx
 	}
+	return idx
+}
 
-	if v, ok := mStorage.URLs[short]; ok {
-		return v, false
+// GetURL возвращает ссылку из хранилища памяти. Если ссылки нет - domain.ErrNotFound
+func (mStorage *storage) GetURL(ctx context.Context, short string) (domain.URL, error) {
+	us := mStorage.urlShard(short)
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+	if url, ok := us.links[short]; ok {
+		return url, nil
 	}
-	return "", false
+	return domain.URL{}, domain.ErrNotFound
 }
 
-// GetURLsByUser возвращает список URL созданных определенным пользователем из хранилища памяти.
+// userShorts возвращает копию списка коротких идентификаторов пользователя
+func (mStorage *storage) userShorts(user string) []string {
+	uss := mStorage.userShard(user)
+	uss.mu.RLock()
+	defer uss.mu.RUnlock()
+	return append([]string(nil), uss.users[user]...)
+}
+
+// GetURLsByUser возвращает список URL созданных определенным пользователем из хранилища памяти. Удаленные и истекшие URL не возвращаются
 func (mStorage *storage) GetURLsByUser(ctx context.Context, user string) (urls map[string]string) {
 	urls = make(map[string]string)
-	if shorts, ok := mStorage.Users[user]; ok {
-		for _, short := range shorts {
-			urls[short] = mStorage.URLs[short]
+	now := time.Now()
+	for _, short := range mStorage.userShorts(user) {
+		us := mStorage.urlShard(short)
+		us.mu.RLock()
+		if url, ok := us.links[short]; ok && !url.Deleted && !url.Expired(now) {
+			urls[short] = url.Long
 		}
+		us.mu.RUnlock()
 	}
 	return
 }
 
-// SetURL записывает связь short и long в map памяти. После сбрасывает изменения в файл
-func (fStorage *fileStorage) SetURL(ctx context.Context, user, short, long string) error {
-	fStorage.URLs[short] = long
-	fStorage.Users[user] = append(fStorage.Users[user], short)
-	file, err := os.OpenFile(fStorage.filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0777)
-	if err != nil {
-		panic(err)
+// ListURLs возвращает страницу ссылок пользователя с количеством переходов, отфильтрованных и упорядоченных по query.
+// Для сортировки по короткому идентификатору и времени создания ссылки просматриваются по индексу пользователя
+// от курсора до заполнения страницы
+func (mStorage *storage) ListURLs(ctx context.Context, user string, query domain.ListQuery) ([]domain.LinkInfo, error) {
+	if query.Sort == domain.SortClicks {
+		return mStorage.listByClicks(user, query), nil
 	}
-	defer file.Close()
-	var buffer bytes.Buffer
-	linkToEncode := link{
-		User:  user,
-		Short: short,
-		Long:  long,
+	uss := mStorage.userShard(user)
+	uss.mu.RLock()
+	for idx := uss.index[user]; idx != nil && !idx.sorted; idx = uss.index[user] {
+		// порядок восстанавливается под блокировкой на запись, выборка идет под блокировкой на чтение
+		uss.mu.RUnlock()
+		uss.mu.Lock()
+		if idx = uss.index[user]; idx != nil && !idx.sorted {
+			idx.sort()
+		}
+		uss.mu.Unlock()
+		uss.mu.RLock()
 	}
-	if err := gob.NewEncoder(&buffer).Encode(linkToEncode); err != nil {
-		panic(err)
+	defer uss.mu.RUnlock()
+	idx := uss.index[user]
+	if idx == nil {
+		return nil, nil
 	}
-	file.Write(append(buffer.Bytes(), 13))
-	return nil
-}
-
-// GetURLsByUser возвращает список URL созданных определенным пользователем из хранилища.
-func (fStorage *fileStorage) GetURLsByUser(ctx context.Context, user string) (urls map[string]string) {
-	urls = make(map[string]string)
-	if shorts, ok := fStorage.Users[user]; ok {
-
-		for _, short := range shorts {
-			urls[short] = fStorage.URLs[short]
+	keys := idx.keys(query.Sort)
+	step := 1
+	if query.Desc {
+		step = -1
+	}
+	now := time.Now()
+	var links []domain.LinkInfo
+	for i := pageStart(keys, query); i >= 0 && i < len(keys) && len(links) < query.Limit; i += step {
+		short := keys[i].Short
+		us := mStorage.urlShard(short)
+		us.mu.RLock()
+		url, ok := us.links[short]
+		clicks := len(us.clicks[short])
+		us.mu.RUnlock()
+		if ok && matchLink(url, query, now) {
+			links = append(links, domain.LinkInfo{URL: url, Clicks: clicks})
 		}
 	}
-	return
+	return links, nil
 }
 
-// GetURL возвращает полный URL.
-func (fStorage *fileStorage) GetURL(ctx context.Context, short string) (string, bool) {
-	if v, ok := fStorage.URLs[short]; ok {
-		return v, false
+// listByClicks возвращает страницу ссылок пользователя, упорядоченных по числу переходов. Число переходов
+// меняется с каждым переходом, поэтому индекса нет и ссылки пользователя перебираются целиком
+func (mStorage *storage) listByClicks(user string, query domain.ListQuery) []domain.LinkInfo {
+	now := time.Now()
+	var links []domain.LinkInfo
+	for _, short := range mStorage.userShorts(user) {
+		us := mStorage.urlShard(short)
+		us.mu.RLock()
+		url, ok := us.links[short]
+		clicks := len(us.clicks[short])
+		us.mu.RUnlock()
+		if ok && matchLink(url, query, now) {
+			links = append(links, domain.LinkInfo{URL: url, Clicks: clicks})
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return lessLink(links[i].Cursor(), links[j].Cursor(), query.Sort) != query.Desc
+	})
+	start := 0
+	if query.After.Short != "" {
+		start = sort.Search(len(links), func(i int) bool {
+			if query.Desc {
+				return lessLink(links[i].Cursor(), query.After, query.Sort)
+			}
+			return lessLink(query.After, links[i].Cursor(), query.Sort)
+		})
+	}
+	links = links[start:]
+	if len(links) > query.Limit {
+		links = links[:query.Limit]
 	}
-	return "", false
+	return links
 }
 
 // Ping не имплементировано для данного хранилища
@@ -152,61 +241,469 @@ func (mStorage *storage) Ping() error {
 	return nil
 }
 
-// SetBatchURLs пакетное сохранение ссылок в памяти
+// SetBatchURLs пакетное сохранение ссылок в памяти. При коллизии уже сохраненные ссылки пакета удаляются
 func (mStorage *storage) SetBatchURLs(ctx context.Context, urls []domain.URL) error {
-	for _, u := range urls {
-		mStorage.URLs[u.Short] = u.Long
-		mStorage.Users[u.User] = append(mStorage.Users[u.User], u.Short)
+	for i, u := range urls {
+		if err := mStorage.SetURL(ctx, u); err != nil {
+			for _, saved := range urls[:i] {
+				mStorage.removeURL(saved.User, saved.Short)
+			}
+			return err
+		}
 	}
 	return nil
 }
 
-// SetBatchURLs не имплементировано для данного хранилища
-func (fStorage *fileStorage) SetBatchURLs(ctx context.Context, urls []domain.URL) error {
-	return nil
+// removeURL убирает ссылку из хранилища полностью
+func (mStorage *storage) removeURL(user, short string) {
+	us := mStorage.urlShard(short)
+	us.mu.Lock()
+	delete(us.links, short)
+	delete(us.clicks, short)
+	delete(us.revisions, short)
+	us.mu.Unlock()
+
+	uss := mStorage.userShard(user)
+	uss.mu.Lock()
+	shorts := uss.users[user]
+	for i := len(shorts) - 1; i >= 0; i-- {
+		if shorts[i] == short {
+			shorts = append(shorts[:i], shorts[i+1:]...)
+			break
+		}
+	}
+	if len(shorts) == 0 {
+		delete(uss.users, user)
+		delete(uss.index, user)
+	} else {
+		uss.users[user] = shorts
+		uss.indexOf(user).remove(short)
+	}
+	uss.mu.Unlock()
 }
 
-// DeleteURLs пакетное удаление ссылок в памяти
-func (mStorage *storage) DeleteURLs(ctx context.Context, user string, shorts []string) {
+// exists проверяет, занят ли короткий идентификатор
+func (mStorage *storage) exists(short string) bool {
+	us := mStorage.urlShard(short)
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+	_, ok := us.links[short]
+	return ok
+}
+
+// DeleteURLs пакетное удаление ссылок в памяти. Удаляются только ссылки пользователя.
+// Удаление выполняется сразу, возвращается идентификатор уже завершенной задачи
+func (mStorage *storage) DeleteURLs(ctx context.Context, user string, shorts []string) (string, error) {
+	job := mStorage.deleteJob(newJobID(), user, shorts, time.Now().UTC())
+	return job.ID, nil
+}
+
+// deleteJob выполняет удаление и сохраняет завершенную задачу с результатами
+func (mStorage *storage) deleteJob(id, user string, shorts []string, at time.Time) domain.DeleteJob {
+	deleted, skipped := mStorage.deleteURLs(user, shorts, at)
+	job := domain.DeleteJob{
+		ID:        id,
+		User:      user,
+		Shorts:    shorts,
+		Status:    domain.JobDone,
+		Deleted:   deleted,
+		Skipped:   skipped,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+	mStorage.jobs.put(job)
+	return job
+}
+
+// deleteURLs помечает ссылки пользователя удаленными на момент at. Уже удаленные ссылки не меняются.
+// Возвращает ссылки пользователя и пропущенные - чужие и несуществующие
+func (mStorage *storage) deleteURLs(user string, shorts []string, at time.Time) (deleted, skipped []string) {
 	for _, short := range shorts {
-		for _, s := range mStorage.Users[user] {
-			if s == short {
-				mStorage.Deleted[short] = user
+		us := mStorage.urlShard(short)
+		us.mu.Lock()
+		url, ok := us.links[short]
+		switch {
+		case !ok || url.User != user:
+			skipped = append(skipped, short)
+		case !url.Deleted:
+			url.Deleted = true
+			url.DeletedAt = at
+			url.UpdatedAt = at
+			us.links[short] = url
+			fallthrough
+		default:
+			deleted = append(deleted, short)
+		}
+		us.mu.Unlock()
+	}
+	return deleted, skipped
+}
+
+// restorableURL возвращает удаленную ссылку пользователя, которую еще можно восстановить. Вызывается под us.mu.
+// Неудаленная ссылка возвращается как есть. Для несуществующих, чужих и удаленных раньше deletedAfter - domain.ErrNotFound
+func (us *urlShard) restorableURL(user, short string, deletedAfter time.Time) (domain.URL, error) {
+	url, ok := us.links[short]
+	if !ok || url.User != user || (url.Deleted && !url.Restorable(deletedAfter)) {
+		return domain.URL{}, domain.ErrNotFound
+	}
+	return url, nil
+}
+
+// restoreURL снимает пометку удаления на момент at. Вызывается под us.mu
+func (us *urlShard) restoreURL(short string, at time.Time) {
+	if url, ok := us.links[short]; ok {
+		url.Deleted = false
+		url.DeletedAt = time.Time{}
+		if !at.IsZero() {
+			url.UpdatedAt = at
+		}
+		us.links[short] = url
+	}
+}
+
+// RestoreURL восстанавливает удаленную ссылку пользователя, если она удалена не раньше deletedAfter.
+// Для несуществующих, чужих и удаленных раньше ссылок - domain.ErrNotFound
+func (mStorage *storage) RestoreURL(ctx context.Context, user, short string, deletedAfter time.Time) (domain.URL, error) {
+	us := mStorage.urlShard(short)
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	url, err := us.restorableURL(user, short, deletedAfter)
+	if err != nil {
+		return url, err
+	}
+	us.restoreURL(short, time.Now().UTC())
+	return us.links[short], nil
+}
+
+// purgeableURLs возвращает ссылки, удаленные раньше deletedBefore
+func (mStorage *storage) purgeableURLs(deletedBefore time.Time) []domain.URL {
+	var purgeable []domain.URL
+	for _, us := range mStorage.urlShards {
+		us.mu.RLock()
+		for _, url := range us.links {
+			if url.Deleted && url.DeletedAt.Before(deletedBefore) {
+				purgeable = append(purgeable, url)
 			}
 		}
+		us.mu.RUnlock()
 	}
+	return purgeable
 }
 
-// DeleteURLs не имплементировано для данного хранилища
-func (fStorage *fileStorage) DeleteURLs(ctx context.Context, user string, shorts []string) {
+// PurgeDeleted окончательно удаляет ссылки, удаленные раньше deletedBefore, вместе с переходами и историей.
+// Возвращает количество удаленных
+func (mStorage *storage) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	purgeable := mStorage.purgeableURLs(deletedBefore)
+	for _, url := range purgeable {
+		mStorage.removeURL(url.User, url.Short)
+	}
+	mStorage.jobs.purge(deletedBefore)
+	return len(purgeable), nil
 }
 
-// Shutdown не имплементировано для данного хранилища
-func (mStorage *storage) Shutdown() error {
-	return nil
+// revision готовит изменение полного URL ссылки. Вызывается под us.mu.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (us *urlShard) revision(user, short, long string) (domain.Revision, error) {
+	url, ok := us.links[short]
+	if !ok || url.Deleted || url.User != user {
+		return domain.Revision{}, domain.ErrNotFound
+	}
+	return domain.Revision{
+		Short:     short,
+		OldLong:   url.Long,
+		NewLong:   long,
+		User:      user,
+		ChangedAt: time.Now().UTC(),
+	}, nil
+}
+
+// applyRevision меняет полный URL ссылки и дописывает изменение в историю. Вызывается под us.mu
+func (us *urlShard) applyRevision(rev domain.Revision) {
+	url, ok := us.links[rev.Short]
+	if !ok {
+		return
+	}
+	url.Long = rev.NewLong
+	url.UpdatedAt = rev.ChangedAt
+	us.links[rev.Short] = url
+	us.revisions[rev.Short] = append(us.revisions[rev.Short], rev)
+}
+
+// UpdateURL меняет полный URL ссылки пользователя и сохраняет изменение в истории.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) UpdateURL(ctx context.Context, user, short, long string) (domain.URL, error) {
+	us := mStorage.urlShard(short)
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	rev, err := us.revision(user, short, long)
+	if err != nil {
+		return domain.URL{}, err
+	}
+	if rev.OldLong != rev.NewLong {
+		us.applyRevision(rev)
+	}
+	return us.links[short], nil
+}
+
+// GetURLRevisions возвращает историю изменений ссылки от старых к новым. Если ссылки нет - domain.ErrNotFound
+func (mStorage *storage) GetURLRevisions(ctx context.Context, short string) ([]domain.Revision, error) {
+	us := mStorage.urlShard(short)
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+	if _, ok := us.links[short]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	return append([]domain.Revision(nil), us.revisions[short]...), nil
+}
+
+// updateURL меняет ссылку через update и отмечает изменение на момент at. Вызывается под us.mu.
+// Если ссылки нет - false
+func (us *urlShard) updateURL(short string, at time.Time, update func(url *domain.URL)) (domain.URL, bool) {
+	url, ok := us.links[short]
+	if !ok {
+		return domain.URL{}, false
+	}
+	update(&url)
+	url.UpdatedAt = at
+	us.links[short] = url
+	return url, true
+}
+
+// updateOwnedURL меняет неудаленную ссылку пользователя через update. Проверка и изменение идут под одной
+// блокировкой шарда, поэтому параллельное удаление не может вклиниться между ними.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) updateOwnedURL(user, short string, update func(url *domain.URL)) (domain.URL, error) {
+	us := mStorage.urlShard(short)
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if url, ok := us.links[short]; !ok || url.Deleted || url.User != user {
+		return domain.URL{}, domain.ErrNotFound
+	}
+	url, _ := us.updateURL(short, time.Now().UTC(), update)
+	return url, nil
+}
+
+// updateURLAt меняет ссылку через update на момент at, если она есть. Используется при проигрывании журнала
+func (mStorage *storage) updateURLAt(short string, at time.Time, update func(url *domain.URL)) {
+	us := mStorage.urlShard(short)
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	us.updateURL(short, at, update)
+}
+
+// SetURLPreview включает или выключает предпросмотр ссылки пользователя.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) SetURLPreview(ctx context.Context, user, short string, preview bool) (domain.URL, error) {
+	return mStorage.updateOwnedURL(user, short, func(url *domain.URL) { url.Preview = preview })
+}
+
+// setURLPreview меняет флаг предпросмотра ссылки на момент at
+func (mStorage *storage) setURLPreview(short string, preview bool, at time.Time) {
+	mStorage.updateURLAt(short, at, func(url *domain.URL) { url.Preview = preview })
+}
+
+// SetURLPassword задает bcrypt-хэш пароля ссылки пользователя, пустой хэш снимает пароль.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) SetURLPassword(ctx context.Context, user, short, hash string) (domain.URL, error) {
+	return mStorage.updateOwnedURL(user, short, func(url *domain.URL) { url.Password = hash })
+}
+
+// setURLPassword меняет хэш пароля ссылки на момент at
+func (mStorage *storage) setURLPassword(short, hash string, at time.Time) {
+	mStorage.updateURLAt(short, at, func(url *domain.URL) { url.Password = hash })
+}
+
+// SetURLRules заменяет правила перенаправления ссылки пользователя, пустой список убирает правила.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) SetURLRules(ctx context.Context, user, short string, rules []domain.RedirectRule) (domain.URL, error) {
+	return mStorage.updateOwnedURL(user, short, func(url *domain.URL) { url.Rules = rules })
+}
+
+// setURLRules меняет правила перенаправления ссылки на момент at
+func (mStorage *storage) setURLRules(short string, rules []domain.RedirectRule, at time.Time) {
+	mStorage.updateURLAt(short, at, func(url *domain.URL) { url.Rules = rules })
+}
+
+// SetURLVariants заменяет варианты A/B-разделения ссылки пользователя, пустой список убирает разделение.
+// Для несуществующих, удаленных и чужих ссылок - domain.ErrNotFound
+func (mStorage *storage) SetURLVariants(ctx context.Context, user, short string, variants []domain.Variant) (domain.URL, error) {
+	return mStorage.updateOwnedURL(user, short, func(url *domain.URL) { url.Variants = variants })
+}
+
+// setURLVariants меняет варианты A/B-разделения ссылки на момент at
+func (mStorage *storage) setURLVariants(short string, variants []domain.Variant, at time.Time) {
+	mStorage.updateURLAt(short, at, func(url *domain.URL) { url.Variants = variants })
+}
+
+// UseURL учитывает переход по ссылке с ограничением переходов. Проверка и увеличение счетчика идут под блокировкой
+// шарда, поэтому последний разрешенный переход достается одному запросу. Если переходы закончились -
+// domain.ErrClicksExhausted, если ссылки нет - domain.ErrNotFound. Ссылки без ограничения не меняются
+func (mStorage *storage) UseURL(ctx context.Context, short string) (domain.URL, error) {
+	us := mStorage.urlShard(short)
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	url, ok := us.links[short]
+	if !ok {
+		return domain.URL{}, domain.ErrNotFound
+	}
+	if url.MaxClicks == 0 {
+		return url, nil
+	}
+	if url.Exhausted() {
+		return url, domain.ErrClicksExhausted
+	}
+	url.Uses++
+	us.links[short] = url
+	return url, nil
+}
+
+// expiredURLs возвращает ссылки, срок действия которых истек на момент now
+func (mStorage *storage) expiredURLs(now time.Time) []domain.URL {
+	var expired []domain.URL
+	for _, us := range mStorage.urlShards {
+		us.mu.RLock()
+		for _, url := range us.links {
+			if url.Expired(now) {
+				expired = append(expired, url)
+			}
+		}
+		us.mu.RUnlock()
+	}
+	return expired
+}
+
+// DeleteExpired удаляет из памяти ссылки с истекшим сроком действия. Возвращает количество удаленных
+func (mStorage *storage) DeleteExpired(ctx context.Context) (int, error) {
+	expired := mStorage.expiredURLs(time.Now())
+	for _, url := range expired {
+		mStorage.removeURL(url.User, url.Short)
+	}
+	return len(expired), nil
+}
+
+// RecordClick сохраняет переход по ссылке. В памяти запись дешевая, поэтому без буферизации
+func (mStorage *storage) RecordClick(ctx context.Context, click domain.Click) {
+	mStorage.addClicks([]domain.Click{click})
+}
+
+// addClicks сохраняет переходы. Переходы по несуществующим ссылкам отбрасываются
+func (mStorage *storage) addClicks(clicks []domain.Click) {
+	for _, click := range clicks {
+		us := mStorage.urlShard(click.Short)
+		us.mu.Lock()
+		if _, ok := us.links[click.Short]; ok {
+			us.clicks[click.Short] = append(us.clicks[click.Short], click)
+		}
+		us.mu.Unlock()
+	}
+}
+
+// GetClickStats возвращает статистику переходов по ссылке. Если ссылки нет - domain.ErrNotFound
+func (mStorage *storage) GetClickStats(ctx context.Context, short string) (domain.ClickStats, error) {
+	us := mStorage.urlShard(short)
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+	if _, ok := us.links[short]; !ok {
+		return domain.ClickStats{}, domain.ErrNotFound
+	}
+	return clickStats(us.clicks[short]), nil
 }
 
 // Shutdown не имплементировано для данного хранилища
-func (fStorage *fileStorage) Shutdown() error {
+func (mStorage *storage) Shutdown() error {
 	return nil
 }
 
 // GetUsersCount возвращает количество пользователей
 func (mStorage *storage) GetUsersCount(ctx context.Context) (int, error) {
-	return len(mStorage.Users), nil
-}
-
-// GetUsersCount возвращает количество пользователей
-func (fStorage *fileStorage) GetUsersCount(ctx context.Context) (int, error) {
-	return len(fStorage.Users), nil
+	count := 0
+	for _, uss := range mStorage.userShards {
+		uss.mu.RLock()
+		count += len(uss.users)
+		uss.mu.RUnlock()
+	}
+	return count, nil
 }
 
 // GetUrlsCount возвращает количество ссылок
 func (mStorage *storage) GetUrlsCount(ctx context.Context) (int, error) {
-	return len(mStorage.URLs), nil
+	count := 0
+	for _, us := range mStorage.urlShards {
+		us.mu.RLock()
+		count += len(us.links)
+		us.mu.RUnlock()
+	}
+	return count, nil
 }
 
-// GetUrlsCount возвращает количество ссылок
-func (fStorage *fileStorage) GetUrlsCount(ctx context.Context) (int, error) {
-	return len(fStorage.URLs), nil
+// export возвращает копию содержимого всех шардов для снимка
+func (mStorage *storage) export() snapshotData {
+	data := snapshotData{
+		Links:     make(map[string]domain.URL),
+		Users:     make(map[string][]string),
+		Clicks:    make(map[string][]domain.Click),
+		Revisions: make(map[string][]domain.Revision),
+		Jobs:      mStorage.jobs.export(),
+	}
+	data.Tags, data.Folders = mStorage.labels.export()
+	for _, us := range mStorage.urlShards {
+		us.mu.RLock()
+		for short, url := range us.links {
+			data.Links[short] = url
+		}
+		for short, clicks := range us.clicks {
+			data.Clicks[short] = append([]domain.Click(nil), clicks...)
+		}
+		for short, revisions := range us.revisions {
+			data.Revisions[short] = append([]domain.Revision(nil), revisions...)
+		}
+		us.mu.RUnlock()
+	}
+	for _, uss := range mStorage.userShards {
+		uss.mu.RLock()
+		for user, shorts := range uss.users {
+			data.Users[user] = append([]string(nil), shorts...)
+		}
+		uss.mu.RUnlock()
+	}
+	return data
+}
+
+// restore раскладывает содержимое снимка по шардам
+func (mStorage *storage) restore(data snapshotData) {
+	mStorage.labels.restore(data.Tags, data.Folders)
+	links := data.links()
+	for short, url := range links {
+		us := mStorage.urlShard(short)
+		us.mu.Lock()
+		us.links[short] = url
+		us.mu.Unlock()
+		// в снимках до появления меток их список собирается по ссылкам
+		mStorage.labels.register(url.User, url.Tags, url.Folder, url.CreatedAt)
+	}
+	for short, clicks := range data.Clicks {
+		us := mStorage.urlShard(short)
+		us.mu.Lock()
+		us.clicks[short] = append(us.clicks[short], clicks...)
+		us.mu.Unlock()
+	}
+	for short, revisions := range data.Revisions {
+		us := mStorage.urlShard(short)
+		us.mu.Lock()
+		us.revisions[short] = append(us.revisions[short], revisions...)
+		us.mu.Unlock()
+	}
+	for _, job := range data.Jobs {
+		mStorage.jobs.put(job)
+	}
+	for user, shorts := range data.Users {
+		uss := mStorage.userShard(user)
+		uss.mu.Lock()
+		uss.users[user] = append(uss.users[user], shorts...)
+		idx := uss.indexOf(user)
+		for _, short := range shorts {
+			idx.add(domain.ListCursor{Short: short, CreatedAt: links[short].CreatedAt})
+		}
+		uss.mu.Unlock()
+	}
 }
@@ -0,0 +1,475 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+func TestShardedStorage(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short003", Long: "http://c.ru", User: "user2"}))
+
+	s.DeleteURLs(ctx, "user2", []string{"short001"}) // чужая ссылка не удаляется
+	s.DeleteURLs(ctx, "user1", []string{"short002"})
+	url, err := s.GetURL(ctx, "short001")
+	require.NoError(t, err)
+	require.Equal(t, "http://a.ru", url.Long)
+	require.False(t, url.Deleted)
+	url, _ = s.GetURL(ctx, "short002")
+	require.True(t, url.Deleted)
+
+	require.Equal(t, map[string]string{"short001": "http://a.ru"}, s.GetURLsByUser(ctx, "user1"))
+	users, _ := s.GetUsersCount(ctx)
+	require.Equal(t, 2, users)
+	urls, _ := s.GetUrlsCount(ctx)
+	require.Equal(t, 3, urls)
+
+	restored := NewShardedStorage(7)
+	restored.restore(s.export())
+	require.Equal(t, s.export(), restored.export())
+}
+
+// TestShardedStorage_Concurrent имеет смысл запускать с -race
+func TestShardedStorage_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	const workers, perWorker = 16, 200
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			user := fmt.Sprintf("user%d", w%4)
+			for i := 0; i < perWorker; i++ {
+				short := fmt.Sprintf("s%d-%d", w, i)
+				assert.NoError(t, s.SetURL(ctx, domain.URL{Short: short, Long: "http://" + short + ".ru", User: user}))
+				s.GetURL(ctx, short)
+				s.GetURLsByUser(ctx, user)
+				if i%10 == 0 {
+					s.DeleteURLs(ctx, user, []string{short})
+				}
+				_, _ = s.GetUrlsCount(ctx)
+			}
+		}(w)
+	}
+	wg.Wait()
+	urls, _ := s.GetUrlsCount(ctx)
+	require.Equal(t, workers*perWorker, urls)
+	users, _ := s.GetUsersCount(ctx)
+	require.Equal(t, 4, users)
+}
+
+func TestShardedStorage_Collision(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.ErrorIs(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://b.ru", User: "user2"}), domain.ErrShortExists)
+	url, _ := s.GetURL(ctx, "short001")
+	require.Equal(t, "http://a.ru", url.Long)
+
+	err := s.SetBatchURLs(ctx, []domain.URL{
+		{Short: "short002", Long: "http://c.ru", User: "user2"},
+		{Short: "short001", Long: "http://d.ru", User: "user2"},
+	})
+	require.ErrorIs(t, err, domain.ErrShortExists)
+	_, err = s.GetURL(ctx, "short002")
+	require.ErrorIs(t, err, domain.ErrNotFound, "пакет откатывается целиком")
+	users, _ := s.GetUsersCount(ctx)
+	require.Equal(t, 1, users)
+}
+
+func TestShardedStorage_DeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1",
+		ExpiresAt: time.Now().Add(-time.Second)}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short003", Long: "http://c.ru", User: "user2",
+		ExpiresAt: time.Now().Add(time.Hour)}))
+
+	url, err := s.GetURL(ctx, "short002")
+	require.NoError(t, err)
+	require.True(t, url.Expired(time.Now()))
+	require.Equal(t, map[string]string{"short001": "http://a.ru"}, s.GetURLsByUser(ctx, "user1"))
+
+	count, err := s.DeleteExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	_, err = s.GetURL(ctx, "short002")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	urls, _ := s.GetUrlsCount(ctx)
+	require.Equal(t, 2, urls)
+}
+
+func TestShardedStorage_Clicks(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	day := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	s.RecordClick(ctx, domain.Click{Short: "short001", Time: day.Add(23 * time.Hour), IP: "10.0.0.1", Referrer: "http://b.ru"})
+	s.RecordClick(ctx, domain.Click{Short: "short001", Time: day.Add(time.Hour), IP: "10.0.0.1", Referrer: "http://a.ru"})
+	s.RecordClick(ctx, domain.Click{Short: "short001", Time: day.Add(25 * time.Hour), IP: "10.0.0.2", Referrer: "http://b.ru"})
+	s.RecordClick(ctx, domain.Click{Short: "unknown", Time: day})
+
+	stats, err := s.GetClickStats(ctx, "short001")
+	require.NoError(t, err)
+	require.Equal(t, domain.ClickStats{Total: 3, Unique: 2, TopReferrers: []domain.ReferrerClicks{
+		{Referrer: "http://b.ru", Clicks: 2},
+		{Referrer: "http://a.ru", Clicks: 1},
+	}, Daily: []domain.DailyClicks{
+		{Date: day, Clicks: 2},
+		{Date: day.Add(24 * time.Hour), Clicks: 1},
+	}}, stats)
+	_, err = s.GetClickStats(ctx, "unknown")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestShardedStorage_RestoreAndPurge(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1"}))
+	s.RecordClick(ctx, domain.Click{Short: "short002", Time: time.Now()})
+	s.DeleteURLs(ctx, "user1", []string{"short001", "short002"})
+
+	_, err := s.RestoreURL(ctx, "user2", "short001", time.Now().Add(-time.Hour))
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	url, err := s.RestoreURL(ctx, "user1", "short001", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.False(t, url.Deleted)
+	require.True(t, url.DeletedAt.IsZero())
+	_, err = s.RestoreURL(ctx, "user1", "short002", time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, domain.ErrNotFound, "срок восстановления прошел")
+
+	count, err := s.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	_, err = s.GetURL(ctx, "short002")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.Empty(t, s.export().Clicks)
+	require.Equal(t, map[string]string{"short001": "http://a.ru"}, s.GetURLsByUser(ctx, "user1"))
+}
+
+func TestShardedStorage_DeleteJob(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1"}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user2"}))
+
+	id, err := s.DeleteURLs(ctx, "user1", []string{"short001", "short002", "short003"})
+	require.NoError(t, err)
+	job, err := s.GetDeleteJob(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, domain.JobDone, job.Status)
+	require.Equal(t, "user1", job.User)
+	require.Equal(t, []string{"short001"}, job.Deleted)
+	require.Equal(t, []string{"short002", "short003"}, job.Skipped)
+
+	// повторное удаление своей ссылки не считается пропуском
+	id, _ = s.DeleteURLs(ctx, "user1", []string{"short001"})
+	job, _ = s.GetDeleteJob(ctx, id)
+	require.Equal(t, []string{"short001"}, job.Deleted)
+	require.Empty(t, job.Skipped)
+
+	_, err = s.GetDeleteJob(ctx, "unknown")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = s.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = s.GetDeleteJob(ctx, id)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestShardedStorage_ListURLs(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	for i := 5; i >= 1; i-- {
+		require.NoError(t, s.SetURL(ctx, domain.URL{Short: fmt.Sprintf("short%03d", i), Long: fmt.Sprintf("http://%d.ru", i), User: "user1"}))
+	}
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short000", Long: "http://0.ru", User: "user2"}))
+	s.DeleteURLs(ctx, "user1", []string{"short002"})
+	s.RecordClick(ctx, domain.Click{Short: "short003"})
+	s.RecordClick(ctx, domain.Click{Short: "short003"})
+
+	page, err := s.ListURLs(ctx, "user1", domain.ListQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, "short001", page[0].Short)
+	require.False(t, page[0].CreatedAt.IsZero())
+	require.True(t, page[1].Deleted, "удаленные ссылки тоже выгружаются")
+
+	page, err = s.ListURLs(ctx, "user1", domain.ListQuery{After: page[1].Cursor(), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, "short003", page[0].Short)
+	require.Equal(t, 2, page[0].Clicks)
+
+	page, err = s.ListURLs(ctx, "user1", domain.ListQuery{After: domain.ListCursor{Short: "short004"}, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "short005", page[0].Short)
+}
+
+func TestShardedStorage_ListURLsQuery(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	now := time.Now().UTC()
+	links := []domain.URL{
+		{Short: "short001", Long: "https://go.dev/doc", CreatedAt: now.Add(-3 * time.Hour)},
+		{Short: "short002", Long: "https://blog.go.dev/intro", CreatedAt: now.Add(-1 * time.Hour)},
+		{Short: "short003", Long: "https://ya.ru/search", CreatedAt: now.Add(-2 * time.Hour)},
+		{Short: "short004", Long: "https://go.dev/play", CreatedAt: now, ExpiresAt: now.Add(-time.Minute)},
+	}
+	for _, url := range links {
+		url.User = "user1"
+		require.NoError(t, s.SetURL(ctx, url))
+	}
+	s.DeleteURLs(ctx, "user1", []string{"short003"})
+	for i := 0; i < 3; i++ {
+		s.RecordClick(ctx, domain.Click{Short: "short002"})
+	}
+	s.RecordClick(ctx, domain.Click{Short: "short001"})
+
+	shorts := func(query domain.ListQuery) []string {
+		page, err := s.ListURLs(ctx, "user1", query)
+		require.NoError(t, err)
+		res := make([]string, 0, len(page))
+		for _, link := range page {
+			res = append(res, link.Short)
+		}
+		return res
+	}
+	require.Equal(t, []string{"short001", "short003", "short002", "short004"}, shorts(domain.ListQuery{Limit: 10, Sort: domain.SortCreated}))
+	require.Equal(t, []string{"short002", "short001", "short004", "short003"}, shorts(domain.ListQuery{Limit: 10, Sort: domain.SortClicks, Desc: true}))
+	require.Equal(t, []string{"short001", "short002"}, shorts(domain.ListQuery{Limit: 10, Status: domain.StatusActive}))
+	require.Equal(t, []string{"short003"}, shorts(domain.ListQuery{Limit: 10, Status: domain.StatusDeleted}))
+	require.Equal(t, []string{"short001", "short002", "short004"}, shorts(domain.ListQuery{Limit: 10, Domain: "GO.dev"}))
+	require.Equal(t, []string{"short004"}, shorts(domain.ListQuery{Limit: 10, Search: "PLAY"}))
+
+	// курсор по убыванию переходов продолжает выборку после последней ссылки страницы
+	page, err := s.ListURLs(ctx, "user1", domain.ListQuery{Limit: 2, Sort: domain.SortClicks, Desc: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"short004", "short003"},
+		shorts(domain.ListQuery{After: page[1].Cursor(), Limit: 2, Sort: domain.SortClicks, Desc: true}))
+}
+
+func TestShardedStorage_ListURLsPages(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	now := time.Now().UTC()
+	var all []domain.ListCursor
+	add := func(i int) {
+		// время создания не совпадает с порядком коротких идентификаторов, у части ссылок оно одинаковое
+		url := domain.URL{Short: fmt.Sprintf("short%03d", i), Long: "http://a.ru", User: "user1",
+			CreatedAt: now.Add(time.Duration(i*7%11) * time.Minute)}
+		require.NoError(t, s.SetURL(ctx, url))
+		all = append(all, domain.ListCursor{Short: url.Short, CreatedAt: url.CreatedAt})
+	}
+	for i := 0; i < 40; i++ {
+		add(i)
+	}
+	s.removeURL("user1", "short010")
+	all = append(all[:10], all[11:]...)
+
+	for _, sortBy := range []domain.ListSort{domain.SortShort, domain.SortCreated} {
+		for _, desc := range []bool{false, true} {
+			want := append([]domain.ListCursor(nil), all...)
+			sort.Slice(want, func(i, j int) bool { return lessLink(want[i], want[j], sortBy) != desc })
+			var got []domain.ListCursor
+			query := domain.ListQuery{Limit: 7, Sort: sortBy, Desc: desc}
+			for {
+				page, err := s.ListURLs(ctx, "user1", query)
+				require.NoError(t, err)
+				for _, link := range page {
+					got = append(got, domain.ListCursor{Short: link.Short, CreatedAt: link.CreatedAt})
+				}
+				if len(page) < query.Limit {
+					break
+				}
+				query.After = page[len(page)-1].Cursor()
+			}
+			require.Equal(t, want, got, "sort %s desc %v", sortBy, desc)
+		}
+	}
+
+	// ссылки, добавленные между страницами, попадают в выборку на своих местах
+	page, err := s.ListURLs(ctx, "user1", domain.ListQuery{Limit: 1, Sort: domain.SortShort, Desc: true})
+	require.NoError(t, err)
+	require.Equal(t, "short039", page[0].Short)
+	add(40)
+	page, err = s.ListURLs(ctx, "user1", domain.ListQuery{Limit: 1, Sort: domain.SortShort, Desc: true})
+	require.NoError(t, err)
+	require.Equal(t, "short040", page[0].Short)
+	page, err = s.ListURLs(ctx, "user2", domain.ListQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Empty(t, page)
+}
+
+func TestShardedStorage_Labels(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short001", Long: "http://a.ru", User: "user1", Tags: []string{"go", "docs"}}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short002", Long: "http://b.ru", User: "user1", Tags: []string{"go"}}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "short003", Long: "http://c.ru", User: "user2"}))
+
+	_, err := s.CreateTag(ctx, "user1", "go")
+	require.ErrorIs(t, err, domain.ErrTagExists)
+	_, err = s.CreateTag(ctx, "user1", "empty")
+	require.NoError(t, err)
+	tags, err := s.ListTags(ctx, "user1")
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+	require.Equal(t, "docs", tags[0].Name)
+	require.Equal(t, 0, tags[1].Links)
+	require.Equal(t, domain.Tag{Name: "go", Links: 2, CreatedAt: tags[2].CreatedAt}, tags[2])
+
+	tag, err := s.RenameTag(ctx, "user1", "go", "golang")
+	require.NoError(t, err)
+	require.Equal(t, 2, tag.Links)
+	_, err = s.RenameTag(ctx, "user1", "golang", "docs")
+	require.ErrorIs(t, err, domain.ErrTagExists)
+	_, err = s.RenameTag(ctx, "user2", "golang", "go")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	url, _ := s.GetURL(ctx, "short001")
+	require.Equal(t, []string{"golang", "docs"}, url.Tags)
+
+	require.NoError(t, s.DeleteTag(ctx, "user1", "docs"))
+	require.ErrorIs(t, s.DeleteTag(ctx, "user1", "docs"), domain.ErrNotFound)
+	url, _ = s.GetURL(ctx, "short001")
+	require.Equal(t, []string{"golang"}, url.Tags)
+
+	url, err = s.SetURLTags(ctx, "user1", "short002", []string{"news"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"news"}, url.Tags)
+	_, err = s.SetURLTags(ctx, "user1", "short003", []string{"news"})
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	url, err = s.SetURLFolder(ctx, "user1", "short001", "Работа")
+	require.NoError(t, err)
+	require.Equal(t, "Работа", url.Folder)
+	folders, err := s.ListFolders(ctx, "user1")
+	require.NoError(t, err)
+	require.Len(t, folders, 1)
+	require.Equal(t, 1, folders[0].Links)
+
+	page, err := s.ListURLs(ctx, "user1", domain.ListQuery{Limit: 10, Tags: []string{"golang"}, Folder: "Работа"})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "short001", page[0].Short)
+
+	require.NoError(t, s.DeleteFolder(ctx, "user1", "Работа"))
+	url, _ = s.GetURL(ctx, "short001")
+	require.Empty(t, url.Folder)
+
+	restored := NewShardedStorage(7)
+	restored.restore(s.export())
+	require.Equal(t, s.export(), restored.export())
+}
+
+// TestShardedStorage_UseURL имеет смысл запускать с -race
+func TestShardedStorage_UseURL(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "once", Long: "http://a.ru", User: "user1", MaxClicks: 1}))
+	require.NoError(t, s.SetURL(ctx, domain.URL{Short: "free", Long: "http://b.ru", User: "user1"}))
+
+	const workers = 32
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	used, exhausted := 0, 0
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.UseURL(ctx, "once")
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				used++
+			} else if assert.ErrorIs(t, err, domain.ErrClicksExhausted) {
+				exhausted++
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, used)
+	require.Equal(t, workers-1, exhausted)
+	url, _ := s.GetURL(ctx, "once")
+	require.True(t, url.Exhausted())
+
+	url, err := s.UseURL(ctx, "free")
+	require.NoError(t, err)
+	require.Zero(t, url.Uses)
+	_, err = s.UseURL(ctx, "missing")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	page, err := s.ListURLs(ctx, "user1", domain.ListQuery{Limit: 10, Status: domain.StatusActive})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "free", page[0].Short)
+}
+
+// TestShardedStorage_SetURLDeleted имеет смысл запускать с -race
+func TestShardedStorage_SetURLDeleted(t *testing.T) {
+	ctx := context.Background()
+	s := NewShardedStorage(4)
+	setters := map[string]func(short string) (domain.URL, error){
+		"preview": func(short string) (domain.URL, error) {
+			return s.SetURLPreview(ctx, "user1", short, true)
+		},
+		"password": func(short string) (domain.URL, error) {
+			return s.SetURLPassword(ctx, "user1", short, "hash")
+		},
+		"rules": func(short string) (domain.URL, error) {
+			return s.SetURLRules(ctx, "user1", short, []domain.RedirectRule{{Platform: "ios", Long: "http://ios.ru"}})
+		},
+		"tags": func(short string) (domain.URL, error) {
+			return s.SetURLTags(ctx, "user1", short, []string{"work"})
+		},
+		"folder": func(short string) (domain.URL, error) {
+			return s.SetURLFolder(ctx, "user1", short, "inbox")
+		},
+		"variants": func(short string) (domain.URL, error) {
+			return s.SetURLVariants(ctx, "user1", short, []domain.Variant{{Name: "a", Long: "http://a.ru", Weight: 1}})
+		},
+	}
+	for name, set := range setters {
+		for i := 0; i < 100; i++ {
+			short := fmt.Sprintf("%s%d", name, i)
+			require.NoError(t, s.SetURL(ctx, domain.URL{Short: short, Long: "http://a.ru", User: "user1"}))
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				s.DeleteURLs(ctx, "user1", []string{short})
+			}()
+			go func() {
+				defer wg.Done()
+				// изменение либо успевает до удаления, либо не проходит
+				url, err := set(short)
+				if err != nil {
+					assert.ErrorIs(t, err, domain.ErrNotFound, name)
+					return
+				}
+				assert.Equal(t, short, url.Short, name)
+				assert.False(t, url.Deleted, name)
+			}()
+			wg.Wait()
+		}
+		_, err := set(fmt.Sprintf("%s%d", name, 0))
+		require.ErrorIs(t, err, domain.ErrNotFound, name)
+		_, err = set("missing")
+		require.ErrorIs(t, err, domain.ErrNotFound, name)
+	}
+}
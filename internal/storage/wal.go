@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Spear5030/yapshrtnr/internal/domain"
+)
+
+// Формат журнала файлового хранилища:
+// заголовок walMagic + версия (uint16) + поколение (uint64), далее записи вида
+// [длина payload uint32][crc32 payload uint32][payload в gob].
+// Поколение увеличивается при каждом сжатии журнала в снимок. В версии 1 поколения не было.
+const (
+	walMagic      = "YPSH"
+	walVersion    = 2
+	walHeaderSize = len(walMagic) + 2 + 8
+	walFrameSize  = 8
+	walMaxRecord  = 64 << 20 // защита от мусорной длины в поврежденной записи
+)
+
+type walOp uint8
+
+const (
+	opSetURLs walOp = iota + 1
+	opDeleteURLs
+	opRemoveURLs // окончательное удаление ссылок, например с истекшим сроком
+	opAddClicks
+	opUpdateURLs
+	opRestoreURLs
+	opCreateTag
+	opRenameTag
+	opDeleteTag
+	opSetURLTags
+	opSetURLFolder
+	opDeleteFolder
+	opSetURLPreview
+	opSetURLPassword
+	opUseURLs
+	opSetURLRules
+	opSetURLVariants
+)
+
+// walRecord запись журнала. Для opSetURLs и opRemoveURLs заполняется URLs, для opDeleteURLs - User, Shorts, Time и JobID,
+// для opRestoreURLs - User, Shorts и Time, для opAddClicks - Clicks, для opUpdateURLs - Revisions.
+// Для операций с метками и папками - User и Name, для opCreateTag еще Time, для opRenameTag - NewName,
+// для opSetURLTags - Shorts, Tags и Time, для opSetURLFolder - Shorts, Name (пустое - вне папок) и Time,
+// для opSetURLPreview - Shorts, Preview и Time, для opSetURLPassword - Shorts, Password и Time,
+// для opUseURLs - Shorts, для opSetURLRules - Shorts, Rules и Time, для opSetURLVariants - Shorts, Variants и Time
+type walRecord struct {
+	Op        walOp
+	URLs      []domain.URL
+	User      string
+	Shorts    []string
+	Clicks    []domain.Click
+	Revisions []domain.Revision
+	Time      time.Time // момент изменения. В записях до его появления - нулевое время
+	JobID     string    // задача удаления. В записях до ее появления пусто, задача не сохраняется
+	Name      string    // метка или папка
+	NewName   string
+	Tags      []string
+	Preview   bool
+	Password  string // bcrypt-хэш, пустой - пароль снят
+	Rules     []domain.RedirectRule
+	Variants  []domain.Variant
+}
+
+var (
+	errWALHeader     = errors.New("storage: unknown file storage format")
+	errWALTorn       = errors.New("storage: torn record")
+	errWALCorrupt    = errors.New("storage: corrupted record in file storage log")
+	errWALGeneration = errors.New("storage: file storage log is newer than snapshot")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+func walHeader(generation uint64) []byte {
+	header := make([]byte, walHeaderSize)
+	copy(header, walMagic)
+	binary.BigEndian.PutUint16(header[len(walMagic):], walVersion)
+	binary.BigEndian.PutUint64(header[len(walMagic)+2:], generation)
+	return header
+}
+
+// readWALHeader возвращает поколение журнала и длину заголовка
+func readWALHeader(r io.Reader) (uint64, int64, error) {
+	header := make([]byte, walHeaderSize)
+	if _, err := io.ReadFull(r, header[:len(walMagic)+2]); err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", errWALHeader, err)
+	}
+	if string(header[:len(walMagic)]) != walMagic {
+		return 0, 0, errWALHeader
+	}
+	switch v := binary.BigEndian.Uint16(header[len(walMagic):]); v {
+	case 1:
+		return 0, int64(len(walMagic) + 2), nil
+	case walVersion:
+		if _, err := io.ReadFull(r, header[len(walMagic)+2:]); err != nil {
+			return 0, 0, fmt.Errorf("%w: %v", errWALHeader, err)
+		}
+		return binary.BigEndian.Uint64(header[len(walMagic)+2:]), int64(walHeaderSize), nil
+	default:
+		return 0, 0, fmt.Errorf("%w: version %d", errWALHeader, v)
+	}
+}
+
+// encodeWALRecord возвращает запись вместе с длиной и контрольной суммой
+func encodeWALRecord(rec walRecord) ([]byte, error) {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(rec); err != nil {
+		return nil, err
+	}
+	b := make([]byte, walFrameSize, walFrameSize+payload.Len())
+	binary.BigEndian.PutUint32(b[0:4], uint32(payload.Len()))
+	binary.BigEndian.PutUint32(b[4:8], crc32.Checksum(payload.Bytes(), crcTable))
+	return append(b, payload.Bytes()...), nil
+}
+
+// readWALRecord читает одну запись и возвращает ее длину. io.EOF - журнал закончился ровно на границе записи,
+// errWALTorn - журнал закончился внутри записи, errWALCorrupt - запись прочитана целиком, но повреждена.
+// Для поврежденной записи длина берется из заголовка записи
+func readWALRecord(r io.Reader) (walRecord, int64, error) {
+	var rec walRecord
+	frame := make([]byte, walFrameSize)
+	n, err := io.ReadFull(r, frame)
+	if err == io.EOF {
+		return rec, 0, io.EOF
+	}
+	if err != nil {
+		return rec, int64(n), errWALTorn
+	}
+	size := binary.BigEndian.Uint32(frame[0:4])
+	if size > walMaxRecord {
+		return rec, walFrameSize + int64(size), errWALCorrupt
+	}
+	payload := make([]byte, size)
+	n, err = io.ReadFull(r, payload)
+	if err != nil {
+		return rec, walFrameSize + int64(n), errWALTorn
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(frame[4:8]) {
+		return rec, walFrameSize + int64(size), errWALCorrupt
+	}
+	if err = gob.NewDecoder(bytes.NewReader(payload)).Decode(&rec); err != nil {
+		return rec, walFrameSize + int64(size), errWALCorrupt
+	}
+	return rec, walFrameSize + int64(size), nil
+}
+
+// replayWAL проигрывает журнал через apply и возвращает смещение конца последней целой записи.
+// Пустой журнал и журнал поколения старше снимка (уже вошедший в снимок) начинаются заново
+// с заголовком поколения снимка. Недописанная или поврежденная последняя запись отбрасывается с записью в лог.
+// Повреждение до последней записи - ошибка errWALCorrupt, журнал не меняется, чтобы после него
+// не потерялись целые записи: восстанавливать такой журнал должен оператор
+func replayWAL(file *os.File, generation uint64, apply func(rec walRecord)) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size() == 0 {
+		return resetWAL(file, generation)
+	}
+	rd := bufio.NewReader(file)
+	logGeneration, offset, err := readWALHeader(rd)
+	if err != nil {
+		return 0, err
+	}
+	if logGeneration < generation {
+		log.Printf("file storage %s: log generation %d already in snapshot %d", file.Name(), logGeneration, generation)
+		return resetWAL(file, generation)
+	}
+	if logGeneration > generation {
+		return 0, fmt.Errorf("%w: log %d, snapshot %d", errWALGeneration, logGeneration, generation)
+	}
+	for {
+		rec, n, err := readWALRecord(rd)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, errWALCorrupt) && offset+n < info.Size() {
+			return 0, fmt.Errorf("%w %s at offset %d", errWALCorrupt, file.Name(), offset)
+		}
+		if err != nil {
+			log.Printf("file storage %s: skip torn record at offset %d, %d bytes dropped", file.Name(), offset, info.Size()-offset)
+			if err = file.Truncate(offset); err != nil {
+				return 0, err
+			}
+			break
+		}
+		apply(rec)
+		offset += n
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+// resetWAL очищает журнал и записывает заголовок заданного поколения
+func resetWAL(file *os.File, generation uint64) (int64, error) {
+	if err := file.Truncate(0); err != nil {
+		return 0, err
+	}
+	if _, err := file.WriteAt(walHeader(generation), 0); err != nil {
+		return 0, err
+	}
+	if err := file.Sync(); err != nil {
+		return 0, err
+	}
+	return file.Seek(int64(walHeaderSize), io.SeekStart)
+}